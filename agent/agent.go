@@ -22,6 +22,11 @@ type Dispatcher interface {
 	Dispatch(context.Context, ...*Message) error
 }
 
+// AuditRecorder records entries into the audit log.
+type AuditRecorder interface {
+	Record(context.Context, *AuditEntry) error
+}
+
 // ConnectableDispatcher ...
 type ConnectableDispatcher interface {
 	Dispatcher
@@ -43,7 +48,9 @@ type Client interface {
 	Info(ctx context.Context) (*StatusResponse, error)
 	Watch(ctx context.Context, out chan<- *Message) error
 	Dispatch(ctx context.Context, messages ...*Message) error
+	Record(ctx context.Context, e *AuditEntry) error
 	Logs(context.Context, *Peer, []byte) io.ReadCloser
+	SearchLogs(context.Context, *LogSearchRequest, func(*LogSearchMatch) error) error
 }
 
 // DeployClient - facade interface.
@@ -60,6 +67,7 @@ type DeployClient interface {
 type Uploader interface {
 	Upload(io.Reader) (hash.Hash, error)
 	Info() (hash.Hash, string, error)
+	Abort() error
 }
 
 // DetectQuorum detects a peer based on the compare function.
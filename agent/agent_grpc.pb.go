@@ -19,11 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Deployments_Upload_FullMethodName = "/agent.Deployments/Upload"
-	Deployments_Deploy_FullMethodName = "/agent.Deployments/Deploy"
-	Deployments_Cancel_FullMethodName = "/agent.Deployments/Cancel"
-	Deployments_Logs_FullMethodName   = "/agent.Deployments/Logs"
-	Deployments_Watch_FullMethodName  = "/agent.Deployments/Watch"
+	Deployments_Upload_FullMethodName       = "/agent.Deployments/Upload"
+	Deployments_UploadStatus_FullMethodName = "/agent.Deployments/UploadStatus"
+	Deployments_Deploy_FullMethodName       = "/agent.Deployments/Deploy"
+	Deployments_Stage_FullMethodName        = "/agent.Deployments/Stage"
+	Deployments_Cancel_FullMethodName       = "/agent.Deployments/Cancel"
+	Deployments_Logs_FullMethodName         = "/agent.Deployments/Logs"
+	Deployments_Download_FullMethodName     = "/agent.Deployments/Download"
+	Deployments_Watch_FullMethodName        = "/agent.Deployments/Watch"
 )
 
 // DeploymentsClient is the client API for Deployments service.
@@ -33,9 +36,12 @@ const (
 // Deployments service public facing endpoints for deployments.
 type DeploymentsClient interface {
 	Upload(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadChunk, UploadResponse], error)
+	UploadStatus(ctx context.Context, in *UploadStatusRequest, opts ...grpc.CallOption) (*UploadStatusResponse, error)
 	Deploy(ctx context.Context, in *DeployCommandRequest, opts ...grpc.CallOption) (*DeployCommandResult, error)
+	Stage(ctx context.Context, in *StageCommandRequest, opts ...grpc.CallOption) (*StageCommandResult, error)
 	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error)
 	Logs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogResponse], error)
+	Download(ctx context.Context, in *ArchiveDownloadRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArchiveDownloadResponse], error)
 	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Message], error)
 }
 
@@ -60,6 +66,16 @@ func (c *deploymentsClient) Upload(ctx context.Context, opts ...grpc.CallOption)
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Deployments_UploadClient = grpc.ClientStreamingClient[UploadChunk, UploadResponse]
 
+func (c *deploymentsClient) UploadStatus(ctx context.Context, in *UploadStatusRequest, opts ...grpc.CallOption) (*UploadStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadStatusResponse)
+	err := c.cc.Invoke(ctx, Deployments_UploadStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *deploymentsClient) Deploy(ctx context.Context, in *DeployCommandRequest, opts ...grpc.CallOption) (*DeployCommandResult, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeployCommandResult)
@@ -70,6 +86,16 @@ func (c *deploymentsClient) Deploy(ctx context.Context, in *DeployCommandRequest
 	return out, nil
 }
 
+func (c *deploymentsClient) Stage(ctx context.Context, in *StageCommandRequest, opts ...grpc.CallOption) (*StageCommandResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StageCommandResult)
+	err := c.cc.Invoke(ctx, Deployments_Stage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *deploymentsClient) Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CancelResponse)
@@ -99,9 +125,28 @@ func (c *deploymentsClient) Logs(ctx context.Context, in *LogRequest, opts ...gr
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Deployments_LogsClient = grpc.ServerStreamingClient[LogResponse]
 
+func (c *deploymentsClient) Download(ctx context.Context, in *ArchiveDownloadRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ArchiveDownloadResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Deployments_ServiceDesc.Streams[2], Deployments_Download_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ArchiveDownloadRequest, ArchiveDownloadResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Deployments_DownloadClient = grpc.ServerStreamingClient[ArchiveDownloadResponse]
+
 func (c *deploymentsClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Message], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &Deployments_ServiceDesc.Streams[2], Deployments_Watch_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &Deployments_ServiceDesc.Streams[3], Deployments_Watch_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -125,9 +170,12 @@ type Deployments_WatchClient = grpc.ServerStreamingClient[Message]
 // Deployments service public facing endpoints for deployments.
 type DeploymentsServer interface {
 	Upload(grpc.ClientStreamingServer[UploadChunk, UploadResponse]) error
+	UploadStatus(context.Context, *UploadStatusRequest) (*UploadStatusResponse, error)
 	Deploy(context.Context, *DeployCommandRequest) (*DeployCommandResult, error)
+	Stage(context.Context, *StageCommandRequest) (*StageCommandResult, error)
 	Cancel(context.Context, *CancelRequest) (*CancelResponse, error)
 	Logs(*LogRequest, grpc.ServerStreamingServer[LogResponse]) error
+	Download(*ArchiveDownloadRequest, grpc.ServerStreamingServer[ArchiveDownloadResponse]) error
 	Watch(*WatchRequest, grpc.ServerStreamingServer[Message]) error
 	mustEmbedUnimplementedDeploymentsServer()
 }
@@ -142,15 +190,24 @@ type UnimplementedDeploymentsServer struct{}
 func (UnimplementedDeploymentsServer) Upload(grpc.ClientStreamingServer[UploadChunk, UploadResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
 }
+func (UnimplementedDeploymentsServer) UploadStatus(context.Context, *UploadStatusRequest) (*UploadStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadStatus not implemented")
+}
 func (UnimplementedDeploymentsServer) Deploy(context.Context, *DeployCommandRequest) (*DeployCommandResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Deploy not implemented")
 }
+func (UnimplementedDeploymentsServer) Stage(context.Context, *StageCommandRequest) (*StageCommandResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stage not implemented")
+}
 func (UnimplementedDeploymentsServer) Cancel(context.Context, *CancelRequest) (*CancelResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Cancel not implemented")
 }
 func (UnimplementedDeploymentsServer) Logs(*LogRequest, grpc.ServerStreamingServer[LogResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method Logs not implemented")
 }
+func (UnimplementedDeploymentsServer) Download(*ArchiveDownloadRequest, grpc.ServerStreamingServer[ArchiveDownloadResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Download not implemented")
+}
 func (UnimplementedDeploymentsServer) Watch(*WatchRequest, grpc.ServerStreamingServer[Message]) error {
 	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
 }
@@ -182,6 +239,24 @@ func _Deployments_Upload_Handler(srv interface{}, stream grpc.ServerStream) erro
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Deployments_UploadServer = grpc.ClientStreamingServer[UploadChunk, UploadResponse]
 
+func _Deployments_UploadStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeploymentsServer).UploadStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Deployments_UploadStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeploymentsServer).UploadStatus(ctx, req.(*UploadStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Deployments_Deploy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeployCommandRequest)
 	if err := dec(in); err != nil {
@@ -200,6 +275,24 @@ func _Deployments_Deploy_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Deployments_Stage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StageCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeploymentsServer).Stage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Deployments_Stage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeploymentsServer).Stage(ctx, req.(*StageCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Deployments_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CancelRequest)
 	if err := dec(in); err != nil {
@@ -229,6 +322,17 @@ func _Deployments_Logs_Handler(srv interface{}, stream grpc.ServerStream) error
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Deployments_LogsServer = grpc.ServerStreamingServer[LogResponse]
 
+func _Deployments_Download_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ArchiveDownloadRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DeploymentsServer).Download(m, &grpc.GenericServerStream[ArchiveDownloadRequest, ArchiveDownloadResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Deployments_DownloadServer = grpc.ServerStreamingServer[ArchiveDownloadResponse]
+
 func _Deployments_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(WatchRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -247,10 +351,18 @@ var Deployments_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "agent.Deployments",
 	HandlerType: (*DeploymentsServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UploadStatus",
+			Handler:    _Deployments_UploadStatus_Handler,
+		},
 		{
 			MethodName: "Deploy",
 			Handler:    _Deployments_Deploy_Handler,
 		},
+		{
+			MethodName: "Stage",
+			Handler:    _Deployments_Stage_Handler,
+		},
 		{
 			MethodName: "Cancel",
 			Handler:    _Deployments_Cancel_Handler,
@@ -267,6 +379,11 @@ var Deployments_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Deployments_Logs_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Download",
+			Handler:       _Deployments_Download_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "Watch",
 			Handler:       _Deployments_Watch_Handler,
@@ -277,13 +394,18 @@ var Deployments_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	Quorum_Upload_FullMethodName   = "/agent.Quorum/Upload"
-	Quorum_Watch_FullMethodName    = "/agent.Quorum/Watch"
-	Quorum_Dispatch_FullMethodName = "/agent.Quorum/Dispatch"
-	Quorum_Deploy_FullMethodName   = "/agent.Quorum/Deploy"
-	Quorum_Info_FullMethodName     = "/agent.Quorum/Info"
-	Quorum_Cancel_FullMethodName   = "/agent.Quorum/Cancel"
-	Quorum_History_FullMethodName  = "/agent.Quorum/History"
+	Quorum_Upload_FullMethodName       = "/agent.Quorum/Upload"
+	Quorum_UploadStatus_FullMethodName = "/agent.Quorum/UploadStatus"
+	Quorum_Watch_FullMethodName        = "/agent.Quorum/Watch"
+	Quorum_Dispatch_FullMethodName     = "/agent.Quorum/Dispatch"
+	Quorum_Deploy_FullMethodName       = "/agent.Quorum/Deploy"
+	Quorum_Stage_FullMethodName        = "/agent.Quorum/Stage"
+	Quorum_Info_FullMethodName         = "/agent.Quorum/Info"
+	Quorum_Cancel_FullMethodName       = "/agent.Quorum/Cancel"
+	Quorum_History_FullMethodName      = "/agent.Quorum/History"
+	Quorum_Audit_FullMethodName        = "/agent.Quorum/Audit"
+	Quorum_Record_FullMethodName       = "/agent.Quorum/Record"
+	Quorum_Authority_FullMethodName    = "/agent.Quorum/Authority"
 )
 
 // QuorumClient is the client API for Quorum service.
@@ -291,12 +413,17 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type QuorumClient interface {
 	Upload(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadChunk, UploadResponse], error)
+	UploadStatus(ctx context.Context, in *UploadStatusRequest, opts ...grpc.CallOption) (*UploadStatusResponse, error)
 	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Message], error)
 	Dispatch(ctx context.Context, in *DispatchRequest, opts ...grpc.CallOption) (*DispatchResponse, error)
 	Deploy(ctx context.Context, in *DeployCommandRequest, opts ...grpc.CallOption) (*DeployCommandResult, error)
+	Stage(ctx context.Context, in *StageCommandRequest, opts ...grpc.CallOption) (*StageCommandResult, error)
 	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error)
 	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error)
 	History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error)
+	Audit(ctx context.Context, in *AuditRequest, opts ...grpc.CallOption) (*AuditResponse, error)
+	Record(ctx context.Context, in *AuditRecordRequest, opts ...grpc.CallOption) (*AuditRecordResponse, error)
+	Authority(ctx context.Context, in *AuthorityRequest, opts ...grpc.CallOption) (*AuthorityResponse, error)
 }
 
 type quorumClient struct {
@@ -320,6 +447,16 @@ func (c *quorumClient) Upload(ctx context.Context, opts ...grpc.CallOption) (grp
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Quorum_UploadClient = grpc.ClientStreamingClient[UploadChunk, UploadResponse]
 
+func (c *quorumClient) UploadStatus(ctx context.Context, in *UploadStatusRequest, opts ...grpc.CallOption) (*UploadStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadStatusResponse)
+	err := c.cc.Invoke(ctx, Quorum_UploadStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *quorumClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Message], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &Quorum_ServiceDesc.Streams[1], Quorum_Watch_FullMethodName, cOpts...)
@@ -359,6 +496,16 @@ func (c *quorumClient) Deploy(ctx context.Context, in *DeployCommandRequest, opt
 	return out, nil
 }
 
+func (c *quorumClient) Stage(ctx context.Context, in *StageCommandRequest, opts ...grpc.CallOption) (*StageCommandResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StageCommandResult)
+	err := c.cc.Invoke(ctx, Quorum_Stage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *quorumClient) Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(InfoResponse)
@@ -389,17 +536,52 @@ func (c *quorumClient) History(ctx context.Context, in *HistoryRequest, opts ...
 	return out, nil
 }
 
+func (c *quorumClient) Audit(ctx context.Context, in *AuditRequest, opts ...grpc.CallOption) (*AuditResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuditResponse)
+	err := c.cc.Invoke(ctx, Quorum_Audit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quorumClient) Record(ctx context.Context, in *AuditRecordRequest, opts ...grpc.CallOption) (*AuditRecordResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuditRecordResponse)
+	err := c.cc.Invoke(ctx, Quorum_Record_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *quorumClient) Authority(ctx context.Context, in *AuthorityRequest, opts ...grpc.CallOption) (*AuthorityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorityResponse)
+	err := c.cc.Invoke(ctx, Quorum_Authority_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QuorumServer is the server API for Quorum service.
 // All implementations must embed UnimplementedQuorumServer
 // for forward compatibility.
 type QuorumServer interface {
 	Upload(grpc.ClientStreamingServer[UploadChunk, UploadResponse]) error
+	UploadStatus(context.Context, *UploadStatusRequest) (*UploadStatusResponse, error)
 	Watch(*WatchRequest, grpc.ServerStreamingServer[Message]) error
 	Dispatch(context.Context, *DispatchRequest) (*DispatchResponse, error)
 	Deploy(context.Context, *DeployCommandRequest) (*DeployCommandResult, error)
+	Stage(context.Context, *StageCommandRequest) (*StageCommandResult, error)
 	Info(context.Context, *InfoRequest) (*InfoResponse, error)
 	Cancel(context.Context, *CancelRequest) (*CancelResponse, error)
 	History(context.Context, *HistoryRequest) (*HistoryResponse, error)
+	Audit(context.Context, *AuditRequest) (*AuditResponse, error)
+	Record(context.Context, *AuditRecordRequest) (*AuditRecordResponse, error)
+	Authority(context.Context, *AuthorityRequest) (*AuthorityResponse, error)
 	mustEmbedUnimplementedQuorumServer()
 }
 
@@ -413,6 +595,9 @@ type UnimplementedQuorumServer struct{}
 func (UnimplementedQuorumServer) Upload(grpc.ClientStreamingServer[UploadChunk, UploadResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
 }
+func (UnimplementedQuorumServer) UploadStatus(context.Context, *UploadStatusRequest) (*UploadStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadStatus not implemented")
+}
 func (UnimplementedQuorumServer) Watch(*WatchRequest, grpc.ServerStreamingServer[Message]) error {
 	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
 }
@@ -422,6 +607,9 @@ func (UnimplementedQuorumServer) Dispatch(context.Context, *DispatchRequest) (*D
 func (UnimplementedQuorumServer) Deploy(context.Context, *DeployCommandRequest) (*DeployCommandResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Deploy not implemented")
 }
+func (UnimplementedQuorumServer) Stage(context.Context, *StageCommandRequest) (*StageCommandResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stage not implemented")
+}
 func (UnimplementedQuorumServer) Info(context.Context, *InfoRequest) (*InfoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Info not implemented")
 }
@@ -431,6 +619,15 @@ func (UnimplementedQuorumServer) Cancel(context.Context, *CancelRequest) (*Cance
 func (UnimplementedQuorumServer) History(context.Context, *HistoryRequest) (*HistoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method History not implemented")
 }
+func (UnimplementedQuorumServer) Audit(context.Context, *AuditRequest) (*AuditResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Audit not implemented")
+}
+func (UnimplementedQuorumServer) Record(context.Context, *AuditRecordRequest) (*AuditRecordResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Record not implemented")
+}
+func (UnimplementedQuorumServer) Authority(context.Context, *AuthorityRequest) (*AuthorityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authority not implemented")
+}
 func (UnimplementedQuorumServer) mustEmbedUnimplementedQuorumServer() {}
 func (UnimplementedQuorumServer) testEmbeddedByValue()                {}
 
@@ -459,6 +656,24 @@ func _Quorum_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Quorum_UploadServer = grpc.ClientStreamingServer[UploadChunk, UploadResponse]
 
+func _Quorum_UploadStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuorumServer).UploadStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Quorum_UploadStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuorumServer).UploadStatus(ctx, req.(*UploadStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Quorum_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(WatchRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -506,6 +721,24 @@ func _Quorum_Deploy_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Quorum_Stage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StageCommandRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuorumServer).Stage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Quorum_Stage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuorumServer).Stage(ctx, req.(*StageCommandRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Quorum_Info_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(InfoRequest)
 	if err := dec(in); err != nil {
@@ -560,6 +793,60 @@ func _Quorum_History_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Quorum_Audit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuorumServer).Audit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Quorum_Audit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuorumServer).Audit(ctx, req.(*AuditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Quorum_Record_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditRecordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuorumServer).Record(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Quorum_Record_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuorumServer).Record(ctx, req.(*AuditRecordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Quorum_Authority_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuorumServer).Authority(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Quorum_Authority_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuorumServer).Authority(ctx, req.(*AuthorityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Quorum_ServiceDesc is the grpc.ServiceDesc for Quorum service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -567,6 +854,10 @@ var Quorum_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "agent.Quorum",
 	HandlerType: (*QuorumServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UploadStatus",
+			Handler:    _Quorum_UploadStatus_Handler,
+		},
 		{
 			MethodName: "Dispatch",
 			Handler:    _Quorum_Dispatch_Handler,
@@ -575,6 +866,10 @@ var Quorum_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Deploy",
 			Handler:    _Quorum_Deploy_Handler,
 		},
+		{
+			MethodName: "Stage",
+			Handler:    _Quorum_Stage_Handler,
+		},
 		{
 			MethodName: "Info",
 			Handler:    _Quorum_Info_Handler,
@@ -587,6 +882,18 @@ var Quorum_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "History",
 			Handler:    _Quorum_History_Handler,
 		},
+		{
+			MethodName: "Audit",
+			Handler:    _Quorum_Audit_Handler,
+		},
+		{
+			MethodName: "Record",
+			Handler:    _Quorum_Record_Handler,
+		},
+		{
+			MethodName: "Authority",
+			Handler:    _Quorum_Authority_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -604,12 +911,16 @@ var Quorum_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	Agent_Connect_FullMethodName  = "/agent.Agent/Connect"
-	Agent_Info_FullMethodName     = "/agent.Agent/Info"
-	Agent_Deploy_FullMethodName   = "/agent.Agent/Deploy"
-	Agent_Cancel_FullMethodName   = "/agent.Agent/Cancel"
-	Agent_Shutdown_FullMethodName = "/agent.Agent/Shutdown"
-	Agent_Logs_FullMethodName     = "/agent.Agent/Logs"
+	Agent_Connect_FullMethodName    = "/agent.Agent/Connect"
+	Agent_Info_FullMethodName       = "/agent.Agent/Info"
+	Agent_Deploy_FullMethodName     = "/agent.Agent/Deploy"
+	Agent_Cancel_FullMethodName     = "/agent.Agent/Cancel"
+	Agent_Shutdown_FullMethodName   = "/agent.Agent/Shutdown"
+	Agent_Logs_FullMethodName       = "/agent.Agent/Logs"
+	Agent_SearchLogs_FullMethodName = "/agent.Agent/SearchLogs"
+	Agent_TLSStatus_FullMethodName  = "/agent.Agent/TLSStatus"
+	Agent_StorageGC_FullMethodName  = "/agent.Agent/StorageGC"
+	Agent_Stage_FullMethodName      = "/agent.Agent/Stage"
 )
 
 // AgentClient is the client API for Agent service.
@@ -622,6 +933,10 @@ type AgentClient interface {
 	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*CancelResponse, error)
 	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
 	Logs(ctx context.Context, in *LogRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogResponse], error)
+	SearchLogs(ctx context.Context, in *LogSearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogSearchMatch], error)
+	TLSStatus(ctx context.Context, in *TLSStatusRequest, opts ...grpc.CallOption) (*TLSStatusResponse, error)
+	StorageGC(ctx context.Context, in *StorageGCRequest, opts ...grpc.CallOption) (*StorageGCResponse, error)
+	Stage(ctx context.Context, in *StageRequest, opts ...grpc.CallOption) (*StageResponse, error)
 }
 
 type agentClient struct {
@@ -701,6 +1016,55 @@ func (c *agentClient) Logs(ctx context.Context, in *LogRequest, opts ...grpc.Cal
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Agent_LogsClient = grpc.ServerStreamingClient[LogResponse]
 
+func (c *agentClient) SearchLogs(ctx context.Context, in *LogSearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogSearchMatch], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Agent_ServiceDesc.Streams[1], Agent_SearchLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[LogSearchRequest, LogSearchMatch]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Agent_SearchLogsClient = grpc.ServerStreamingClient[LogSearchMatch]
+
+func (c *agentClient) TLSStatus(ctx context.Context, in *TLSStatusRequest, opts ...grpc.CallOption) (*TLSStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TLSStatusResponse)
+	err := c.cc.Invoke(ctx, Agent_TLSStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) StorageGC(ctx context.Context, in *StorageGCRequest, opts ...grpc.CallOption) (*StorageGCResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StorageGCResponse)
+	err := c.cc.Invoke(ctx, Agent_StorageGC_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) Stage(ctx context.Context, in *StageRequest, opts ...grpc.CallOption) (*StageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StageResponse)
+	err := c.cc.Invoke(ctx, Agent_Stage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServer is the server API for Agent service.
 // All implementations must embed UnimplementedAgentServer
 // for forward compatibility.
@@ -711,6 +1075,10 @@ type AgentServer interface {
 	Cancel(context.Context, *CancelRequest) (*CancelResponse, error)
 	Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
 	Logs(*LogRequest, grpc.ServerStreamingServer[LogResponse]) error
+	SearchLogs(*LogSearchRequest, grpc.ServerStreamingServer[LogSearchMatch]) error
+	TLSStatus(context.Context, *TLSStatusRequest) (*TLSStatusResponse, error)
+	StorageGC(context.Context, *StorageGCRequest) (*StorageGCResponse, error)
+	Stage(context.Context, *StageRequest) (*StageResponse, error)
 	mustEmbedUnimplementedAgentServer()
 }
 
@@ -739,6 +1107,18 @@ func (UnimplementedAgentServer) Shutdown(context.Context, *ShutdownRequest) (*Sh
 func (UnimplementedAgentServer) Logs(*LogRequest, grpc.ServerStreamingServer[LogResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method Logs not implemented")
 }
+func (UnimplementedAgentServer) SearchLogs(*LogSearchRequest, grpc.ServerStreamingServer[LogSearchMatch]) error {
+	return status.Errorf(codes.Unimplemented, "method SearchLogs not implemented")
+}
+func (UnimplementedAgentServer) TLSStatus(context.Context, *TLSStatusRequest) (*TLSStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TLSStatus not implemented")
+}
+func (UnimplementedAgentServer) StorageGC(context.Context, *StorageGCRequest) (*StorageGCResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StorageGC not implemented")
+}
+func (UnimplementedAgentServer) Stage(context.Context, *StageRequest) (*StageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stage not implemented")
+}
 func (UnimplementedAgentServer) mustEmbedUnimplementedAgentServer() {}
 func (UnimplementedAgentServer) testEmbeddedByValue()               {}
 
@@ -861,6 +1241,71 @@ func _Agent_Logs_Handler(srv interface{}, stream grpc.ServerStream) error {
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Agent_LogsServer = grpc.ServerStreamingServer[LogResponse]
 
+func _Agent_SearchLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LogSearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServer).SearchLogs(m, &grpc.GenericServerStream[LogSearchRequest, LogSearchMatch]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Agent_SearchLogsServer = grpc.ServerStreamingServer[LogSearchMatch]
+
+func _Agent_TLSStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TLSStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).TLSStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_TLSStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).TLSStatus(ctx, req.(*TLSStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_StorageGC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StorageGCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).StorageGC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_StorageGC_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).StorageGC(ctx, req.(*StorageGCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_Stage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Stage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Agent_Stage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Stage(ctx, req.(*StageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Agent_ServiceDesc is the grpc.ServiceDesc for Agent service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -888,6 +1333,18 @@ var Agent_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Shutdown",
 			Handler:    _Agent_Shutdown_Handler,
 		},
+		{
+			MethodName: "TLSStatus",
+			Handler:    _Agent_TLSStatus_Handler,
+		},
+		{
+			MethodName: "StorageGC",
+			Handler:    _Agent_StorageGC_Handler,
+		},
+		{
+			MethodName: "Stage",
+			Handler:    _Agent_Stage_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -895,6 +1352,11 @@ var Agent_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Agent_Logs_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SearchLogs",
+			Handler:       _Agent_SearchLogs_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "agent.proto",
 }
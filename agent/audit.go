@@ -0,0 +1,162 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"log"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+// maximum length of the argument summary recorded in an audit entry.
+const maxAuditArguments = 512
+
+// metadata key of the fingerprint a proxy forwards a request on behalf of.
+const mdAuditForwarded = "bw.audit.forwarded"
+
+// AuditForwarded records the fingerprint of the requester a proxied request is made
+// on behalf of. the proxy has already recorded the request into the audit log.
+func AuditForwarded(ctx context.Context, fingerprint string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, mdAuditForwarded, fingerprint)
+}
+
+// auditForwarded fingerprint the request was forwarded on behalf of, empty when
+// the request was not forwarded.
+func auditForwarded(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if vals := md.Get(mdAuditForwarded); len(vals) == 1 {
+		return vals[0]
+	}
+
+	return ""
+}
+
+// NewAuditEvent create an audit event message. audit events are hidden from observers
+// and are not retained by the WAL, the quorum's audit log retains them.
+func NewAuditEvent(p *Peer, e *AuditEntry) *Message {
+	return &Message{
+		Id:        uuid.Must(uuid.NewV4()).String(),
+		Type:      Message_AuditEvent,
+		Hidden:    true,
+		Ephemeral: true,
+		Peer:      p,
+		Ts:        e.Ts,
+		Event: &Message_Audit{
+			Audit: e,
+		},
+	}
+}
+
+// AuditDigest computes the digest of the entry, the digest covers every field
+// of the entry except the digest itself.
+func AuditDigest(e *AuditEntry) []byte {
+	dup := proto.Clone(e).(*AuditEntry)
+	dup.Digest = nil
+
+	encoded, err := proto.MarshalOptions{Deterministic: true}.Marshal(dup)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to marshal audit entry"))
+	}
+
+	digest := sha256.Sum256(encoded)
+	return digest[:]
+}
+
+// VerifyAudit ensures the entries have not been tampered with. consecutive entries
+// must be chained together.
+func VerifyAudit(entries ...*AuditEntry) error {
+	for i, e := range entries {
+		if !bytes.Equal(e.Digest, AuditDigest(e)) {
+			return errors.Errorf("audit entry %d digest mismatch", e.Sequence)
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		if prev := entries[i-1]; prev.Sequence+1 == e.Sequence && !bytes.Equal(prev.Digest, e.Previous) {
+			return errors.Errorf("audit entry %d is not chained to entry %d", e.Sequence, prev.Sequence)
+		}
+	}
+
+	return nil
+}
+
+type authenticator interface {
+	// Fingerprint of the authenticated request context, empty when unauthenticated.
+	Fingerprint(ctx context.Context) string
+	// Agent checks if the request was made by an agent of the cluster.
+	Agent(ctx context.Context) error
+}
+
+// AuditInterceptor records the given rpcs into the audit log by the authenticated fingerprint.
+// requests agents of the cluster forward on behalf of a requester are not recorded, the
+// proxying agent has already recorded them.
+func AuditInterceptor(a authenticator, r AuditRecorder, rpcs ...string) grpc.UnaryServerInterceptor {
+	audited := make(map[string]bool, len(rpcs))
+	for _, rpc := range rpcs {
+		audited[rpc] = true
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		if !audited[info.FullMethod] || (auditForwarded(ctx) != "" && a.Agent(ctx) == nil) {
+			return handler(ctx, req)
+		}
+
+		entry := &AuditEntry{
+			Ts:          time.Now().Unix(),
+			Fingerprint: a.Fingerprint(ctx),
+			Rpc:         info.FullMethod,
+			Arguments:   auditArguments(req),
+		}
+
+		resp, err = handler(ctx, req)
+
+		if err != nil {
+			entry.Result = err.Error()
+		}
+
+		go func() {
+			dctx, done := context.WithTimeout(context.Background(), 10*time.Second)
+			defer done()
+
+			if cause := r.Record(dctx, entry); cause != nil {
+				log.Println(errors.Wrapf(cause, "unable to record audit entry %s %s", entry.Fingerprint, entry.Rpc))
+			}
+		}()
+
+		return resp, err
+	}
+}
+
+// auditRedactor implemented by requests that carry credentials, the redacted
+// request is recorded in their place.
+type auditRedactor interface {
+	AuditRedact() proto.Message
+}
+
+// summarize the arguments of a request.
+func auditArguments(req interface{}) string {
+	if r, ok := req.(auditRedactor); ok {
+		req = r.AuditRedact()
+	}
+
+	m, ok := req.(proto.Message)
+	if !ok {
+		return ""
+	}
+
+	summary := prototext.MarshalOptions{Multiline: false}.Format(m)
+	if len(summary) > maxAuditArguments {
+		summary = summary[:maxAuditArguments] + "..."
+	}
+
+	return summary
+}
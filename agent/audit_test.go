@@ -0,0 +1,155 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/james-lawrence/bw/agent"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type staticfingerprint string
+
+func (t staticfingerprint) Fingerprint(context.Context) string {
+	return string(t)
+}
+
+func (t staticfingerprint) Agent(context.Context) error {
+	if t == "agent" {
+		return nil
+	}
+
+	return errors.New("not an agent")
+}
+
+// redacted request that carries credentials.
+type redacted struct {
+	*CancelRequest
+}
+
+func (t redacted) AuditRedact() proto.Message {
+	return &CancelRequest{Initiator: "redacted"}
+}
+
+type chanrecorder chan *AuditEntry
+
+func (t chanrecorder) Record(ctx context.Context, e *AuditEntry) error {
+	t <- e
+	return nil
+}
+
+var _ = Describe("VerifyAudit", func() {
+	chain := func() []*AuditEntry {
+		e1 := &AuditEntry{Sequence: 1, Ts: 1, Fingerprint: "fp1", Rpc: Quorum_Deploy_FullMethodName}
+		e1.Digest = AuditDigest(e1)
+		e2 := &AuditEntry{Sequence: 2, Ts: 2, Fingerprint: "fp2", Rpc: Quorum_Cancel_FullMethodName, Previous: e1.Digest}
+		e2.Digest = AuditDigest(e2)
+		return []*AuditEntry{e1, e2}
+	}
+
+	It("should succeed for a valid chain", func() {
+		Expect(VerifyAudit(chain()...)).To(Succeed())
+	})
+
+	It("should detect modified entries", func() {
+		entries := chain()
+		entries[0].Fingerprint = "fp3"
+		Expect(VerifyAudit(entries...)).To(MatchError("audit entry 1 digest mismatch"))
+	})
+
+	It("should detect broken chains", func() {
+		entries := chain()
+		entries[1].Previous = []byte("invalid")
+		entries[1].Digest = AuditDigest(entries[1])
+		Expect(VerifyAudit(entries...)).To(MatchError("audit entry 2 is not chained to entry 1"))
+	})
+})
+
+var _ = Describe("AuditInterceptor", func() {
+	handler := func(err error) grpc.UnaryHandler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &CancelResponse{}, err
+		}
+	}
+
+	It("should record audited rpcs", func() {
+		d := make(chanrecorder, 1)
+		i := AuditInterceptor(staticfingerprint("fp1"), d, Quorum_Cancel_FullMethodName)
+
+		_, err := i(context.Background(), &CancelRequest{Initiator: "bob"}, &grpc.UnaryServerInfo{FullMethod: Quorum_Cancel_FullMethodName}, handler(errors.New("boom")))
+		Expect(err).To(MatchError("boom"))
+
+		var e *AuditEntry
+		Eventually(d).WithTimeout(time.Second).Should(Receive(&e))
+		Expect(e.Fingerprint).To(Equal("fp1"))
+		Expect(e.Rpc).To(Equal(Quorum_Cancel_FullMethodName))
+		Expect(e.Arguments).To(ContainSubstring("bob"))
+		Expect(e.Result).To(Equal("boom"))
+	})
+
+	forwarded := func(fingerprint string) context.Context {
+		md, _ := metadata.FromOutgoingContext(AuditForwarded(context.Background(), fingerprint))
+		return metadata.NewIncomingContext(context.Background(), md)
+	}
+
+	It("should not record rpcs forwarded by agents", func() {
+		d := make(chanrecorder, 1)
+		i := AuditInterceptor(staticfingerprint("agent"), d, Quorum_Cancel_FullMethodName)
+
+		_, err := i(forwarded("fp1"), &CancelRequest{Initiator: "bob"}, &grpc.UnaryServerInfo{FullMethod: Quorum_Cancel_FullMethodName}, handler(nil))
+		Expect(err).To(Succeed())
+		Consistently(d).WithTimeout(100 * time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("should record rpcs of agents that are not forwarded", func() {
+		d := make(chanrecorder, 1)
+		i := AuditInterceptor(staticfingerprint("agent"), d, Quorum_Cancel_FullMethodName)
+
+		_, err := i(context.Background(), &CancelRequest{Initiator: "bob"}, &grpc.UnaryServerInfo{FullMethod: Quorum_Cancel_FullMethodName}, handler(nil))
+		Expect(err).To(Succeed())
+
+		var e *AuditEntry
+		Eventually(d).WithTimeout(time.Second).Should(Receive(&e))
+		Expect(e.Fingerprint).To(Equal("agent"))
+	})
+
+	It("should record forwarded rpcs of requesters that are not agents", func() {
+		d := make(chanrecorder, 1)
+		i := AuditInterceptor(staticfingerprint("fp2"), d, Quorum_Cancel_FullMethodName)
+
+		_, err := i(forwarded("fp1"), &CancelRequest{Initiator: "bob"}, &grpc.UnaryServerInfo{FullMethod: Quorum_Cancel_FullMethodName}, handler(nil))
+		Expect(err).To(Succeed())
+
+		var e *AuditEntry
+		Eventually(d).WithTimeout(time.Second).Should(Receive(&e))
+		Expect(e.Fingerprint).To(Equal("fp2"))
+	})
+
+	It("should redact the credentials of requests", func() {
+		d := make(chanrecorder, 1)
+		i := AuditInterceptor(staticfingerprint("fp1"), d, Quorum_Cancel_FullMethodName)
+
+		_, err := i(context.Background(), redacted{CancelRequest: &CancelRequest{Initiator: "secret"}}, &grpc.UnaryServerInfo{FullMethod: Quorum_Cancel_FullMethodName}, handler(nil))
+		Expect(err).To(Succeed())
+
+		var e *AuditEntry
+		Eventually(d).WithTimeout(time.Second).Should(Receive(&e))
+		Expect(e.Arguments).ToNot(ContainSubstring("secret"))
+		Expect(e.Arguments).To(ContainSubstring("redacted"))
+	})
+
+	It("should ignore other rpcs", func() {
+		d := make(chanrecorder, 1)
+		i := AuditInterceptor(staticfingerprint("fp1"), d, Quorum_Cancel_FullMethodName)
+
+		_, err := i(context.Background(), &HistoryRequest{}, &grpc.UnaryServerInfo{FullMethod: Quorum_History_FullMethodName}, handler(nil))
+		Expect(err).To(Succeed())
+		Consistently(d).WithTimeout(100 * time.Millisecond).ShouldNot(Receive())
+	})
+})
@@ -7,8 +7,21 @@ import (
 	status "google.golang.org/grpc/status"
 )
 
+// scopedPeer the peer information permissions are scoped against.
+type scopedPeer = interface {
+	GetName() string
+	GetIp() string
+	GetLabels() map[string]string
+}
+
 type auth interface {
 	Deploy(ctx context.Context) error
+	// Restart checks if the request is allowed to restart the agent.
+	Restart(ctx context.Context) error
+	// DeployTo checks if the request is allowed to deploy to every one of the peers.
+	DeployTo(ctx context.Context, peers ...scopedPeer) error
+	// Agent checks if the request was made by an agent of the cluster.
+	Agent(ctx context.Context) error
 }
 
 type noauth struct{}
@@ -16,3 +29,15 @@ type noauth struct{}
 func (t noauth) Deploy(context.Context) error {
 	return status.Error(codes.PermissionDenied, "invalid credentials")
 }
+
+func (t noauth) Restart(context.Context) error {
+	return status.Error(codes.PermissionDenied, "invalid credentials")
+}
+
+func (t noauth) DeployTo(context.Context, ...scopedPeer) error {
+	return status.Error(codes.PermissionDenied, "invalid credentials")
+}
+
+func (t noauth) Agent(context.Context) error {
+	return status.Error(codes.PermissionDenied, "invalid credentials")
+}
@@ -103,8 +103,20 @@ func ExampleConfigClient(options ...ConfigClientOption) ConfigClient {
 type Deployment struct {
 	DataDir   string        `yaml:"dir"`
 	Timeout   time.Duration `yaml:"timeout"`
-	Prompt    string        `yaml:"prompt"`  // used to prompt before a deploy is started, useful for deploying to sensitive systems like production.
-	CommitRef string        `yaml:"treeish"` // used to populate commit information in the environment
+	Prompt    string        `yaml:"prompt"`   // used to prompt before a deploy is started, useful for deploying to sensitive systems like production.
+	CommitRef string        `yaml:"treeish"`  // used to populate commit information in the environment
+	Prestage  bool          `yaml:"prestage"` // distribute the archive to every node before any node begins deploying.
+	// how long to wait for every node to stage the archive, defaults to 15 minutes.
+	// the deploy's timeout applies once prestaging completes.
+	PrestageTimeout time.Duration `yaml:"prestageTimeout"`
+	Archive         ArchiveConfig `yaml:"archive"`
+}
+
+// ArchiveConfig packing configuration for deployment archives.
+type ArchiveConfig struct {
+	Compression  string `yaml:"compression"`  // gzip (default) or zstd.
+	Level        int    `yaml:"level"`        // compression level, zero uses the default level of the algorithm.
+	Reproducible bool   `yaml:"reproducible"` // normalize timestamps and ownership so identical contents produce identical archives.
 }
 
 // ConfigClient ...
@@ -173,6 +185,17 @@ func NewConfig(options ...ConfigOption) Config {
 			TTL:       60,
 			Frequency: time.Hour,
 		},
+		Dashboard: dashboard{
+			Session: 12 * time.Hour,
+		},
+		Logs: logs{
+			KeepN:         20,
+			Compress:      true,
+			CompressAfter: 24 * time.Hour,
+		},
+		Signatures: signatures{
+			Retention: 90 * 24 * time.Hour,
+		},
 	}
 
 	newTLSAgent(bw.DefaultEnvironmentName)(&c)
@@ -234,6 +257,93 @@ func ConfigOptionName(name string) ConfigOption {
 	}
 }
 
+type logs struct {
+	KeepN         int           `yaml:"keepN"`         // number of deploy logs to retain, independent of the deploy keepN. zero disables retention.
+	MaxAge        time.Duration `yaml:"maxAge"`        // remove retained logs older than this duration. zero disables.
+	Compress      bool          `yaml:"compress"`      // gzip retained deploy logs.
+	CompressAfter time.Duration `yaml:"compressAfter"` // age at which retained logs are compressed. zero compresses them once the deploy completes.
+	Syslog        struct {
+		Address  string `yaml:"address"`  // host:port of a syslog server accepting RFC5424 messages over tcp.
+		TLS      bool   `yaml:"tls"`      // connect to the syslog server using tls.
+		Insecure bool   `yaml:"insecure"` // skip tls verification.
+	} `yaml:"syslog"`
+	Loki struct {
+		Endpoint string            `yaml:"endpoint"` // loki push api url. e.g.) http://localhost:3100/loki/api/v1/push
+		Tenant   string            `yaml:"tenant"`   // tenant id for multi-tenant loki deployments.
+		Labels   map[string]string `yaml:"labels"`   // static labels added to every stream.
+	} `yaml:"loki"`
+}
+
+type audit struct {
+	// export the audit log to the file as json lines, empty disables the export.
+	// entries may be repeated after a restart, they can be deduplicated by their sequence.
+	Path string `yaml:"path"`
+}
+
+// oidc issuer trusted to exchange workload identity tokens for short lived deploy grants.
+type oidc struct {
+	Issuer   string        `yaml:"issuer"`   // expected iss claim, e.g.) https://token.actions.githubusercontent.com
+	Audience string        `yaml:"audience"` // expected aud claim.
+	JWKS     string        `yaml:"jwks"`     // path to the issuer's json web key set, reloaded when modified.
+	Subjects []string      `yaml:"subjects"` // regular expressions, one must match the entire sub claim.
+	TTL      time.Duration `yaml:"ttl"`      // duration of the issued grants, defaults to 15m.
+}
+
+// verification of the signatures of deploy archives.
+type signatures struct {
+	// accept archives without a signature, e.g.) archives uploaded before archives were signed.
+	// signed archives are always verified.
+	Unsigned bool `yaml:"unsigned"`
+	// how long expired grants are retained so archives they signed remain deployable,
+	// e.g.) rollbacks to archives uploaded using short lived grants. defaults to 90 days.
+	Retention time.Duration `yaml:"retention"`
+}
+
+// authority key storage for the cluster's certificate authority.
+type authority struct {
+	Keys   string `yaml:"keys"` // disk (default), pkcs11 (requires building with -tags pkcs11), or vault.
+	PKCS11 struct {
+		Module string `yaml:"module"` // path to the PKCS#11 library, e.g.) /usr/lib/softhsm/libsofthsm2.so
+		Token  string `yaml:"token"`  // label of the token holding the keys.
+		Pin    string `yaml:"pin"`    // user pin of the token.
+		Label  string `yaml:"label"`  // prefix of the key labels, defaults to bw-tlsca.
+	} `yaml:"pkcs11"`
+	Vault struct {
+		Mount string `yaml:"mount"` // mount path of the transit engine, defaults to transit.
+		Key   string `yaml:"key"`   // prefix of the key names, defaults to bw-tlsca.
+		Type  string `yaml:"type"`  // type of newly created keys, defaults to rsa-4096.
+	} `yaml:"vault"`
+}
+
+// storage backend used to transfer deploy archives to the nodes.
+type archiveStorage struct {
+	Backend string `yaml:"backend"` // torrent (default), s3, or filesystem.
+	S3      struct {
+		Bucket    string `yaml:"bucket"`
+		Prefix    string `yaml:"prefix"`    // key prefix for archives within the bucket.
+		Region    string `yaml:"region"`    // defaults to the region of the aws environment.
+		Endpoint  string `yaml:"endpoint"`  // url of s3 compatible services, e.g.) http://minio.example.com:9000
+		PathStyle bool   `yaml:"pathStyle"` // address buckets by path, required by most s3 compatible services.
+	} `yaml:"s3"`
+	Filesystem struct {
+		Directory string `yaml:"directory"` // directory shared by every node, e.g.) an NFS mount.
+	} `yaml:"filesystem"`
+	Torrent struct {
+		Upload       int           `yaml:"upload"`       // bytes per second each node uploads, zero is unlimited.
+		Download     int           `yaml:"download"`     // bytes per second each node downloads, zero is unlimited.
+		ZoneFallback time.Duration `yaml:"zoneFallback"` // how long downloads only use peers labeled with the same zone, defaults to 10s.
+	} `yaml:"torrent"`
+	Retention struct {
+		KeepN  int      `yaml:"keepN"`  // number of the most recent archives each node retains, defaults to the deploy keepN.
+		Pinned []string `yaml:"pinned"` // deployment ids of archives that are never removed.
+	} `yaml:"retention"`
+}
+
+type dashboard struct {
+	Enabled bool          `yaml:"enabled"` // serve the read only web dashboard over the agent's tls listener.
+	Session time.Duration `yaml:"session"` // duration a dashboard login remains valid.
+}
+
 type bootstrap struct {
 	Attempts         int    `yaml:"attempts"`
 	ReadOnly         bool   `yaml:"readonly"`
@@ -258,16 +368,27 @@ type Config struct {
 		Mode      string `yaml:"source"`
 		Directory string `yaml:"directory"`
 	} `yaml:"credentials"`
-	DNSBind      dnsBind  `yaml:"dnsBind"`
-	DNSBootstrap []string `yaml:"dnsBootstrap"`
+	Authority    authority      `yaml:"authority"`
+	Storage      archiveStorage `yaml:"storage"`
+	DNSBind      dnsBind        `yaml:"dnsBind"`
+	DNSBootstrap []string       `yaml:"dnsBootstrap"`
 	AWSBootstrap struct {
 		AutoscalingGroups []string `yaml:"autoscalingGroups"` // additional autoscaling groups to check for instances.
 	} `yaml:"awsBootstrap"`
+	Dashboard  dashboard  `yaml:"dashboard"`
+	Logs       logs       `yaml:"logs"`
+	Audit      audit      `yaml:"audit"`
+	OIDC       []oidc     `yaml:"oidc"`
+	Signatures signatures `yaml:"signatures"`
+	// labels describing the node, shared with the cluster and used to scope permissions.
+	// labels are gossiped as part of the node metadata so keep them brief.
+	Labels map[string]string `yaml:"labels"`
 }
 
 func (t Config) Sanitize() Config {
 	dup := t
 	dup.ClusterTokens = []string{}
+	dup.Authority.PKCS11.Pin = ""
 	return dup
 }
 
@@ -310,6 +431,7 @@ func (t Config) Peer() *Peer {
 		Name:    t.Name,
 		Ip:      t.P2PAdvertised.IP.String(),
 		P2PPort: uint32(t.P2PAdvertised.Port),
+		Labels:  t.Labels,
 	}
 }
 
@@ -188,6 +188,19 @@ func (t Conn) Deploy(ctx context.Context, options *DeployOptions, archive *Archi
 	return ar.Deploy, nil
 }
 
+// Stage the archive on the node ahead of its deploy.
+func (t Conn) Stage(ctx context.Context, archive *Archive) (err error) {
+	rpc := NewAgentClient(t.conn)
+
+	return grpcx.Retry(ctx, func() error {
+		if _, err = rpc.Stage(ctx, &StageRequest{Archive: archive}); err != nil {
+			return errors.Wrap(err, "failed to stage archive")
+		}
+
+		return nil
+	}, codes.Unavailable)
+}
+
 // Connect ...
 func (t Conn) Connect(ctx context.Context) (d *ConnectResponse, err error) {
 	rpc := NewAgentClient(t.conn)
@@ -249,6 +262,12 @@ func (t Conn) Dispatch(ctx context.Context, messages ...*Message) (err error) {
 	return nil
 }
 
+// Record an entry into the audit log.
+func (t Conn) Record(ctx context.Context, e *AuditEntry) (err error) {
+	_, err = NewQuorumClient(t.conn).Record(ctx, &AuditRecordRequest{Entry: e})
+	return err
+}
+
 // Logs return the logs for the given deployment.
 func (t Conn) Logs(ctx context.Context, p *Peer, did []byte) io.ReadCloser {
 	var (
@@ -264,6 +283,28 @@ func (t Conn) Logs(ctx context.Context, p *Peer, did []byte) io.ReadCloser {
 	return readLogs(c)
 }
 
+// SearchLogs search the deploy logs of the connected agent, invoking match for each result.
+func (t Conn) SearchLogs(ctx context.Context, req *LogSearchRequest, match func(*LogSearchMatch) error) (err error) {
+	var (
+		c Agent_SearchLogsClient
+		m *LogSearchMatch
+	)
+
+	if c, err = NewAgentClient(t.conn).SearchLogs(ctx, req); err != nil {
+		return errors.WithStack(err)
+	}
+
+	for {
+		if m, err = c.Recv(); err != nil {
+			return iox.IgnoreEOF(err)
+		}
+
+		if err = match(m); err != nil {
+			return err
+		}
+	}
+}
+
 type logsClient interface {
 	Recv() (*LogResponse, error)
 }
@@ -13,6 +13,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/grpcx"
@@ -54,13 +55,109 @@ func (t DeployConn) Cancel(ctx context.Context, req *CancelRequest) error {
 }
 
 // Upload an archive to be deployed.
+// uploads with a session resume from the offset acknowledged by the quorum.
+// sessions are only known to the quorum node that received the upload, when the
+// upload lands on another node it restarts from the beginning.
 func (t DeployConn) Upload(ctx context.Context, m *UploadMetadata, src io.Reader) (info *Archive, err error) {
+	return t.upload(ctx, m, func(stream archiveWriter, checksum io.Writer, offset uint64) error {
+		if _, err := io.CopyN(checksum, src, int64(offset)); err != nil {
+			return errors.Wrap(err, "failed to skip acknowledged data")
+		}
+
+		return streamArchive(io.TeeReader(src, checksum), stream)
+	})
+}
+
+// UploadDelta upload an archive to be deployed, chunks present in the held archive
+// are sent as references for the quorum to resolve from its own copy. falls back
+// to uploading the entire archive when the quorum is unable to resolve them.
+func (t DeployConn) UploadDelta(ctx context.Context, m *UploadMetadata, src io.ReadSeeker, held *Archive) (info *Archive, err error) {
+	refs := make(map[string]bool, len(held.GetChunks()))
+	for _, c := range held.GetChunks() {
+		refs[hex.EncodeToString(c.Digest)] = true
+	}
+
+	info, err = t.upload(ctx, m, func(stream archiveWriter, checksum io.Writer, offset uint64) (err error) {
+		position := uint64(0)
+		for _, c := range m.Chunks {
+			chunk := io.TeeReader(io.LimitReader(src, int64(c.Length)), checksum)
+			skip := min(offset-min(position, offset), c.Length)
+			position += c.Length
+
+			// skip the portion of the chunk already acknowledged by the quorum.
+			if _, err = io.CopyN(io.Discard, chunk, int64(skip)); err != nil {
+				return errors.Wrap(err, "failed to skip acknowledged data")
+			}
+
+			if skip == c.Length {
+				continue
+			}
+
+			if skip > 0 || !refs[hex.EncodeToString(c.Digest)] {
+				if err = streamArchive(chunk, stream); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if _, err = io.Copy(io.Discard, chunk); err != nil {
+				return errors.Wrap(err, "failed to read chunk")
+			}
+
+			ref := &UploadChunk{
+				Checksum:             []byte{},
+				Data:                 []byte{},
+				InitialChunkMetadata: &UploadChunk_None{},
+				Reference:            c,
+			}
+
+			if err = stream.Send(ref); err != nil {
+				return errors.Wrap(err, "failed to write chunk reference")
+			}
+		}
+
+		// any remaining data is not described by the chunks.
+		if _, err = io.CopyN(checksum, src, int64(offset-min(position, offset))); err != nil {
+			return errors.Wrap(err, "failed to skip acknowledged data")
+		}
+
+		return streamArchive(io.TeeReader(src, checksum), stream)
+	})
+
+	if !grpcx.IsCode(err, codes.FailedPrecondition) {
+		return info, err
+	}
+
+	if _, err = src.Seek(0, io.SeekStart); err != nil {
+		return info, errors.Wrap(err, "unable to rewind archive")
+	}
+
+	return t.Upload(ctx, m, src)
+}
+
+func (t DeployConn) upload(ctx context.Context, m *UploadMetadata, send func(stream archiveWriter, checksum io.Writer, offset uint64) error) (info *Archive, err error) {
 	var (
 		stream Deployments_UploadClient
 		_info  *UploadResponse
+		resp   *UploadStatusResponse
 		rpc    = NewDeploymentsClient(t.conn)
 	)
 
+	m = proto.Clone(m).(*UploadMetadata)
+	m.Offset = 0
+
+	// older quorums are unable to resume uploads, the upload starts from the beginning.
+	if m.Session != "" {
+		if resp, err = rpc.UploadStatus(ctx, &UploadStatusRequest{Session: m.Session}); grpcx.IsUnimplemented(err) {
+			resp = &UploadStatusResponse{}
+		} else if err != nil {
+			return info, errors.Wrap(err, "failed to retrieve upload status")
+		}
+
+		m.Offset = resp.Offset
+	}
+
 	if stream, err = rpc.Upload(ctx); err != nil {
 		return info, errors.Wrap(err, "failed to create upload stream")
 	}
@@ -79,7 +176,14 @@ func (t DeployConn) Upload(ctx context.Context, m *UploadMetadata, src io.Reader
 	}
 
 	checksum := sha256.New()
-	if err = streamArchive(io.TeeReader(src, checksum), stream); err != nil {
+	if err = send(stream, checksum, m.Offset); errors.Is(err, io.EOF) {
+		// the server terminated the stream, retrieve the actual error.
+		if _, cause := stream.CloseAndRecv(); cause != nil {
+			return info, cause
+		}
+
+		return info, err
+	} else if err != nil {
 		return info, errorsx.Compact(err, stream.CloseSend())
 	}
 
@@ -113,6 +217,27 @@ func (t DeployConn) RemoteDeploy(ctx context.Context, initiator string, dopts *D
 	return errors.WithStack(err)
 }
 
+// Stage distribute the archive to the servers ahead of its deploy, blocks until
+// every server has staged the archive.
+func (t DeployConn) Stage(ctx context.Context, initiator string, a *Archive, peers ...*Peer) (_ *Staged, err error) {
+	var (
+		resp *StageCommandResult
+	)
+
+	req := StageCommandRequest{
+		Initiator: initiator,
+		Archive:   a,
+		Peers:     peers,
+	}
+
+	err = grpcx.Retry(ctx, func() (cause error) {
+		resp, cause = NewDeploymentsClient(t.conn).Stage(ctx, &req)
+		return cause
+	}, codes.Unavailable)
+
+	return resp.GetStaged(), errors.WithStack(err)
+}
+
 // Watch for messages sent to the leader. blocks.
 func (t DeployConn) Watch(ctx context.Context, out chan<- *Message) (err error) {
 	var (
@@ -150,3 +275,39 @@ func (t DeployConn) Logs(ctx context.Context, p *Peer, did []byte) io.ReadCloser
 
 	return readLogs(c)
 }
+
+// Download the contents of the archive from the cluster's storage.
+// the cluster resolves the archive's location from the deployment id.
+func (t DeployConn) Download(ctx context.Context, a *Archive) io.ReadCloser {
+	var (
+		err error
+		c   Deployments_DownloadClient
+	)
+
+	rpc := NewDeploymentsClient(t.conn)
+	if c, err = rpc.Download(ctx, &ArchiveDownloadRequest{DeploymentID: a.DeploymentID}); err != nil {
+		return io.NopCloser(iox.ErrReader(err))
+	}
+
+	r, w := io.Pipe()
+	go func() {
+		for {
+			var (
+				werr error
+				resp *ArchiveDownloadResponse
+			)
+
+			if resp, werr = c.Recv(); werr != nil {
+				w.CloseWithError(werr)
+				return
+			}
+
+			if _, werr = w.Write(resp.Content); werr != nil {
+				w.CloseWithError(werr)
+				return
+			}
+		}
+	}()
+
+	return r
+}
@@ -0,0 +1,127 @@
+package agent_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"sync"
+
+	. "github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/grpcx"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// resumable upload server that interrupts the first upload once the
+// specified number of bytes are received.
+type resumable struct {
+	UnimplementedDeploymentsServer
+	sync.Mutex
+	interrupt   int
+	interrupted bool
+	received    []byte
+	references  int
+}
+
+func (t *resumable) UploadStatus(ctx context.Context, req *UploadStatusRequest) (*UploadStatusResponse, error) {
+	t.Lock()
+	defer t.Unlock()
+	return &UploadStatusResponse{Offset: uint64(len(t.received))}, nil
+}
+
+func (t *resumable) Upload(stream Deployments_UploadServer) error {
+	chunk, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if chunk.GetMetadata().Offset != uint64(len(t.received)) {
+		return status.Error(codes.OutOfRange, "offset mismatch")
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			digest := sha256.Sum256(t.received)
+			return stream.SendAndClose(&UploadResponse{Archive: &Archive{Checksum: digest[:]}})
+		} else if err != nil {
+			return err
+		}
+
+		if ref := chunk.GetReference(); ref != nil {
+			t.references++
+			t.received = append(t.received, make([]byte, ref.Length)...)
+			continue
+		}
+
+		t.received = append(t.received, chunk.Data...)
+		if !t.interrupted && len(t.received) >= t.interrupt {
+			t.interrupted = true
+			return status.Error(codes.Unavailable, "interrupted")
+		}
+	}
+}
+
+var _ = Describe("DeployConn", func() {
+	content := make([]byte, 3*1024*1024)
+	_, err := rand.Read(content)
+	Expect(err).To(Succeed())
+
+	upload := func(srv *resumable, do func(DeployConn) (*Archive, error)) (err error) {
+		conn, s := testingx.NewGRPCServer(func(s *grpc.Server) {
+			RegisterDeploymentsServer(s, srv)
+		})
+		defer s.Stop()
+		defer conn.Close()
+
+		_, err = do(NewDeployConn(conn))
+		return err
+	}
+
+	It("should resume interrupted uploads", func() {
+		srv := &resumable{interrupt: 1024 * 1024}
+		do := func(c DeployConn) (*Archive, error) {
+			return c.Upload(context.Background(), &UploadMetadata{Session: "session"}, bytes.NewReader(content))
+		}
+
+		Expect(grpcx.IsCode(upload(srv, do), codes.Unavailable)).To(BeTrue())
+		Expect(len(srv.received)).To(Equal(srv.interrupt))
+		Expect(upload(srv, do)).To(Succeed())
+		Expect(srv.received).To(Equal(content))
+	})
+
+	It("should resume interrupted delta uploads", func() {
+		held := make([]byte, 1024*1024)
+		encoded := append(append(append([]byte(nil), content[:1024*1024]...), held...), content[2*1024*1024:]...)
+		chunks := []*ArchiveChunk{
+			{Digest: []byte{0x1}, Length: 1024 * 1024},
+			{Digest: []byte{0x2}, Length: 1024 * 1024},
+			{Digest: []byte{0x3}, Length: 1024 * 1024},
+		}
+
+		srv := &resumable{interrupt: 512 * 1024}
+		do := func(c DeployConn) (*Archive, error) {
+			return c.UploadDelta(
+				context.Background(),
+				&UploadMetadata{Session: "session", Chunks: chunks},
+				bytes.NewReader(encoded),
+				&Archive{Chunks: chunks[1:2]},
+			)
+		}
+
+		Expect(grpcx.IsCode(upload(srv, do), codes.Unavailable)).To(BeTrue())
+		Expect(upload(srv, do)).To(Succeed())
+		Expect(srv.received).To(Equal(encoded))
+		Expect(srv.references).To(Equal(1))
+	})
+})
@@ -0,0 +1,494 @@
+// Package dashboard serves a read only web interface displaying the state of the cluster.
+// it is served by the agents over their tls listener and requires the notary search permission.
+package dashboard
+
+import (
+	"context"
+	_ "embed"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/memberlist"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/notary"
+)
+
+//go:embed index.html
+var index []byte
+
+//go:embed login.html
+var loginpage []byte
+
+type cluster interface {
+	Peers() []*agent.Peer
+	GetN(n int, key []byte) []*memberlist.Node
+}
+
+// verifies the authorization token presented during login.
+type authority interface {
+	Authorization(encoded []byte) error
+}
+
+type grants interface {
+	Lookup(fingerprint string) (*notary.Grant, error)
+}
+
+// Option for the dashboard.
+type Option func(*Dashboard)
+
+// OptionSessionTTL duration a login remains valid.
+func OptionSessionTTL(d time.Duration) Option {
+	return func(t *Dashboard) {
+		if d > 0 {
+			t.sessions.ttl = d
+		}
+	}
+}
+
+// OptionTimeout for requests made to the cluster.
+func OptionTimeout(d time.Duration) Option {
+	return func(t *Dashboard) {
+		t.timeout = d
+	}
+}
+
+// OptionPreviousSecrets secrets of sessions issued before the secret was rotated.
+func OptionPreviousSecrets(secrets ...[]byte) Option {
+	return func(t *Dashboard) {
+		t.sessions.previous = secrets
+	}
+}
+
+// New dashboard. the secret is used to sign login sessions and should be shared by the cluster.
+func New(secret []byte, c cluster, d dialers.Defaults, a authority, g grants, options ...Option) *Dashboard {
+	dash := &Dashboard{
+		cluster:   c,
+		dialer:    d,
+		quorum:    dialers.NewQuorum(c, d.Defaults()...),
+		authority: a,
+		grants:    g,
+		sessions: sessions{
+			secret: secret,
+			ttl:    12 * time.Hour,
+		},
+		timeout: 10 * time.Second,
+	}
+
+	for _, opt := range options {
+		opt(dash)
+	}
+
+	return dash
+}
+
+// Dashboard http handlers.
+type Dashboard struct {
+	cluster   cluster
+	dialer    dialers.Defaults
+	quorum    dialers.ContextDialer
+	authority authority
+	grants    grants
+	sessions  sessions
+	timeout   time.Duration
+	upgrader  websocket.Upgrader
+}
+
+// Bind the dashboard routes to the router.
+func (t *Dashboard) Bind(r *mux.Router) {
+	r.Path("/login").Methods(http.MethodGet).HandlerFunc(t.loginPage)
+	r.Path("/login").Methods(http.MethodPost).HandlerFunc(t.login)
+	r.Path("/").Methods(http.MethodGet).Handler(t.authenticated(http.HandlerFunc(t.index)))
+	r.Path("/api/quorum").Methods(http.MethodGet).Handler(t.authenticated(http.HandlerFunc(t.quorumInfo)))
+	r.Path("/api/nodes").Methods(http.MethodGet).Handler(t.authenticated(http.HandlerFunc(t.nodes)))
+	r.Path("/api/logs").Methods(http.MethodGet).Handler(t.authenticated(http.HandlerFunc(t.logs)))
+	r.Path("/api/watch").Methods(http.MethodGet).Handler(t.authenticated(http.HandlerFunc(t.watch)))
+}
+
+// Handler for the dashboard.
+func (t *Dashboard) Handler() http.Handler {
+	r := mux.NewRouter()
+	t.Bind(r)
+	return r
+}
+
+// loginPage submits the authorization from the url's fragment, fragments are never
+// sent to the server so the token stays out of access logs.
+func (t *Dashboard) loginPage(resp http.ResponseWriter, req *http.Request) {
+	resp.Header().Set("Content-Type", "text/html; charset=utf-8")
+	resp.Header().Set("Cache-Control", "no-store")
+	resp.Header().Set("Referrer-Policy", "no-referrer")
+	errorsx.Log(errors.WithStack(writeAll(resp, loginpage)))
+}
+
+// login exchanges a notary authorization token for a session.
+func (t *Dashboard) login(resp http.ResponseWriter, req *http.Request) {
+	var (
+		err     error
+		a       *notary.Authorization
+		encoded = req.PostFormValue("authorization")
+	)
+
+	if err = t.authority.Authorization([]byte(encoded)); err != nil {
+		http.Error(resp, "invalid authorization, generate a new login using: bw info dashboard", http.StatusUnauthorized)
+		return
+	}
+
+	if a, err = notary.DecodeAuthorization(encoded); err != nil {
+		http.Error(resp, "invalid authorization", http.StatusUnauthorized)
+		return
+	}
+
+	log.Println("dashboard login", a.Token.Fingerprint)
+	http.SetCookie(resp, t.sessions.issue(a.Token.Fingerprint, time.Now()))
+	http.Redirect(resp, req, "/", http.StatusSeeOther)
+}
+
+// authenticated ensures the request has a valid session for a grant that
+// is still valid and has search permissions. the grant's scope limits the
+// peers visible to the request.
+func (t *Dashboard) authenticated(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		var (
+			err         error
+			c           *http.Cookie
+			g           *notary.Grant
+			fingerprint string
+		)
+
+		if c, err = req.Cookie(cookieName); err != nil {
+			http.Error(resp, "missing session, generate a login using: bw info dashboard", http.StatusUnauthorized)
+			return
+		}
+
+		if fingerprint, err = t.sessions.verify(c, time.Now()); err != nil {
+			http.Error(resp, "invalid session, generate a login using: bw info dashboard", http.StatusUnauthorized)
+			return
+		}
+
+		if g, err = t.grants.Lookup(fingerprint); err != nil || !g.GetPermission().GetSearch() {
+			http.Error(resp, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		if err = g.Valid(time.Now()); err != nil {
+			http.Error(resp, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(resp, req.WithContext(context.WithValue(req.Context(), scopeKey{}, g.GetPermission().GetScope())))
+	})
+}
+
+type scopeKey struct{}
+
+// scope of the grant the request was authenticated with, nil when unrestricted.
+func scope(req *http.Request) *notary.Scope {
+	s, _ := req.Context().Value(scopeKey{}).(*notary.Scope)
+	return s
+}
+
+// peers within the scope of the request.
+func (t *Dashboard) peers(req *http.Request) (peers []*agent.Peer) {
+	s := scope(req)
+	for _, p := range t.cluster.Peers() {
+		if s.Match(p) {
+			peers = append(peers, p)
+		}
+	}
+
+	return peers
+}
+
+func (t *Dashboard) index(resp http.ResponseWriter, req *http.Request) {
+	resp.Header().Set("Content-Type", "text/html; charset=utf-8")
+	resp.Header().Set("Cache-Control", "no-store")
+	errorsx.Log(errors.WithStack(writeAll(resp, index)))
+}
+
+func (t *Dashboard) quorumInfo(resp http.ResponseWriter, req *http.Request) {
+	var (
+		err  error
+		conn *grpc.ClientConn
+		info *agent.InfoResponse
+	)
+
+	ctx, done := context.WithTimeout(req.Context(), t.timeout)
+	defer done()
+
+	if conn, err = t.quorum.DialContext(ctx); err != nil {
+		log.Println(errors.Wrap(err, "dashboard unable to connect to quorum"))
+		http.Error(resp, "quorum unavailable", http.StatusBadGateway)
+		return
+	}
+	defer conn.Close()
+
+	if info, err = agent.NewQuorumClient(conn).Info(ctx, &agent.InfoRequest{}); err != nil {
+		log.Println(errors.Wrap(err, "dashboard unable to retrieve quorum info"))
+		http.Error(resp, "quorum unavailable", http.StatusBadGateway)
+		return
+	}
+
+	if s := scope(req); s != nil {
+		quorum := info.Quorum[:0]
+		for _, p := range info.Quorum {
+			if s.Match(p) {
+				quorum = append(quorum, p)
+			}
+		}
+		info.Quorum = quorum
+
+		if info.Leader != nil && !s.Match(info.Leader) {
+			info.Leader = nil
+		}
+	}
+
+	writeProto(resp, info)
+}
+
+type node struct {
+	Peer        json.RawMessage   `json:"peer"`
+	Deployments []json.RawMessage `json:"deployments"`
+	Error       string            `json:"error,omitempty"`
+}
+
+func (t *Dashboard) nodes(resp http.ResponseWriter, req *http.Request) {
+	var (
+		wg    sync.WaitGroup
+		peers = t.peers(req)
+		nodes = make([]node, len(peers))
+	)
+
+	ctx, done := context.WithTimeout(req.Context(), t.timeout)
+	defer done()
+
+	for idx, p := range peers {
+		wg.Add(1)
+		go func(idx int, p *agent.Peer) {
+			defer wg.Done()
+			nodes[idx] = t.node(ctx, p)
+		}(idx, p)
+	}
+
+	wg.Wait()
+
+	resp.Header().Set("Content-Type", "application/json")
+	errorsx.Log(errors.WithStack(json.NewEncoder(resp).Encode(nodes)))
+}
+
+func (t *Dashboard) node(ctx context.Context, p *agent.Peer) (n node) {
+	var (
+		err     error
+		conn    *grpc.ClientConn
+		info    *agent.StatusResponse
+		encoded []byte
+	)
+
+	n.Peer = marshal(p)
+	n.Deployments = []json.RawMessage{}
+
+	if conn, err = dialers.NewDirect(agent.RPCAddress(p), t.dialer.Defaults()...).DialContext(ctx); err != nil {
+		n.Error = errors.Wrap(err, "unable to connect").Error()
+		return n
+	}
+	defer conn.Close()
+
+	if info, err = agent.NewConn(conn).Info(ctx); err != nil {
+		n.Error = err.Error()
+		return n
+	}
+
+	for _, d := range info.Deployments {
+		if encoded, err = protojson.Marshal(d); err != nil {
+			n.Error = errors.Wrap(err, "unable to encode deployment").Error()
+			return n
+		}
+
+		n.Deployments = append(n.Deployments, encoded)
+	}
+
+	return n
+}
+
+// logs streams the deployment logs from the requested peer.
+func (t *Dashboard) logs(resp http.ResponseWriter, req *http.Request) {
+	var (
+		err  error
+		did  []byte
+		p    *agent.Peer
+		conn *grpc.ClientConn
+	)
+
+	q := req.URL.Query()
+
+	if did, err = base64.StdEncoding.DecodeString(q.Get("deployment")); err != nil || len(did) == 0 {
+		http.Error(resp, "invalid deployment id", http.StatusBadRequest)
+		return
+	}
+
+	for _, c := range t.peers(req) {
+		if c.Name == q.Get("peer") {
+			p = c
+			break
+		}
+	}
+
+	if p == nil {
+		http.Error(resp, "unknown peer", http.StatusNotFound)
+		return
+	}
+
+	if conn, err = dialers.NewDirect(agent.RPCAddress(p), t.dialer.Defaults()...).DialContext(req.Context()); err != nil {
+		log.Println(errors.Wrap(err, "dashboard unable to connect to peer"))
+		http.Error(resp, "peer unavailable", http.StatusBadGateway)
+		return
+	}
+	defer conn.Close()
+
+	logs := agent.NewConn(conn).Logs(req.Context(), p, did)
+	defer logs.Close()
+
+	resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if _, err = io.Copy(resp, logs); err != nil {
+		log.Println(errors.Wrap(err, "dashboard log stream failed"))
+	}
+}
+
+// watch streams the quorum's event history followed by live events over a websocket.
+func (t *Dashboard) watch(resp http.ResponseWriter, req *http.Request) {
+	var (
+		err     error
+		ws      *websocket.Conn
+		conn    *grpc.ClientConn
+		history *agent.HistoryResponse
+		events  agent.Quorum_WatchClient
+		m       *agent.Message
+	)
+
+	if ws, err = t.upgrader.Upgrade(resp, req, nil); err != nil {
+		log.Println(errors.Wrap(err, "dashboard websocket upgrade failed"))
+		return
+	}
+	defer ws.Close()
+
+	ctx, done := context.WithCancel(req.Context())
+	defer done()
+
+	// the dashboard never sends messages, but we need to read in order
+	// to process control frames and detect when the client disconnects.
+	go func() {
+		defer done()
+		for {
+			if _, _, err := ws.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	if conn, err = t.quorum.DialContext(ctx); err != nil {
+		log.Println(errors.Wrap(err, "dashboard unable to connect to quorum"))
+		return
+	}
+	defer conn.Close()
+
+	qc := agent.NewQuorumClient(conn)
+
+	if history, err = qc.History(ctx, &agent.HistoryRequest{}); err != nil {
+		log.Println(errors.Wrap(err, "dashboard unable to retrieve history"))
+		return
+	}
+
+	s := scope(req)
+
+	for _, h := range history.Messages {
+		if !visible(s, h) {
+			continue
+		}
+
+		if err = t.send(ws, h); err != nil {
+			return
+		}
+	}
+
+	if events, err = qc.Watch(ctx, &agent.WatchRequest{}); err != nil {
+		log.Println(errors.Wrap(err, "dashboard unable to watch quorum"))
+		return
+	}
+
+	for m, err = events.Recv(); err == nil; m, err = events.Recv() {
+		if !visible(s, m) {
+			continue
+		}
+
+		if err = t.send(ws, m); err != nil {
+			return
+		}
+	}
+
+	errorsx.Log(errors.Wrap(errorsx.Ignore(err, context.Canceled), "dashboard watch failed"))
+}
+
+// visible determines if the message was emitted by a peer within the scope.
+func visible(s *notary.Scope, m *agent.Message) bool {
+	return s == nil || (m.Peer != nil && s.Match(m.Peer))
+}
+
+func (t *Dashboard) send(ws *websocket.Conn, m *agent.Message) (err error) {
+	var (
+		encoded []byte
+	)
+
+	if encoded, err = protojson.Marshal(m); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err = ws.SetWriteDeadline(time.Now().Add(t.timeout)); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(ws.WriteMessage(websocket.TextMessage, encoded))
+}
+
+func writeProto(resp http.ResponseWriter, m proto.Message) {
+	var (
+		err     error
+		encoded []byte
+	)
+
+	if encoded, err = protojson.Marshal(m); err != nil {
+		log.Println(errors.Wrap(err, "dashboard unable to encode response"))
+		http.Error(resp, "unable to encode response", http.StatusInternalServerError)
+		return
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	errorsx.Log(errors.WithStack(writeAll(resp, encoded)))
+}
+
+func marshal(m proto.Message) json.RawMessage {
+	encoded, err := protojson.Marshal(m)
+	if err != nil {
+		log.Println(errors.Wrap(err, "dashboard unable to encode message"))
+		return json.RawMessage("null")
+	}
+
+	return encoded
+}
+
+func writeAll(w io.Writer, b []byte) error {
+	_, err := w.Write(b)
+	return err
+}
@@ -0,0 +1,231 @@
+package dashboard_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/memberlist"
+	"github.com/pkg/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dashboard"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/internal/rsax"
+	"github.com/james-lawrence/bw/internal/sshx"
+	"github.com/james-lawrence/bw/notary"
+)
+
+type emptycluster struct{}
+
+func (t emptycluster) Peers() []*agent.Peer                      { return nil }
+func (t emptycluster) GetN(n int, key []byte) []*memberlist.Node { return nil }
+
+type staticcluster []*agent.Peer
+
+func (t staticcluster) Peers() []*agent.Peer                      { return t }
+func (t staticcluster) GetN(n int, key []byte) []*memberlist.Node { return nil }
+
+func searchable(perm *notary.Permission) error {
+	if perm.Search {
+		return nil
+	}
+
+	return errors.New("unauthorized")
+}
+
+func quickGrant(perm *notary.Permission) (notary.Signer, *notary.Grant) {
+	pkey, err := rsax.UnsafeAuto()
+	Expect(err).To(Succeed())
+	pubkey, err := sshx.PublicKey(pkey)
+	Expect(err).To(Succeed())
+	ss, err := notary.NewSigner(pkey)
+	Expect(err).To(Succeed())
+
+	return ss, (&notary.Grant{Permission: perm, Authorization: pubkey}).EnsureDefaults()
+}
+
+func login(h http.Handler, ss notary.Signer, ttl time.Duration) *httptest.ResponseRecorder {
+	encoded, err := ss.TokenTTL(ttl)
+	Expect(err).To(Succeed())
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(url.Values{"authorization": []string{encoded}}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	return resp
+}
+
+func index(h http.Handler, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	return get(h, "/", cookies...)
+}
+
+func get(h http.Handler, path string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, req)
+	return resp
+}
+
+var _ = Describe("Dashboard", func() {
+	var (
+		ss      notary.Signer
+		grant   *notary.Grant
+		storage notary.Directory
+		handler http.Handler
+	)
+
+	BeforeEach(func() {
+		ss, grant = quickGrant(&notary.Permission{Search: true})
+		storage = notary.NewDirectory(GinkgoT().TempDir())
+		_, err := storage.Insert(grant)
+		Expect(err).To(Succeed())
+
+		handler = dashboard.New(
+			[]byte("secret"),
+			emptycluster{},
+			dialers.NewDefaults(),
+			notary.NewAuthChecker(storage, searchable),
+			storage,
+		).Handler()
+	})
+
+	It("should reject requests without a session", func() {
+		Expect(index(handler).Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should exchange a valid authorization for a session", func() {
+		resp := login(handler, ss, time.Minute)
+		Expect(resp.Code).To(Equal(http.StatusSeeOther))
+		Expect(resp.Result().Cookies()).To(HaveLen(1))
+
+		resp = index(handler, resp.Result().Cookies()...)
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Header().Get("Content-Type")).To(ContainSubstring("text/html"))
+	})
+
+	It("should accept sessions issued by other agents sharing the secret", func() {
+		resp := login(handler, ss, time.Minute)
+		Expect(resp.Code).To(Equal(http.StatusSeeOther))
+
+		other := dashboard.New(
+			[]byte("secret"),
+			emptycluster{},
+			dialers.NewDefaults(),
+			notary.NewAuthChecker(storage, searchable),
+			storage,
+		).Handler()
+
+		Expect(index(other, resp.Result().Cookies()...).Code).To(Equal(http.StatusOK))
+	})
+
+	It("should not accept authorizations from the query string", func() {
+		encoded, err := ss.TokenTTL(time.Minute)
+		Expect(err).To(Succeed())
+
+		resp := get(handler, "/login?"+url.Values{"authorization": []string{encoded}}.Encode())
+		Expect(resp.Code).To(Equal(http.StatusOK))
+		Expect(resp.Result().Cookies()).To(BeEmpty())
+	})
+
+	It("should accept sessions signed by a previous secret", func() {
+		resp := login(handler, ss, time.Minute)
+		Expect(resp.Code).To(Equal(http.StatusSeeOther))
+
+		rotated := dashboard.New(
+			[]byte("rotated"),
+			emptycluster{},
+			dialers.NewDefaults(),
+			notary.NewAuthChecker(storage, searchable),
+			storage,
+			dashboard.OptionPreviousSecrets([]byte("secret")),
+		).Handler()
+
+		Expect(index(rotated, resp.Result().Cookies()...).Code).To(Equal(http.StatusOK))
+	})
+
+	It("should reject expired authorizations", func() {
+		Expect(login(handler, ss, -time.Minute).Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should reject authorizations without search permission", func() {
+		deployer, g := quickGrant(&notary.Permission{Deploy: true})
+		_, err := storage.Insert(g)
+		Expect(err).To(Succeed())
+		Expect(login(handler, deployer, time.Minute).Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should reject tampered sessions", func() {
+		resp := login(handler, ss, time.Minute)
+		cookie := resp.Result().Cookies()[0]
+		cookie.Value = cookie.Value + "a"
+		Expect(index(handler, cookie).Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should reject sessions signed with a different secret", func() {
+		resp := login(handler, ss, time.Minute)
+
+		other := dashboard.New(
+			[]byte("different"),
+			emptycluster{},
+			dialers.NewDefaults(),
+			notary.NewAuthChecker(storage, searchable),
+			storage,
+		).Handler()
+
+		Expect(index(other, resp.Result().Cookies()...).Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should reject sessions once the grant is revoked", func() {
+		resp := login(handler, ss, time.Minute)
+		Expect(resp.Code).To(Equal(http.StatusSeeOther))
+
+		_, err := storage.Delete(grant)
+		Expect(err).To(Succeed())
+
+		Expect(index(handler, resp.Result().Cookies()...).Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("should reject sessions once the grant expires", func() {
+		resp := login(handler, ss, time.Minute)
+		Expect(resp.Code).To(Equal(http.StatusSeeOther))
+
+		grant.NotAfter = time.Now().Add(-time.Minute).Unix()
+		_, err := storage.Insert(grant)
+		Expect(err).To(Succeed())
+
+		Expect(index(handler, resp.Result().Cookies()...).Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("should limit the peers visible to scoped grants", func() {
+		scoped, g := quickGrant(&notary.Permission{Search: true, Scope: &notary.Scope{Labels: []string{"env=staging"}}})
+		_, err := storage.Insert(g)
+		Expect(err).To(Succeed())
+
+		h := dashboard.New(
+			[]byte("secret"),
+			staticcluster{
+				agent.NewPeer("staging", agent.PeerOptionLabels(map[string]string{"env": "staging"})),
+				agent.NewPeer("production", agent.PeerOptionLabels(map[string]string{"env": "production"})),
+			},
+			dialers.NewDefaults(),
+			notary.NewAuthChecker(storage, searchable),
+			storage,
+		).Handler()
+
+		resp := login(h, scoped, time.Minute)
+		Expect(resp.Code).To(Equal(http.StatusSeeOther))
+
+		logs := "/api/logs?" + url.Values{"peer": []string{"production"}, "deployment": []string{"ZGVwbG95bWVudA=="}}.Encode()
+		Expect(get(h, logs, resp.Result().Cookies()...).Code).To(Equal(http.StatusNotFound))
+	})
+})
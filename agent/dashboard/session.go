@@ -0,0 +1,99 @@
+package dashboard
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const cookieName = "bw.dashboard"
+
+// sessions are stateless, signed with a secret shared by the cluster.
+// this allows a login against any agent to remain valid for the others.
+// sessions signed by previous secrets remain valid, allowing the secret to be rotated.
+type sessions struct {
+	secret   []byte
+	previous [][]byte
+	ttl      time.Duration
+}
+
+func (t sessions) sign(payload string) string {
+	return sign(t.secret, payload)
+}
+
+func sign(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signed determines if the signature was produced by the current or a previous secret.
+func (t sessions) signed(payload, sig string) bool {
+	for _, secret := range append([][]byte{t.secret}, t.previous...) {
+		if hmac.Equal([]byte(sig), []byte(sign(secret, payload))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// issue a session cookie for the given fingerprint.
+func (t sessions) issue(fingerprint string, now time.Time) *http.Cookie {
+	expires := now.Add(t.ttl)
+	payload := fmt.Sprintf("%d.%s", expires.Unix(), fingerprint)
+
+	return &http.Cookie{
+		Name:     cookieName,
+		Value:    base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + t.sign(payload),
+		Path:     "/",
+		Expires:  expires,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	}
+}
+
+// verify the session cookie returning the fingerprint it was issued for.
+func (t sessions) verify(c *http.Cookie, now time.Time) (fingerprint string, err error) {
+	var (
+		decoded []byte
+		expires int64
+	)
+
+	encoded, sig, ok := strings.Cut(c.Value, ".")
+	if !ok {
+		return "", errors.New("malformed session")
+	}
+
+	if decoded, err = base64.RawURLEncoding.DecodeString(encoded); err != nil {
+		return "", errors.Wrap(err, "malformed session")
+	}
+
+	payload := string(decoded)
+	if !t.signed(payload, sig) {
+		return "", errors.New("invalid session signature")
+	}
+
+	ts, fingerprint, ok := strings.Cut(payload, ".")
+	if !ok {
+		return "", errors.New("malformed session")
+	}
+
+	if expires, err = strconv.ParseInt(ts, 10, 64); err != nil {
+		return "", errors.Wrap(err, "malformed session")
+	}
+
+	if now.Unix() > expires {
+		return "", errors.New("session expired")
+	}
+
+	return fingerprint, nil
+}
@@ -28,7 +28,8 @@ import (
 )
 
 type auth interface {
-	Deploy(ctx context.Context) error
+	// Profile checks if the request is allowed to debug the agent.
+	Profile(ctx context.Context) error
 }
 
 func NewService(a auth) *Service {
@@ -55,7 +56,7 @@ func (t *Service) Bind(srv *grpc.Server) *Service {
 }
 
 func (t Service) Stacktrace(ctx context.Context, _ *StacktraceRequest) (_ *StacktraceResponse, err error) {
-	if err = t.auth.Deploy(ctx); err != nil {
+	if err = t.auth.Profile(ctx); err != nil {
 		return nil, err
 	}
 
@@ -92,7 +93,7 @@ func (t *Service) Download(ctx context.Context, req *DownloadRequest) (_ *Downlo
 		return nil, status.Errorf(codes.Unavailable, "profile has not completed")
 	}
 
-	if err = t.auth.Deploy(ctx); err != nil {
+	if err = t.auth.Profile(ctx); err != nil {
 		return nil, err
 	}
 
@@ -118,7 +119,7 @@ func (t *Service) Download(ctx context.Context, req *DownloadRequest) (_ *Downlo
 }
 
 func (t *Service) Cancel(ctx context.Context, req *CancelRequest) (_ *CancelResponse, err error) {
-	if err = t.auth.Deploy(ctx); err != nil {
+	if err = t.auth.Profile(ctx); err != nil {
 		return nil, err
 	}
 
@@ -132,7 +133,7 @@ func (t *Service) profile(ctx context.Context, req *ProfileRequest, strategy fun
 		return nil, status.Errorf(codes.AlreadyExists, "a profile is already in progress")
 	}
 
-	if err = t.auth.Deploy(ctx); err != nil {
+	if err = t.auth.Profile(ctx); err != nil {
 		return nil, err
 	}
 
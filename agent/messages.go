@@ -121,6 +121,38 @@ func NewDeployHeartbeat(p *Peer) *Message {
 	}
 }
 
+// TransferEvent progress of a local transfer.
+func TransferEvent(p *Peer, description string, completed, total uint64, elapsed time.Duration) *Message {
+	return &Message{
+		Id:        uuid.Must(uuid.NewV4()).String(),
+		Type:      Message_TransferEvent,
+		Ephemeral: true,
+		Peer:      p,
+		Ts:        time.Now().Unix(),
+		Event: &Message_Transfer{
+			Transfer: &Transfer{
+				Description: description,
+				Completed:   completed,
+				Total:       total,
+				Elapsed:     int64(elapsed),
+			},
+		},
+	}
+}
+
+// StagedEvent an archive staged on nodes ahead of its deploy.
+func StagedEvent(p *Peer, s *Staged) *Message {
+	return &Message{
+		Id:   uuid.Must(uuid.NewV4()).String(),
+		Type: Message_StageEvent,
+		Peer: p,
+		Ts:   time.Now().Unix(),
+		Event: &Message_Staged{
+			Staged: s,
+		},
+	}
+}
+
 // PeerEvent ...
 func PeerEvent(p *Peer) *Message {
 	return &Message{
@@ -182,6 +214,24 @@ func (t *DeployOptions) DeployOption(dc *DeployCommand) {
 	dc.Options = t
 }
 
+// DeployCommandOptionError records the reason a deploy failed.
+func DeployCommandOptionError(cause error) doption {
+	return func(dc *DeployCommand) {
+		if cause == nil {
+			return
+		}
+
+		dc.Error = cause.Error()
+	}
+}
+
+// DeployCommandOptionPeers records the nodes targeted by the deploy.
+func DeployCommandOptionPeers(peers ...*Peer) doption {
+	return func(dc *DeployCommand) {
+		dc.Peers = peers
+	}
+}
+
 func updateDTS(dc *DeployCommand) {
 	if dc.Archive == nil {
 		return
@@ -295,3 +345,20 @@ func ApplyToStateMachine(r *raft.Raft, m *Message, d time.Duration) (err error)
 
 	return nil
 }
+
+// NewAuthorityEvent shares the private key of a certificate authority generation with the quorum.
+// authority events are hidden from observers and are not retained by the WAL, the quorum's
+// authorities retain them.
+func NewAuthorityEvent(p *Peer, a *AuthorityGeneration) *Message {
+	return &Message{
+		Id:        uuid.Must(uuid.NewV4()).String(),
+		Type:      Message_AuthorityEvent,
+		Hidden:    true,
+		Ephemeral: true,
+		Peer:      p,
+		Ts:        time.Now().Unix(),
+		Event: &Message_Authority{
+			Authority: a,
+		},
+	}
+}
@@ -32,6 +32,11 @@ func URIDiscovery(address string) string {
 	return fmt.Sprintf("%s://%s", bw.ProtocolDiscovery, address)
 }
 
+// URIExchange for the oidc exchange of a node.
+func URIExchange(address string) string {
+	return fmt.Sprintf("%s://%s", bw.ProtocolExchange, address)
+}
+
 // URIPeer for a peer
 func URIPeer(p *Peer, proto string) string {
 	if p.P2PPort == 0 {
@@ -100,6 +105,13 @@ func PeerOptionName(n string) PeerOption {
 	}
 }
 
+// PeerOptionLabels labels describing the peer.
+func PeerOptionLabels(labels map[string]string) PeerOption {
+	return func(p *Peer) {
+		p.Labels = labels
+	}
+}
+
 // PeerOptionPublicKey peers public key.
 func PeerOptionPublicKey(k []byte) PeerOption {
 	return func(p *Peer) {
@@ -149,11 +161,32 @@ func NodesToPeers(nodes ...*memberlist.Node) []*Peer {
 	return peers
 }
 
+// ResolvePeers the members of the cluster a deploy to the requested peers will target.
+// an empty set of peers targets the entire cluster. the cluster's view of the peers
+// is returned to ensure information provided by the requester, such as labels, is ignored.
+func ResolvePeers(members []*memberlist.Node, requested ...*Peer) (resolved []*Peer) {
+	ips := make(map[string]bool, len(requested))
+	for _, p := range requested {
+		ips[p.Ip] = true
+	}
+
+	for _, p := range NodesToPeers(members...) {
+		if len(requested) > 0 && !ips[p.Ip] {
+			continue
+		}
+
+		resolved = append(resolved, p)
+	}
+
+	return resolved
+}
+
 // PeerToMetadata ...
 func PeerToMetadata(p *Peer) *PeerMetadata {
 	return &PeerMetadata{
 		Status:  int32(p.Status),
 		P2PPort: p.P2PPort,
+		Labels:  p.Labels,
 	}
 }
 
@@ -202,6 +235,7 @@ func NodeToPeer(n *memberlist.Node) (_zerop *Peer, err error) {
 		Name:    n.Name,
 		Ip:      n.Addr.String(),
 		P2PPort: m.P2PPort,
+		Labels:  m.Labels,
 	}, nil
 }
 
@@ -1,35 +1,48 @@
 package proxy
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"log"
 
+	"github.com/hashicorp/memberlist"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/internal/bytesx"
 	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/iox"
 	"github.com/james-lawrence/bw/notary"
+	"github.com/james-lawrence/bw/storage"
 )
 
 type auth interface {
 	Authorize(ctx context.Context) *notary.Permission
+	Fingerprint(ctx context.Context) string
+}
+
+type membership interface {
+	Members() []*memberlist.Node
 }
 
 // NewDeployment proxy for deploys, dialer must be a quorum dialer.
-func NewDeployment(a auth, d dialers.Quorum) Deployment {
-	return Deployment{Auth: a, Dialer: d}
+func NewDeployment(a auth, m membership, d dialers.Quorum, dl storage.DownloadFactory) Deployment {
+	return Deployment{Auth: a, Members: m, Dialer: d, Downloads: dl}
 }
 
 // Deployment - proxy deployment commands from any agent to quorum.
 type Deployment struct {
 	agent.UnimplementedDeploymentsServer
-	Auth   auth
-	Dialer dialers.Quorum
+	Auth      auth
+	Members   membership
+	Dialer    dialers.Quorum
+	Downloads storage.DownloadFactory
 }
 
 // Bind to the given grpc server.
@@ -45,6 +58,25 @@ func (t Deployment) direct(ctx context.Context, p *agent.Peer) (conn *grpc.Clien
 	return dialers.NewDirect(agent.RPCAddress(p), t.Dialer.Defaults()...).DialContext(ctx)
 }
 
+// forwarded records the requester the quorum is dialed on behalf of, so the quorum
+// does not record the request into the audit log a second time.
+func (t Deployment) forwarded(ctx context.Context) context.Context {
+	return agent.AuditForwarded(ctx, t.Auth.Fingerprint(ctx))
+}
+
+// scoped ensures the peers are within the permitted scope, when no peers are provided
+// every member of the cluster must be within the scope. the quorum is dialed with the
+// agent's credentials, so the scope of the requester must be enforced before proxying.
+func (t Deployment) scoped(p *notary.Permission, peers ...*agent.Peer) error {
+	for _, peer := range agent.ResolvePeers(t.Members.Members(), peers...) {
+		if !p.Scope.Match(peer) {
+			return status.Errorf(codes.PermissionDenied, "peer %s (%s) is outside of the permitted scope", peer.Name, peer.Ip)
+		}
+	}
+
+	return nil
+}
+
 // Upload a deployment archive into the cluster
 func (t Deployment) Upload(stream agent.Deployments_UploadServer) (err error) {
 	var (
@@ -89,16 +121,40 @@ func (t Deployment) Upload(stream agent.Deployments_UploadServer) (err error) {
 	return stream.SendAndClose(resp)
 }
 
+// UploadStatus retrieve the offset an interrupted upload resumes from.
+func (t Deployment) UploadStatus(ctx context.Context, req *agent.UploadStatusRequest) (_ *agent.UploadStatusResponse, err error) {
+	var (
+		cc *grpc.ClientConn
+	)
+
+	if !t.Auth.Authorize(ctx).Deploy {
+		return nil, status.Error(codes.PermissionDenied, "invalid credentials")
+	}
+
+	if cc, err = t.conn(ctx); err != nil {
+		log.Println("proxy connection failed", err)
+		return nil, status.Error(codes.Unavailable, "proxy connection error")
+	}
+	defer cc.Close()
+
+	return agent.NewQuorumClient(cc).UploadStatus(ctx, req)
+}
+
 // Deploy execute an actual deployment archive.
 func (t Deployment) Deploy(ctx context.Context, req *agent.DeployCommandRequest) (resp *agent.DeployCommandResult, err error) {
 	var (
 		cc *grpc.ClientConn
 	)
 
-	if !t.Auth.Authorize(ctx).Deploy {
+	p := t.Auth.Authorize(ctx)
+	if !p.Deploy {
 		return resp, status.Error(codes.PermissionDenied, "invalid credentials")
 	}
 
+	if err = t.scoped(p, req.Peers...); err != nil {
+		return resp, err
+	}
+
 	if cc, err = t.conn(ctx); err != nil {
 		cause := status.Error(codes.Unavailable, "proxy connection error")
 		errorsx.Log(cause)
@@ -106,16 +162,43 @@ func (t Deployment) Deploy(ctx context.Context, req *agent.DeployCommandRequest)
 	}
 	defer cc.Close()
 
-	return agent.NewQuorumClient(cc).Deploy(ctx, req)
+	return agent.NewQuorumClient(cc).Deploy(t.forwarded(ctx), req)
 }
 
-// Cancel an active deploy.
-func (t Deployment) Cancel(ctx context.Context, req *agent.CancelRequest) (resp *agent.CancelResponse, err error) {
+// Stage distribute a deployment archive to the nodes ahead of its deploy.
+func (t Deployment) Stage(ctx context.Context, req *agent.StageCommandRequest) (resp *agent.StageCommandResult, err error) {
 	var (
 		cc *grpc.ClientConn
 	)
 
-	if !t.Auth.Authorize(ctx).Deploy {
+	p := t.Auth.Authorize(ctx)
+	if !p.Deploy {
+		return resp, status.Error(codes.PermissionDenied, "invalid credentials")
+	}
+
+	if err = t.scoped(p, req.Peers...); err != nil {
+		return resp, err
+	}
+
+	if cc, err = t.conn(ctx); err != nil {
+		cause := status.Error(codes.Unavailable, "proxy connection error")
+		errorsx.Log(cause)
+		return resp, cause
+	}
+	defer cc.Close()
+
+	return agent.NewQuorumClient(cc).Stage(t.forwarded(ctx), req)
+}
+
+// Cancel an active deploy, the deploy must only target peers within the permitted scope.
+func (t Deployment) Cancel(ctx context.Context, req *agent.CancelRequest) (resp *agent.CancelResponse, err error) {
+	var (
+		cc   *grpc.ClientConn
+		info *agent.InfoResponse
+	)
+
+	p := t.Auth.Authorize(ctx)
+	if !p.Deploy {
 		return resp, status.Error(codes.PermissionDenied, "invalid credentials")
 	}
 
@@ -126,7 +209,19 @@ func (t Deployment) Cancel(ctx context.Context, req *agent.CancelRequest) (resp
 	}
 	defer cc.Close()
 
-	return agent.NewQuorumClient(cc).Cancel(ctx, req)
+	if p.Scope != nil {
+		if info, err = agent.NewQuorumClient(cc).Info(ctx, &agent.InfoRequest{}); err != nil {
+			return resp, err
+		}
+
+		if dc := info.GetDeploying(); dc != nil {
+			if err = t.scoped(p, dc.Peers...); err != nil {
+				return resp, err
+			}
+		}
+	}
+
+	return agent.NewQuorumClient(cc).Cancel(t.forwarded(ctx), req)
 }
 
 // Watch watch for events.
@@ -136,7 +231,8 @@ func (t Deployment) Watch(req *agent.WatchRequest, out agent.Deployments_WatchSe
 		w  agent.Quorum_WatchClient
 	)
 
-	if !t.Auth.Authorize(out.Context()).Deploy {
+	p := t.Auth.Authorize(out.Context())
+	if !p.Deploy {
 		return status.Error(codes.PermissionDenied, "invalid credentials")
 	}
 
@@ -152,7 +248,12 @@ func (t Deployment) Watch(req *agent.WatchRequest, out agent.Deployments_WatchSe
 		return err
 	}
 
+	observable := t.observable(p)
 	for msg, err := w.Recv(); err == nil; msg, err = w.Recv() {
+		if !observable(msg) {
+			continue
+		}
+
 		if err = out.Send(msg); err != nil {
 			return err
 		}
@@ -161,16 +262,70 @@ func (t Deployment) Watch(req *agent.WatchRequest, out agent.Deployments_WatchSe
 	return errorsx.Compact(errors.WithStack(err), w.CloseSend())
 }
 
+// observable determines which messages the permission is allowed to observe.
+// messages of deploys are only observable when the deploy is within the scope,
+// all other messages when the peer emitting them is within the scope.
+func (t Deployment) observable(p *notary.Permission) func(*agent.Message) bool {
+	deploying := false // the active deploy is within the scope.
+
+	return func(m *agent.Message) bool {
+		if p.Scope == nil {
+			return true
+		}
+
+		switch m.Type {
+		case agent.Message_DeployCommandEvent:
+			if dc := m.GetDeployCommand(); dc.Command == agent.DeployCommand_Begin {
+				deploying = t.scoped(p, dc.Peers...) == nil
+			}
+
+			return deploying
+		case agent.Message_PeersFoundEvent, agent.Message_PeersCompletedEvent, agent.Message_DeployHeartbeat:
+			return deploying
+		case agent.Message_StageEvent:
+			return t.scoped(p, m.GetStaged().GetPeers()...) == nil
+		default:
+			return p.Scope.Match(m.Peer)
+		}
+	}
+}
+
+// dispatchable ensures the permission is allowed to dispatch the messages. log events
+// only require the emitting peer to be within the scope, every other message impacts
+// the entire cluster.
+func (t Deployment) dispatchable(p *notary.Permission, messages ...*agent.Message) error {
+	for _, m := range messages {
+		if m.Type == agent.Message_LogEvent && m.GetLog() != nil && m.Peer != nil {
+			if err := t.scoped(p, m.Peer); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := t.scoped(p); err != nil {
+			return status.Errorf(codes.PermissionDenied, "%s messages are not permitted by the scope", m.Type)
+		}
+	}
+
+	return nil
+}
+
 // Dispatch messages to the state machine.
 func (t Deployment) Dispatch(ctx context.Context, req *agent.DispatchRequest) (resp *agent.DispatchResponse, err error) {
 	var (
 		cc *grpc.ClientConn
 	)
 
-	if !t.Auth.Authorize(ctx).Deploy {
+	p := t.Auth.Authorize(ctx)
+	if !p.Deploy {
 		return resp, status.Error(codes.PermissionDenied, "invalid credentials")
 	}
 
+	if err = t.dispatchable(p, req.Messages...); err != nil {
+		return resp, err
+	}
+
 	if cc, err = t.conn(ctx); err != nil {
 		cause := status.Error(codes.Unavailable, "proxy connection error")
 		errorsx.Log(cause)
@@ -188,7 +343,8 @@ func (t Deployment) Logs(req *agent.LogRequest, out agent.Deployments_LogsServer
 		w  agent.Deployments_LogsClient
 	)
 
-	if !t.Auth.Authorize(out.Context()).Deploy {
+	p := t.Auth.Authorize(out.Context())
+	if !p.Deploy {
 		return status.Error(codes.PermissionDenied, "invalid credentials")
 	}
 
@@ -199,6 +355,14 @@ func (t Deployment) Logs(req *agent.LogRequest, out agent.Deployments_LogsServer
 		)
 	}
 
+	if p.Scope != nil && len(agent.ResolvePeers(t.Members.Members(), req.Peer)) == 0 {
+		return status.Errorf(codes.PermissionDenied, "peer %s (%s) is not a member of the cluster", req.Peer.Name, req.Peer.Ip)
+	}
+
+	if err = t.scoped(p, req.Peer); err != nil {
+		return err
+	}
+
 	if cc, err = t.direct(out.Context(), req.Peer); err != nil {
 		cause := status.Error(codes.Unavailable, "proxy connection error")
 		errorsx.Log(cause)
@@ -218,3 +382,120 @@ func (t Deployment) Logs(req *agent.LogRequest, out agent.Deployments_LogsServer
 
 	return errorsx.Compact(errors.WithStack(err), w.CloseSend())
 }
+
+// Download retrieve a deployment archive from the cluster's storage.
+// the archive is resolved from the deploys known to the cluster, never from the request,
+// otherwise the agent would read any location its storage is able to reach.
+func (t Deployment) Download(req *agent.ArchiveDownloadRequest, out agent.Deployments_DownloadServer) (err error) {
+	const KB16 = 16 * bytesx.KiB
+	var (
+		a *agent.Archive
+	)
+
+	p := t.Auth.Authorize(out.Context())
+	if !p.Deploy {
+		return status.Error(codes.PermissionDenied, "invalid credentials")
+	}
+
+	id := req.DeploymentID
+	if len(id) == 0 {
+		// older clients only provide the archive.
+		id = req.GetArchive().GetDeploymentID()
+	}
+
+	if len(id) == 0 {
+		return status.Error(codes.InvalidArgument, "deployment to download required")
+	}
+
+	if a, err = t.archive(out.Context(), p, id); err != nil {
+		return err
+	}
+
+	src := t.Downloads.New(a.Location).Download(out.Context(), a)
+
+	buf := bytes.NewBuffer(make([]byte, 0, KB16))
+	for err == nil {
+		err = iox.Error(io.CopyN(buf, src, KB16))
+		if buf.Len() > 0 {
+			err = errorsx.Compact(err, out.Send(&agent.ArchiveDownloadResponse{Content: buf.Bytes()}))
+		}
+		buf.Reset()
+	}
+
+	return errorsx.Compact(iox.IgnoreEOF(err), src.Close())
+}
+
+// archive of the deployment, resolved from the quorum's deploys and then from the
+// deploys retained by the agents. only deploys within the permitted scope are resolved.
+func (t Deployment) archive(ctx context.Context, p *notary.Permission, id []byte) (_ *agent.Archive, err error) {
+	var (
+		cc   *grpc.ClientConn
+		info *agent.InfoResponse
+	)
+
+	if cc, err = t.conn(ctx); err != nil {
+		cause := status.Error(codes.Unavailable, "proxy connection error")
+		errorsx.Log(cause)
+		return nil, cause
+	}
+	defer cc.Close()
+
+	if info, err = agent.NewQuorumClient(cc).Info(ctx, &agent.InfoRequest{}); err != nil {
+		return nil, err
+	}
+
+	known := []struct {
+		a     *agent.Archive
+		peers []*agent.Peer
+	}{
+		{a: info.GetDeployed().GetArchive(), peers: info.GetDeployed().GetPeers()},
+		{a: info.GetDeploying().GetArchive(), peers: info.GetDeploying().GetPeers()},
+		{a: info.GetStaged().GetArchive(), peers: info.GetStaged().GetPeers()},
+	}
+
+	for _, k := range known {
+		if k.a != nil && bytes.Equal(k.a.DeploymentID, id) && t.scoped(p, k.peers...) == nil {
+			return k.a, nil
+		}
+	}
+
+	for _, peer := range agent.ResolvePeers(t.Members.Members()) {
+		if !p.Scope.Match(peer) {
+			continue
+		}
+
+		if a := t.deployed(ctx, peer, id); a != nil {
+			return a, nil
+		}
+	}
+
+	return nil, status.Errorf(codes.NotFound, "deployment %s not found", bw.RandomID(id))
+}
+
+// deployed archive of the deployment retained by the peer, nil when the peer does not hold it.
+func (t Deployment) deployed(ctx context.Context, p *agent.Peer, id []byte) *agent.Archive {
+	var (
+		err  error
+		cc   *grpc.ClientConn
+		info *agent.StatusResponse
+	)
+
+	if cc, err = t.direct(ctx, p); err != nil {
+		log.Println("unable to connect to peer", p.Name, err)
+		return nil
+	}
+	defer cc.Close()
+
+	if info, err = agent.NewAgentClient(cc).Info(ctx, &agent.StatusRequest{}); err != nil {
+		log.Println("unable to retrieve deployments", p.Name, err)
+		return nil
+	}
+
+	for _, d := range info.Deployments {
+		if a := d.GetArchive(); a != nil && bytes.Equal(a.DeploymentID, id) {
+			return a
+		}
+	}
+
+	return nil
+}
@@ -2,25 +2,41 @@ package agent
 
 import (
 	"context"
+	"net"
 
+	"github.com/hashicorp/memberlist"
 	"github.com/james-lawrence/bw/internal/grpcx"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 type quorum interface {
 	Deploy(ctx context.Context, by string, opts *DeployOptions, a *Archive, peers ...*Peer) error
+	Stage(ctx context.Context, by string, a *Archive, peers ...*Peer) (*Staged, error)
 	Upload(stream Quorum_UploadServer) error
+	UploadStatus(context.Context, *UploadStatusRequest) (*UploadStatusResponse, error)
 	Watch(stream Quorum_WatchServer) error
 	History(context.Context) ([]*Message, error)
+	Audit(context.Context, *AuditRequest) (*AuditResponse, error)
+	Record(context.Context, *AuditEntry) error
+	Authority(context.Context, uint64) ([]byte, error)
 	Dispatch(context.Context, ...*Message) error
 	Info(context.Context) (InfoResponse, error)
 	Cancel(context.Context, *CancelRequest) error
 }
 
+type membership interface {
+	Members() []*memberlist.Node
+}
+
 // NewQuorum ...
-func NewQuorum(q quorum, a auth) Quorum {
+func NewQuorum(q quorum, m membership, a auth) Quorum {
 	return Quorum{
 		q:    q,
+		m:    m,
 		auth: a,
 	}
 }
@@ -30,6 +46,7 @@ type Quorum struct {
 	UnimplementedQuorumServer
 	auth
 	q quorum
+	m membership
 }
 
 // Bind to a grpc server.
@@ -55,7 +72,7 @@ func (t Quorum) Info(ctx context.Context, _ *InfoRequest) (_ *InfoResponse, err
 
 // Deploy ...
 func (t Quorum) Deploy(ctx context.Context, req *DeployCommandRequest) (_ *DeployCommandResult, err error) {
-	if err := t.auth.Deploy(ctx); err != nil {
+	if err := t.auth.DeployTo(ctx, t.resolve(req.Peers...)...); err != nil {
 		return nil, err
 	}
 
@@ -66,6 +83,32 @@ func (t Quorum) Deploy(ctx context.Context, req *DeployCommandRequest) (_ *Deplo
 	return &DeployCommandResult{}, err
 }
 
+// Stage distributes the archive to the peers ahead of its deploy.
+func (t Quorum) Stage(ctx context.Context, req *StageCommandRequest) (_ *StageCommandResult, err error) {
+	var (
+		staged *Staged
+	)
+
+	if err = t.auth.DeployTo(ctx, t.resolve(req.Peers...)...); err != nil {
+		return nil, err
+	}
+
+	if staged, err = t.q.Stage(ctx, req.Initiator, req.Archive, req.Peers...); err != nil {
+		return nil, err
+	}
+
+	return &StageCommandResult{Staged: staged}, nil
+}
+
+// resolve the peers a deploy will target from the cluster membership.
+func (t Quorum) resolve(requested ...*Peer) (resolved []scopedPeer) {
+	for _, p := range ResolvePeers(t.m.Members(), requested...) {
+		resolved = append(resolved, p)
+	}
+
+	return resolved
+}
+
 // History
 func (t Quorum) History(ctx context.Context, req *HistoryRequest) (resp *HistoryResponse, err error) {
 	var (
@@ -84,6 +127,86 @@ func (t Quorum) History(ctx context.Context, req *HistoryRequest) (resp *History
 	}, nil
 }
 
+// Audit retrieve a page of the audit log.
+func (t Quorum) Audit(ctx context.Context, req *AuditRequest) (resp *AuditResponse, err error) {
+	if err := t.auth.Deploy(ctx); err != nil {
+		return resp, err
+	}
+
+	return t.q.Audit(ctx, req)
+}
+
+// Record an entry into the audit log. the entry is chained by the quorum,
+// any sequence or digest provided by the agent is discarded.
+func (t Quorum) Record(ctx context.Context, req *AuditRecordRequest) (_ *AuditRecordResponse, err error) {
+	if err = t.auth.Agent(ctx); err != nil {
+		return nil, err
+	}
+
+	if req.Entry == nil {
+		return nil, status.Error(codes.InvalidArgument, "audit entry required")
+	}
+
+	e := proto.Clone(req.Entry).(*AuditEntry)
+	e.Sequence, e.Previous, e.Digest = 0, nil, nil
+
+	if err = t.q.Record(ctx, e); err != nil {
+		return nil, err
+	}
+
+	return &AuditRecordResponse{}, nil
+}
+
+// Authority sealed private key of a certificate authority generation, only agents connecting
+// from a member of the cluster are permitted. every agent requires the key to rotate the authority.
+func (t Quorum) Authority(ctx context.Context, req *AuthorityRequest) (_ *AuthorityResponse, err error) {
+	var (
+		key []byte
+	)
+
+	if err = t.auth.Agent(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = t.member(ctx); err != nil {
+		return nil, err
+	}
+
+	if key, err = t.q.Authority(ctx, req.Generation); err != nil {
+		return nil, err
+	}
+
+	return &AuthorityResponse{
+		Authority: &AuthorityGeneration{Generation: req.Generation, Key: key},
+	}, nil
+}
+
+// member ensures the request originates from the address of a member of the cluster,
+// credentials alone are insufficient since a user with the grant permission can issue agent credentials.
+func (t Quorum) member(ctx context.Context) error {
+	var (
+		host string
+		err  error
+	)
+
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return status.Error(codes.PermissionDenied, "unknown origin")
+	}
+
+	if host, _, err = net.SplitHostPort(p.Addr.String()); err != nil {
+		return status.Error(codes.PermissionDenied, "unknown origin")
+	}
+
+	for _, n := range t.m.Members() {
+		if n.Addr.Equal(net.ParseIP(host)) {
+			return nil
+		}
+	}
+
+	return status.Errorf(codes.PermissionDenied, "%s is not a member of the cluster", host)
+}
+
 // Upload ...
 func (t Quorum) Upload(stream Quorum_UploadServer) (err error) {
 	if err := t.auth.Deploy(stream.Context()); err != nil {
@@ -93,6 +216,15 @@ func (t Quorum) Upload(stream Quorum_UploadServer) (err error) {
 	return t.q.Upload(stream)
 }
 
+// UploadStatus ...
+func (t Quorum) UploadStatus(ctx context.Context, req *UploadStatusRequest) (*UploadStatusResponse, error) {
+	if err := t.auth.Deploy(ctx); err != nil {
+		return nil, err
+	}
+
+	return t.q.UploadStatus(ctx, req)
+}
+
 // Watch watch for events.
 func (t Quorum) Watch(_ *WatchRequest, out Quorum_WatchServer) (err error) {
 	if err := t.auth.Deploy(out.Context()); err != nil {
@@ -102,20 +234,53 @@ func (t Quorum) Watch(_ *WatchRequest, out Quorum_WatchServer) (err error) {
 	return t.q.Watch(out)
 }
 
-// Dispatch record deployment events.
+// Dispatch record deployment events. log events only require the emitting peer to be
+// within the permitted scope, every other message impacts the entire cluster.
 func (t Quorum) Dispatch(ctx context.Context, req *DispatchRequest) (*DispatchResponse, error) {
 	if err := t.auth.Deploy(ctx); err != nil {
 		return nil, err
 	}
 
+	// audit entries are only recorded by the quorum, see Record.
+	// authority keys are only generated by the quorum, see Authority.
+	for _, m := range req.Messages {
+		if m.GetAudit() != nil {
+			return nil, status.Error(codes.InvalidArgument, "audit events cannot be dispatched")
+		}
+
+		if m.GetAuthority() != nil {
+			return nil, status.Error(codes.InvalidArgument, "authority events cannot be dispatched")
+		}
+
+		peers := []*Peer(nil)
+		if m.Type == Message_LogEvent && m.GetLog() != nil && m.Peer != nil {
+			peers = append(peers, m.Peer)
+		}
+
+		if err := t.auth.DeployTo(ctx, t.resolve(peers...)...); err != nil {
+			return nil, err
+		}
+	}
+
 	return &DispatchResponse{}, t.q.Dispatch(ctx, req.Messages...)
 }
 
-// Cancel the active deploy.
+// Cancel the active deploy, the deploy must only target peers within the permitted scope.
 func (t Quorum) Cancel(ctx context.Context, req *CancelRequest) (*CancelResponse, error) {
 	if err := t.auth.Deploy(ctx); err != nil {
 		return nil, err
 	}
 
+	info, err := t.q.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if dc := info.GetDeploying(); dc != nil {
+		if err = t.auth.DeployTo(ctx, t.resolve(dc.Peers...)...); err != nil {
+			return nil, err
+		}
+	}
+
 	return &CancelResponse{}, t.q.Cancel(ctx, req)
 }
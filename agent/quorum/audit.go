@@ -0,0 +1,193 @@
+package quorum
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/james-lawrence/bw/agent"
+)
+
+const (
+	// maximum number of entries returned by a single page of the audit log.
+	maxAuditPage = 1000
+	// number of exported entries retained in memory, older entries are only
+	// available from the export.
+	auditRetained = 10000
+)
+
+// NewAudit tracks the audit log of the cluster. entries are chained in the order
+// they're applied to the WAL, since every member of quorum applies the WAL in the same
+// order every member computes the same chain.
+func NewAudit() *Audit {
+	return &Audit{
+		m: &sync.RWMutex{},
+	}
+}
+
+// Audit transcoder, maintains the hash chained audit log.
+type Audit struct {
+	m        *sync.RWMutex
+	entries  []*agent.AuditEntry
+	sink     io.Writer
+	exported uint64 // sequence of the latest exported entry.
+}
+
+// Decode chains new audit entries onto the log.
+func (t *Audit) Decode(ctx TranscoderContext, m *agent.Message) error {
+	var (
+		e *agent.AuditEntry
+	)
+
+	if e = m.GetAudit(); e == nil {
+		return nil
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	var last *agent.AuditEntry
+	if len(t.entries) > 0 {
+		last = t.entries[len(t.entries)-1]
+	}
+
+	// entries with a sequence have already been chained, they're being restored from a snapshot.
+	if e.Sequence > 0 {
+		return t.restore(last, e)
+	}
+
+	e.Sequence = 1
+	if last != nil {
+		e.Sequence = last.Sequence + 1
+		e.Previous = last.Digest
+	}
+	e.Digest = agent.AuditDigest(e)
+
+	t.entries = append(t.entries, e)
+
+	if t.sink != nil {
+		if err := t.export(e); err != nil {
+			log.Println("unable to export audit entry", e.Sequence, err)
+			return nil
+		}
+
+		t.exported = e.Sequence
+		t.compact()
+	}
+
+	return nil
+}
+
+// compact drops the exported entries beyond the retained window. entries are
+// dropped in batches to avoid copying the log for every new entry.
+func (t *Audit) compact() {
+	if len(t.entries) < 2*auditRetained {
+		return
+	}
+
+	i := sort.Search(len(t.entries)-auditRetained, func(i int) bool {
+		return t.entries[i].Sequence > t.exported
+	})
+
+	if i == 0 {
+		return
+	}
+
+	t.entries = append([]*agent.AuditEntry(nil), t.entries[i:]...)
+}
+
+// restore a previously chained entry, the entry must extend the chain.
+func (t *Audit) restore(last *agent.AuditEntry, e *agent.AuditEntry) error {
+	if last != nil && e.Sequence <= last.Sequence {
+		if i := len(t.entries) - int(last.Sequence-e.Sequence) - 1; i >= 0 && proto.Equal(t.entries[i], e) {
+			return nil
+		}
+
+		return errors.Errorf("audit entry %d conflicts with the recorded log", e.Sequence)
+	}
+
+	if !bytes.Equal(e.Digest, agent.AuditDigest(e)) {
+		return errors.Errorf("audit entry %d digest mismatch", e.Sequence)
+	}
+
+	// the log restored from a compacted snapshot starts after the first entry.
+	if last == nil && e.Sequence == 1 && len(e.Previous) > 0 {
+		return errors.Errorf("audit entry %d does not start the audit log", e.Sequence)
+	}
+
+	if last != nil && (e.Sequence != last.Sequence+1 || !bytes.Equal(e.Previous, last.Digest)) {
+		return errors.Errorf("audit entry %d is not chained to entry %d", e.Sequence, last.Sequence)
+	}
+
+	t.entries = append(t.entries, e)
+	return nil
+}
+
+// Encode the audit log so it survives compaction of the WAL.
+func (t *Audit) Encode(dst io.Writer) (err error) {
+	t.m.RLock()
+	defer t.m.RUnlock()
+
+	for _, e := range t.entries {
+		if err = encodeProtoTo(dst, agent.NewAuditEvent(nil, e)); err != nil {
+			return errors.Wrap(err, "unable to encode audit log")
+		}
+	}
+
+	return nil
+}
+
+// Entries returns the entries recorded at or after since, optionally restricted to a fingerprint.
+// entries resume after the cursor and at most limit entries are returned, next is the cursor
+// for the following page, 0 when there are no more results.
+func (t *Audit) Entries(since time.Time, fingerprint string, cursor uint64, limit int) (entries []*agent.AuditEntry, next uint64) {
+	t.m.RLock()
+	defer t.m.RUnlock()
+
+	if limit <= 0 || limit > maxAuditPage {
+		limit = maxAuditPage
+	}
+
+	offset := sort.Search(len(t.entries), func(i int) bool {
+		return t.entries[i].Sequence > cursor
+	})
+
+	for _, e := range t.entries[offset:] {
+		if e.Ts < since.Unix() {
+			continue
+		}
+
+		if fingerprint != "" && e.Fingerprint != fingerprint {
+			continue
+		}
+
+		if len(entries) == limit {
+			return entries, entries[limit-1].Sequence
+		}
+
+		entries = append(entries, e)
+	}
+
+	return entries, 0
+}
+
+// export the entry to the sink as a json line.
+func (t *Audit) export(e *agent.AuditEntry) (err error) {
+	var (
+		encoded []byte
+	)
+
+	if encoded, err = protojson.Marshal(e); err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = t.sink.Write(append(encoded, '\n'))
+	return errors.WithStack(err)
+}
@@ -0,0 +1,174 @@
+package quorum
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/james-lawrence/bw/agent"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func auditEntry(fingerprint, rpc string, ts time.Time) *agent.Message {
+	return agent.NewAuditEvent(p, &agent.AuditEntry{Ts: ts.Unix(), Fingerprint: fingerprint, Rpc: rpc})
+}
+
+var _ = Describe("Audit", func() {
+	now := time.Now()
+
+	It("should chain entries in the order they're applied", func() {
+		audit := NewAudit()
+		wal := NewWAL(NewTranscoder(audit))
+		Expect(snapshotRestore(wal, bytes.NewBuffer(nil),
+			auditEntry("fp1", agent.Quorum_Deploy_FullMethodName, now),
+			agent.NewDeployCommand(p, agent.DeployCommandBegin("foo", archive, nil)),
+			auditEntry("fp2", agent.Quorum_Cancel_FullMethodName, now),
+		)).To(Succeed())
+
+		entries, _ := audit.Entries(time.Time{}, "", 0, 0)
+		Expect(entries).To(HaveLen(2))
+		Expect(entries[0].Sequence).To(Equal(uint64(1)))
+		Expect(entries[1].Sequence).To(Equal(uint64(2)))
+		Expect(entries[1].Previous).To(Equal(entries[0].Digest))
+		Expect(agent.VerifyAudit(entries...)).To(Succeed())
+	})
+
+	It("should survive compaction of the WAL", func() {
+		buf := bytes.NewBuffer(nil)
+		audit := NewAudit()
+		wal := NewWAL(NewTranscoder(audit))
+		cmds, err := messagesToCommands(
+			auditEntry("fp1", agent.Quorum_Deploy_FullMethodName, now),
+			agent.NewDeployCommand(p, agent.DeployCommandBegin("foo", archive, nil)),
+			auditEntry("fp2", agent.Quorum_Cancel_FullMethodName, now),
+		)
+		Expect(err).To(Succeed())
+		for _, cmd := range cmds {
+			Expect(wal.decode(wal.ctx, cmd)).To(Succeed())
+		}
+
+		s, err := wal.Snapshot()
+		Expect(err).To(Succeed())
+		Expect(s.Persist(NewInmemSnapshotSink(buf))).To(Succeed())
+
+		restored := NewAudit()
+		rwal := NewWAL(NewTranscoder(restored))
+		Expect(rwal.Restore(io.NopCloser(buf))).To(Succeed())
+		Expect(restored.Entries(time.Time{}, "", 0, 0)).To(HaveLen(2))
+
+		// new entries continue the chain.
+		encoded, err := messagesToCommands(auditEntry("fp3", agent.Agent_Shutdown_FullMethodName, now))
+		Expect(err).To(Succeed())
+		Expect(rwal.decode(rwal.ctx, encoded[0])).To(Succeed())
+
+		entries, _ := restored.Entries(time.Time{}, "", 0, 0)
+		Expect(entries).To(HaveLen(3))
+		Expect(entries[2].Sequence).To(Equal(uint64(3)))
+		Expect(agent.VerifyAudit(entries...)).To(Succeed())
+	})
+
+	It("should reject entries that do not extend the chain", func() {
+		audit := NewAudit()
+		wal := NewWAL(NewTranscoder(audit))
+		Expect(snapshotRestore(wal, bytes.NewBuffer(nil),
+			auditEntry("fp1", agent.Quorum_Deploy_FullMethodName, now),
+		)).To(Succeed())
+		recorded, _ := audit.Entries(time.Time{}, "", 0, 0)
+
+		unchained := &agent.AuditEntry{Sequence: 2, Ts: now.Unix(), Fingerprint: "fp2", Rpc: agent.Quorum_Deploy_FullMethodName, Previous: []byte("forged")}
+		unchained.Digest = agent.AuditDigest(unchained)
+		Expect(audit.Decode(wal.ctx, agent.NewAuditEvent(p, unchained))).To(MatchError("audit entry 2 is not chained to entry 1"))
+
+		tampered := &agent.AuditEntry{Sequence: 2, Ts: now.Unix(), Fingerprint: "fp2", Rpc: agent.Quorum_Deploy_FullMethodName, Previous: recorded[0].Digest, Digest: []byte("forged")}
+		Expect(audit.Decode(wal.ctx, agent.NewAuditEvent(p, tampered))).To(MatchError("audit entry 2 digest mismatch"))
+
+		rewritten := &agent.AuditEntry{Sequence: 1, Ts: now.Unix(), Fingerprint: "fp2", Rpc: agent.Quorum_Deploy_FullMethodName}
+		rewritten.Digest = agent.AuditDigest(rewritten)
+		Expect(audit.Decode(wal.ctx, agent.NewAuditEvent(p, rewritten))).To(MatchError("audit entry 1 conflicts with the recorded log"))
+
+		Expect(audit.Entries(time.Time{}, "", 0, 0)).To(HaveLen(1))
+	})
+
+	It("should filter entries by time and fingerprint", func() {
+		audit := NewAudit()
+		wal := NewWAL(NewTranscoder(audit))
+		Expect(snapshotRestore(wal, bytes.NewBuffer(nil),
+			auditEntry("fp1", agent.Quorum_Deploy_FullMethodName, now.Add(-48*time.Hour)),
+			auditEntry("fp1", agent.Quorum_Deploy_FullMethodName, now),
+			auditEntry("fp2", agent.Quorum_Deploy_FullMethodName, now),
+		)).To(Succeed())
+
+		Expect(audit.Entries(now.Add(-time.Hour), "", 0, 0)).To(HaveLen(2))
+		Expect(audit.Entries(time.Time{}, "fp1", 0, 0)).To(HaveLen(2))
+		Expect(audit.Entries(now.Add(-time.Hour), "fp1", 0, 0)).To(HaveLen(1))
+	})
+
+	It("should export new entries to the sink", func() {
+		var (
+			sink    bytes.Buffer
+			decoded map[string]interface{}
+		)
+
+		audit := NewAudit()
+		audit.sink = &sink
+		wal := NewWAL(NewTranscoder(audit))
+		Expect(snapshotRestore(wal, bytes.NewBuffer(nil),
+			auditEntry("fp1", agent.Quorum_Deploy_FullMethodName, now),
+		)).To(Succeed())
+
+		lines := strings.Split(strings.TrimSpace(sink.String()), "\n")
+		Expect(lines).To(HaveLen(1))
+		Expect(json.Unmarshal([]byte(lines[0]), &decoded)).To(Succeed())
+		Expect(decoded).To(HaveKeyWithValue("fingerprint", "fp1"))
+		Expect(decoded).To(HaveKeyWithValue("sequence", "1"))
+	})
+	It("should page through the entries", func() {
+		audit := NewAudit()
+		wal := NewWAL(NewTranscoder(audit))
+		for i := 0; i < 5; i++ {
+			Expect(audit.Decode(wal.ctx, auditEntry("fp1", agent.Quorum_Deploy_FullMethodName, now))).To(Succeed())
+		}
+
+		first, next := audit.Entries(time.Time{}, "", 0, 2)
+		Expect(first).To(HaveLen(2))
+		Expect(next).To(Equal(uint64(2)))
+
+		second, next := audit.Entries(time.Time{}, "", next, 2)
+		Expect(second).To(HaveLen(2))
+		Expect(second[0].Sequence).To(Equal(uint64(3)))
+		Expect(next).To(Equal(uint64(4)))
+
+		last, next := audit.Entries(time.Time{}, "", next, 2)
+		Expect(last).To(HaveLen(1))
+		Expect(last[0].Sequence).To(Equal(uint64(5)))
+		Expect(next).To(BeZero())
+	})
+
+	It("should only retain the window of exported entries", func() {
+		buf := bytes.NewBuffer(nil)
+		audit := NewAudit()
+		audit.sink = io.Discard
+		wal := NewWAL(NewTranscoder(audit))
+		for i := 0; i < 2*auditRetained; i++ {
+			Expect(audit.Decode(wal.ctx, auditEntry("fp1", agent.Quorum_Deploy_FullMethodName, now))).To(Succeed())
+		}
+
+		Expect(audit.entries).To(HaveLen(auditRetained))
+		Expect(audit.entries[0].Sequence).To(Equal(uint64(auditRetained + 1)))
+
+		s, err := wal.Snapshot()
+		Expect(err).To(Succeed())
+		Expect(s.Persist(NewInmemSnapshotSink(buf))).To(Succeed())
+
+		restored := NewAudit()
+		rwal := NewWAL(NewTranscoder(restored))
+		Expect(rwal.Restore(io.NopCloser(buf))).To(Succeed())
+		Expect(restored.Decode(rwal.ctx, auditEntry("fp2", agent.Quorum_Deploy_FullMethodName, now))).To(Succeed())
+		Expect(restored.entries).To(HaveLen(auditRetained + 1))
+		Expect(agent.VerifyAudit(restored.entries...)).To(Succeed())
+	})
+})
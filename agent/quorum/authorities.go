@@ -0,0 +1,126 @@
+package quorum
+
+import (
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/cryptox"
+)
+
+// NewAuthorities tracks the private keys of the certificate authority generations.
+// the first key applied to the WAL for a generation wins, since every member of quorum
+// applies the WAL in the same order every member retains the same key.
+// keys are sealed with the cluster's secret before they're applied to the WAL, so neither
+// the WAL nor its snapshots contain the plaintext keys.
+func NewAuthorities() *Authorities {
+	return &Authorities{
+		m:    &sync.RWMutex{},
+		keys: make(map[uint64][]byte),
+	}
+}
+
+// Authorities transcoder, retains the sealed keys of the live certificate authority generations.
+// a rotation only ever trusts the latest two generations, older generations are retired and dropped.
+type Authorities struct {
+	m      *sync.RWMutex
+	keys   map[uint64][]byte
+	latest uint64
+	secret []byte
+}
+
+// Decode records the generation's key unless one has already been recorded or the generation was retired.
+func (t *Authorities) Decode(ctx TranscoderContext, m *agent.Message) error {
+	var (
+		a *agent.AuthorityGeneration
+	)
+
+	if a = m.GetAuthority(); a == nil || len(a.Key) == 0 {
+		return nil
+	}
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.retired(a.Generation) {
+		return nil
+	}
+
+	if _, ok := t.keys[a.Generation]; ok {
+		return nil
+	}
+
+	t.keys[a.Generation] = a.Key
+
+	if a.Generation > t.latest {
+		t.latest = a.Generation
+	}
+
+	for g := range t.keys {
+		if t.retired(g) {
+			delete(t.keys, g)
+		}
+	}
+
+	return nil
+}
+
+// Encode the keys of the live generations so they survive compaction of the WAL.
+func (t *Authorities) Encode(dst io.Writer) (err error) {
+	t.m.RLock()
+	defer t.m.RUnlock()
+
+	for g, key := range t.keys {
+		if t.retired(g) {
+			continue
+		}
+
+		if err = encodeProtoTo(dst, agent.NewAuthorityEvent(nil, &agent.AuthorityGeneration{Generation: g, Key: key})); err != nil {
+			return errors.Wrap(err, "unable to encode authorities")
+		}
+	}
+
+	return nil
+}
+
+// Key of the generation sealed with the cluster's secret, nil when the quorum hasn't generated the key.
+func (t *Authorities) Key(g uint64) []byte {
+	t.m.RLock()
+	defer t.m.RUnlock()
+
+	return t.keys[g]
+}
+
+// generatable ensures the quorum only generates keys for the current or next generation,
+// otherwise every request for an arbitrary generation would retain another key.
+func (t *Authorities) generatable(g uint64) error {
+	t.m.RLock()
+	defer t.m.RUnlock()
+
+	if t.retired(g) {
+		return status.Errorf(codes.FailedPrecondition, "authority generation %d has been retired", g)
+	}
+
+	if g > t.latest+1 {
+		return status.Errorf(codes.InvalidArgument, "authority generation %d is beyond the next generation %d", g, t.latest+1)
+	}
+
+	return nil
+}
+
+// seal the key with the cluster's secret.
+func (t *Authorities) seal(key []byte) ([]byte, error) {
+	if len(t.secret) == 0 {
+		return nil, errors.New("authority keys require a cluster secret")
+	}
+
+	return cryptox.Seal(t.secret, key)
+}
+
+func (t *Authorities) retired(g uint64) bool {
+	return g+1 < t.latest
+}
@@ -0,0 +1,125 @@
+package quorum
+
+import (
+	"bytes"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/cryptox"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func authorityGeneration(g uint64, key string) *agent.Message {
+	return agent.NewAuthorityEvent(p, &agent.AuthorityGeneration{Generation: g, Key: []byte(key)})
+}
+
+var _ = Describe("Authorities", func() {
+	It("should retain the first key applied for a generation", func() {
+		authorities := NewAuthorities()
+		wal := NewWAL(NewTranscoder(authorities))
+		Expect(snapshotRestore(wal, bytes.NewBuffer(nil),
+			authorityGeneration(1, "key1"),
+			authorityGeneration(1, "key2"),
+			authorityGeneration(2, "key3"),
+		)).To(Succeed())
+
+		Expect(authorities.Key(1)).To(Equal([]byte("key1")))
+		Expect(authorities.Key(2)).To(Equal([]byte("key3")))
+		Expect(authorities.Key(3)).To(BeNil())
+	})
+
+	It("should survive compaction of the WAL", func() {
+		buf := bytes.NewBuffer(nil)
+		authorities := NewAuthorities()
+		wal := NewWAL(NewTranscoder(authorities))
+		cmds, err := messagesToCommands(
+			authorityGeneration(1, "key1"),
+			authorityGeneration(2, "key2"),
+		)
+		Expect(err).To(Succeed())
+		for _, cmd := range cmds {
+			Expect(wal.decode(wal.ctx, cmd)).To(Succeed())
+		}
+
+		s, err := wal.Snapshot()
+		Expect(err).To(Succeed())
+		Expect(s.Persist(NewInmemSnapshotSink(buf))).To(Succeed())
+
+		restored := NewAuthorities()
+		rwal := NewWAL(NewTranscoder(restored))
+		Expect(rwal.Restore(io.NopCloser(buf))).To(Succeed())
+		Expect(restored.Key(1)).To(Equal([]byte("key1")))
+		Expect(restored.Key(2)).To(Equal([]byte("key2")))
+	})
+
+	It("should drop retired generations", func() {
+		buf := bytes.NewBuffer(nil)
+		authorities := NewAuthorities()
+		wal := NewWAL(NewTranscoder(authorities))
+		Expect(snapshotRestore(wal, buf,
+			authorityGeneration(1, "key1"),
+			authorityGeneration(2, "key2"),
+			authorityGeneration(3, "key3"),
+			authorityGeneration(1, "key4"),
+		)).To(Succeed())
+
+		Expect(authorities.Key(1)).To(BeNil())
+		Expect(authorities.Key(2)).To(Equal([]byte("key2")))
+		Expect(authorities.Key(3)).To(Equal([]byte("key3")))
+
+		s, err := wal.Snapshot()
+		Expect(err).To(Succeed())
+		snapshot := bytes.NewBuffer(nil)
+		Expect(s.Persist(NewInmemSnapshotSink(snapshot))).To(Succeed())
+
+		restored := NewAuthorities()
+		rwal := NewWAL(NewTranscoder(restored))
+		Expect(rwal.Restore(io.NopCloser(snapshot))).To(Succeed())
+		Expect(restored.keys).To(HaveLen(2))
+		Expect(restored.Key(2)).To(Equal([]byte("key2")))
+		Expect(restored.Key(3)).To(Equal([]byte("key3")))
+	})
+
+	It("should only generate the current or next generation", func() {
+		authorities := NewAuthorities()
+		wal := NewWAL(NewTranscoder(authorities))
+		Expect(authorities.generatable(1)).To(Succeed())
+		Expect(status.Code(authorities.generatable(2))).To(Equal(codes.InvalidArgument))
+
+		Expect(snapshotRestore(wal, bytes.NewBuffer(nil),
+			authorityGeneration(1, "key1"),
+			authorityGeneration(2, "key2"),
+			authorityGeneration(3, "key3"),
+		)).To(Succeed())
+
+		Expect(status.Code(authorities.generatable(1))).To(Equal(codes.FailedPrecondition))
+		Expect(authorities.generatable(2)).To(Succeed())
+		Expect(authorities.generatable(4)).To(Succeed())
+		Expect(status.Code(authorities.generatable(5))).To(Equal(codes.InvalidArgument))
+	})
+
+	It("should seal keys with the cluster's secret", func() {
+		authorities := NewAuthorities()
+		_, err := authorities.seal([]byte("key1"))
+		Expect(err).ToNot(Succeed())
+
+		secret := bytes.Repeat([]byte{1}, 32)
+		rotated := bytes.Repeat([]byte{2}, 32)
+		authorities.secret = secret
+		sealed, err := authorities.seal([]byte("key1"))
+		Expect(err).To(Succeed())
+		Expect(sealed).ToNot(ContainSubstring("key1"))
+
+		opened, err := cryptox.Open(sealed, rotated, secret)
+		Expect(err).To(Succeed())
+		Expect(opened).To(Equal([]byte("key1")))
+
+		_, err = cryptox.Open(sealed, rotated)
+		Expect(err).ToNot(Succeed())
+	})
+})
@@ -1,6 +1,7 @@
 package quorum
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"log"
@@ -36,11 +37,25 @@ type deployment struct {
 	deploying            int32                // is a deploy process in progress.
 	runningDeploy        *agent.DeployCommand // currently active deployment.
 	lastSuccessfulDeploy *agent.DeployCommand // used for bootstrapping and recovering when a deploy proxy fails.
+	staged               *agent.Staged        // archive staged on the nodes ahead of its deploy.
 	m                    *sync.RWMutex
 }
 
+// Encode the staged archive so it survives compaction of the WAL.
 func (t *deployment) Encode(dst io.Writer) error {
-	return nil
+	t.m.RLock()
+	defer t.m.RUnlock()
+
+	if t.staged == nil {
+		return nil
+	}
+
+	// the event is only used to restore the state, it shouldn't be retained or observed.
+	m := agent.StagedEvent(nil, t.staged)
+	m.Ephemeral = true
+	m.Hidden = true
+
+	return errors.Wrap(encodeProtoTo(dst, m), "unable to encode staged archive")
 }
 
 func (t *deployment) Decode(ctx TranscoderContext, m *agent.Message) error {
@@ -51,6 +66,11 @@ func (t *deployment) Decode(ctx TranscoderContext, m *agent.Message) error {
 	switch event := m.GetEvent().(type) {
 	case *agent.Message_DeployCommand:
 		dc = event.DeployCommand
+	case *agent.Message_Staged:
+		t.m.Lock()
+		t.staged = event.Staged
+		t.m.Unlock()
+		return nil
 	default:
 		return nil
 	}
@@ -66,6 +86,10 @@ func (t *deployment) Decode(ctx TranscoderContext, m *agent.Message) error {
 
 		t.m.Lock()
 		t.runningDeploy = dc
+		// deploying the staged archive consumes it, prestaging replaces it.
+		if t.staged != nil && (bytes.Equal(t.staged.GetArchive().GetDeploymentID(), dc.GetArchive().GetDeploymentID()) || dc.GetOptions().GetPrestage()) {
+			t.staged = nil
+		}
 		t.m.Unlock()
 	case agent.DeployCommand_Done:
 		atomic.SwapInt32(&t.deploying, none)
@@ -95,6 +119,7 @@ func (t *deployment) getInfo(leader *agent.Peer) agent.InfoResponse {
 		Deployed:  t.lastSuccessfulDeploy,
 		Leader:    leader,
 		Quorum:    agent.QuorumPeers(t.c),
+		Staged:    t.staged,
 	}
 }
 
@@ -0,0 +1,66 @@
+package quorum
+
+import (
+	"bytes"
+
+	"github.com/james-lawrence/bw/agent"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+)
+
+var _ = Describe("Staged", func() {
+	var (
+		d       *deployment
+		wal     WAL
+		staged  *agent.Staged
+		current = &agent.Archive{DeploymentID: []byte("current")}
+		other   = &agent.Archive{DeploymentID: []byte("other")}
+	)
+
+	apply := func(commands ...*agent.Message) {
+		cmds, err := messagesToCommands(commands...)
+		Expect(err).To(Succeed())
+		for _, cmd := range cmds {
+			Expect(wal.decode(wal.ctx, cmd)).To(Succeed())
+		}
+	}
+
+	BeforeEach(func() {
+		d = newDeployment(nil)
+		wal = NewWAL(NewTranscoder(d))
+		staged = &agent.Staged{Archive: current, Initiator: "foo", Peers: []*agent.Peer{p}}
+	})
+
+	It("should survive compaction of the WAL", func() {
+		Expect(snapshotRestore(
+			wal,
+			bytes.NewBuffer(nil),
+			agent.StagedEvent(p, staged),
+			agent.NewDeployCommand(p, agent.DeployCommandBegin("foo", other, &agent.DeployOptions{})),
+			agent.NewDeployCommand(p, agent.DeployCommandDone("foo", other.DeployOption)),
+		)).To(Succeed())
+
+		Expect(proto.Equal(d.staged, staged)).To(BeTrue())
+	})
+
+	It("should be consumed by its deploy", func() {
+		apply(
+			agent.StagedEvent(p, staged),
+			agent.NewDeployCommand(p, agent.DeployCommandBegin("foo", current, &agent.DeployOptions{})),
+		)
+
+		Expect(d.staged).To(BeNil())
+	})
+
+	It("should be replaced by a prestaged deploy", func() {
+		apply(
+			agent.StagedEvent(p, staged),
+			agent.NewDeployCommand(p, agent.DeployCommandBegin("foo", other, &agent.DeployOptions{Prestage: true})),
+		)
+
+		Expect(d.staged).To(BeNil())
+	})
+})
@@ -31,3 +31,15 @@ func (t DisabledMachine) Dispatch(_ context.Context, m ...*agent.Message) (err e
 func (t DisabledMachine) Deploy(ctx context.Context, c cluster, dialer dialers.Defaults, by string, dopts *agent.DeployOptions, a *agent.Archive, peers ...*agent.Peer) (err error) {
 	return status.Error(codes.Unavailable, agent.ErrDisabledMachine.Error())
 }
+
+func (t DisabledMachine) Stage(ctx context.Context, c cluster, dialer dialers.Defaults, by string, a *agent.Archive, peers ...*agent.Peer) (*agent.Staged, error) {
+	return nil, status.Error(codes.Unavailable, agent.ErrDisabledMachine.Error())
+}
+
+func (t DisabledMachine) Record(ctx context.Context, c cluster, e *agent.AuditEntry) error {
+	return status.Error(codes.Unavailable, agent.ErrDisabledMachine.Error())
+}
+
+func (t DisabledMachine) Authority(ctx context.Context, c cluster, a *Authorities, g uint64) ([]byte, error) {
+	return nil, status.Error(codes.Unavailable, agent.ErrDisabledMachine.Error())
+}
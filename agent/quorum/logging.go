@@ -15,10 +15,18 @@ type Logging struct{}
 
 // Decode logs the message received by the state machine.
 func (t Logging) Decode(_ TranscoderContext, m *agent.Message) error {
-	if envx.Boolean(false, bw.EnvLogsQuorum, bw.EnvLogsVerbose) {
-		log.Println("transcoding", spew.Sdump(m))
+	if !envx.Boolean(false, bw.EnvLogsQuorum, bw.EnvLogsVerbose) {
+		return nil
 	}
 
+	// never log the private keys of the certificate authority.
+	if a := m.GetAuthority(); a != nil {
+		log.Println("transcoding authority generation", a.Generation)
+		return nil
+	}
+
+	log.Println("transcoding", spew.Sdump(m))
+
 	return nil
 }
 
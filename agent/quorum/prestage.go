@@ -0,0 +1,63 @@
+package quorum
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/agentutil"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// prestage distributes the archive to every node before any of them begin deploying.
+func prestage(ctx context.Context, local *agent.Peer, d agent.Dispatcher, dialer dialers.Defaults, archive *agent.Archive, nodes ...*agent.Peer) error {
+	var (
+		wg     sync.WaitGroup
+		m      sync.Mutex
+		failed []string
+	)
+
+	errorsx.Log(agentutil.Dispatch(ctx, d, agent.LogEvent(local, fmt.Sprintf("prestaging archive on %d node(s)", len(nodes)))))
+
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n *agent.Peer) {
+			defer wg.Done()
+
+			if err := stage(ctx, dialer, archive, n); err != nil {
+				errorsx.Log(agentutil.Dispatch(ctx, d, agent.LogError(n, errors.Wrap(err, "prestage failed"))))
+				m.Lock()
+				failed = append(failed, n.Name)
+				m.Unlock()
+			}
+		}(n)
+	}
+
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return errors.Errorf("prestage failed on %d node(s): %s", len(failed), strings.Join(failed, ", "))
+	}
+
+	errorsx.Log(agentutil.Dispatch(ctx, d, agent.LogEvent(local, fmt.Sprintf("prestaged archive on %d node(s)", len(nodes)))))
+
+	return nil
+}
+
+func stage(ctx context.Context, dialer dialers.Defaults, archive *agent.Archive, n *agent.Peer) (err error) {
+	var (
+		c *grpc.ClientConn
+	)
+
+	if c, err = dialers.NewDirect(agent.RPCAddress(n)).DialContext(ctx, dialer.Defaults()...); err != nil {
+		return err
+	}
+	defer c.Close()
+
+	return agent.NewConn(c).Stage(ctx, archive)
+}
@@ -79,6 +79,58 @@ func (t *ProxyMachine) Deploy(ctx context.Context, c cluster, dialer dialers.Def
 	return nil
 }
 
+func (t *ProxyMachine) Stage(ctx context.Context, c cluster, dialer dialers.Defaults, by string, a *agent.Archive, peers ...*agent.Peer) (_ *agent.Staged, err error) {
+	var (
+		conn *grpc.ClientConn
+		resp *agent.StageCommandResult
+	)
+
+	if conn, err = t.DialLeader(t.dialer); err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if resp, err = agent.NewQuorumClient(conn).Stage(ctx, &agent.StageCommandRequest{Initiator: by, Archive: a, Peers: peers}); err != nil {
+		return nil, err
+	}
+
+	return resp.Staged, nil
+}
+
+// Record an entry into the audit log.
+func (t *ProxyMachine) Record(ctx context.Context, c cluster, e *agent.AuditEntry) (err error) {
+	var (
+		conn *grpc.ClientConn
+	)
+
+	if conn, err = t.DialLeader(t.dialer); err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = agent.NewQuorumClient(conn).Record(ctx, &agent.AuditRecordRequest{Entry: e})
+	return err
+}
+
+// Authority retrieves the generation's private key from the leader.
+func (t *ProxyMachine) Authority(ctx context.Context, c cluster, a *Authorities, g uint64) (_ []byte, err error) {
+	var (
+		conn *grpc.ClientConn
+		resp *agent.AuthorityResponse
+	)
+
+	if conn, err = t.DialLeader(t.dialer); err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if resp, err = agent.NewQuorumClient(conn).Authority(ctx, &agent.AuthorityRequest{Generation: g}); err != nil {
+		return nil, err
+	}
+
+	return resp.GetAuthority().GetKey(), nil
+}
+
 // Dispatch a message to the WAL.
 func (t *ProxyMachine) Dispatch(ctx context.Context, m ...*agent.Message) (err error) {
 	return t.writeWAL(ctx, m...)
@@ -4,6 +4,7 @@ package quorum
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"hash"
 	"io"
@@ -33,6 +34,9 @@ type stateMachine interface {
 	Leader() *agent.Peer
 	Dispatch(context.Context, ...*agent.Message) error
 	Deploy(ctx context.Context, c cluster, dialer dialers.Defaults, by string, dopts *agent.DeployOptions, a *agent.Archive, peers ...*agent.Peer) error
+	Stage(ctx context.Context, c cluster, dialer dialers.Defaults, by string, a *agent.Archive, peers ...*agent.Peer) (*agent.Staged, error)
+	Record(ctx context.Context, c cluster, e *agent.AuditEntry) error
+	Authority(ctx context.Context, c cluster, a *Authorities, g uint64) ([]byte, error)
 }
 
 type cluster interface {
@@ -61,11 +65,35 @@ func OptionStateMachineDispatch(d stateMachine) Option {
 	}
 }
 
+// OptionAuditSink export new audit entries to the writer as json lines.
+func OptionAuditSink(w io.Writer) Option {
+	return func(q *Quorum) {
+		q.audit.sink = w
+	}
+}
+
+// OptionAuthoritySecret secret used to seal the keys of the certificate authority generations.
+// every agent of the cluster must derive the same secret to open the keys.
+func OptionAuthoritySecret(secret []byte) Option {
+	return func(q *Quorum) {
+		q.authorities.secret = secret
+	}
+}
+
+// OptionChunks resolve chunks referenced by uploads from the index.
+func OptionChunks(idx storage.ChunkIndex) Option {
+	return func(q *Quorum) {
+		q.chunks = idx
+	}
+}
+
 // New new quorum instance based on the options.
 func New(cd agent.ConnectableDispatcher, c cluster, codec transcoder, upload storage.UploadProtocol, rp raftutil.Protocol, options ...Option) Quorum {
 	deployment := newDeployment(c)
 	obs := NewObserver(cd)
 	history := NewHistory()
+	audit := NewAudit()
+	authorities := NewAuthorities()
 	leadershipTransfer := NewLeadershipTransfer(c, rp)
 
 	wal := NewWAL(
@@ -74,6 +102,8 @@ func New(cd agent.ConnectableDispatcher, c cluster, codec transcoder, upload sto
 			codec,
 			obs,
 			history,
+			audit,
+			authorities,
 			leadershipTransfer,
 		),
 	)
@@ -84,6 +114,7 @@ func New(cd agent.ConnectableDispatcher, c cluster, codec transcoder, upload sto
 		wal:                   &wal,
 		sm:                    &DisabledMachine{},
 		uploads:               upload,
+		sessions:              NewUploads(uploadSessionTTL),
 		rp:                    rp,
 		dialer:                dialers.NewQuorum(c, grpc.WithTransportCredentials(insecure.NewCredentials())),
 		m:                     &sync.Mutex{},
@@ -91,6 +122,8 @@ func New(cd agent.ConnectableDispatcher, c cluster, codec transcoder, upload sto
 		lost:                  make(chan struct{}),
 		disconnected:          make(chan struct{}),
 		history:               history,
+		audit:                 audit,
+		authorities:           authorities,
 		leadershipTransfer:    leadershipTransfer,
 	}
 
@@ -108,6 +141,8 @@ type Quorum struct {
 	wal                *WAL
 	sm                 stateMachine
 	uploads            storage.UploadProtocol
+	sessions           *Uploads
+	chunks             storage.ChunkIndex
 	m                  *sync.Mutex
 	c                  cluster
 	dialer             dialers.Defaults
@@ -115,6 +150,8 @@ type Quorum struct {
 	disconnected       chan struct{}
 	rp                 raftutil.Protocol
 	history            History
+	audit              *Audit
+	authorities        *Authorities
 	leadershipTransfer *LeadershipTransfer
 }
 
@@ -198,39 +235,62 @@ func (t *Quorum) Deploy(ctx context.Context, by string, dopts *agent.DeployOptio
 	return t.proxy().Deploy(ctx, t.c, t.dialer, by, dopts, a, peers...)
 }
 
+// Stage the archive on the peers ahead of its deploy.
+func (t *Quorum) Stage(ctx context.Context, by string, a *agent.Archive, peers ...*agent.Peer) (*agent.Staged, error) {
+	return t.proxy().Stage(ctx, t.c, t.dialer, by, a, peers...)
+}
+
 // Upload ...
 func (t *Quorum) Upload(stream agent.Quorum_UploadServer) (err error) {
 	var (
-		checksum hash.Hash
-		location string
-		dst      agent.Uploader
-		chunk    *agent.UploadChunk
+		checksum  hash.Hash
+		location  string
+		u         *upload
+		chunk     *agent.UploadChunk
+		held      map[string]storage.ChunkLocation
+		completed bool
+		failed    bool
 	)
 
 	if chunk, err = stream.Recv(); err != nil {
 		return errors.WithStack(err)
 	}
 
-	metadata := chunk.GetMetadata()
-	if dst, err = t.uploads.NewUpload(metadata.Bytes); err != nil {
+	if u, err = t.sessions.acquire(chunk.GetMetadata(), t.uploads); err != nil {
 		return err
 	}
 
+	// interrupted uploads remain resumable from the last chunk written,
+	// failed uploads are discarded since their contents are unknown.
+	defer func() {
+		if failed {
+			t.sessions.discard(u)
+			return
+		}
+
+		t.sessions.release(u, completed)
+	}()
+
 	for {
 		chunk, err := stream.Recv()
 
 		if err == io.EOF {
-			if checksum, location, err = dst.Info(); err != nil {
+			if checksum, location, err = u.dst.Info(); err != nil {
 				log.Println("error getting archive info", err)
+				failed = true
 				return err
 			}
 
+			completed = true
+
 			tmp := t.c.Local()
 			return stream.SendAndClose(&agent.UploadResponse{
 				Archive: &agent.Archive{
 					Peer:         tmp,
 					Location:     location,
-					Commit:       metadata.Vcscommit,
+					Commit:       u.metadata.Vcscommit,
+					Signature:    u.metadata.Signature,
+					Chunks:       u.metadata.Chunks,
 					Checksum:     checksum.Sum(nil),
 					DeploymentID: checksum.Sum(nil),
 					Ts:           time.Now().UTC().Unix(),
@@ -243,13 +303,87 @@ func (t *Quorum) Upload(stream agent.Quorum_UploadServer) (err error) {
 			return err
 		}
 
-		if _, err = dst.Upload(bytes.NewBuffer(chunk.Data)); err != nil {
+		data := chunk.Data
+		if ref := chunk.GetReference(); ref != nil {
+			if held == nil {
+				if held, err = t.chunkIndex(); err != nil {
+					return err
+				}
+			}
+
+			if data, err = t.chunk(held, ref); err != nil {
+				return err
+			}
+		}
+
+		// a partially written chunk leaves the upload in an unknown state.
+		if _, err = u.dst.Upload(bytes.NewBuffer(data)); err != nil {
 			log.Println("error uploading chunk", err)
+			failed = true
 			return err
 		}
+
+		t.sessions.advance(u, uint64(len(data)))
 	}
 }
 
+// UploadStatus returns the offset an interrupted upload resumes from.
+func (t *Quorum) UploadStatus(ctx context.Context, req *agent.UploadStatusRequest) (*agent.UploadStatusResponse, error) {
+	return &agent.UploadStatusResponse{Offset: t.sessions.Offset(req.Session)}, nil
+}
+
+func (t *Quorum) chunkIndex() (_ map[string]storage.ChunkLocation, err error) {
+	var (
+		held map[string]storage.ChunkLocation
+	)
+
+	if t.chunks == nil {
+		return nil, status.Error(codes.FailedPrecondition, "chunk references are unsupported")
+	}
+
+	if held, err = t.chunks.Index(); err != nil {
+		log.Println("unable to index chunks", err)
+		return nil, status.Error(codes.FailedPrecondition, "unable to index chunks")
+	}
+
+	return held, nil
+}
+
+// chunk resolves a referenced chunk from the local archives.
+func (t *Quorum) chunk(held map[string]storage.ChunkLocation, ref *agent.ArchiveChunk) (_ []byte, err error) {
+	var (
+		buf    []byte
+		digest = hex.EncodeToString(ref.Digest)
+	)
+
+	loc, ok := held[digest]
+	if !ok || loc.Length != int64(ref.Length) {
+		return nil, status.Errorf(codes.FailedPrecondition, "chunk unavailable: %s", digest)
+	}
+
+	if buf, err = storage.ReadChunk(loc, ref.Digest); err != nil {
+		log.Println("unable to read chunk", digest, err)
+		return nil, status.Errorf(codes.FailedPrecondition, "chunk unavailable: %s", digest)
+	}
+
+	return buf, nil
+}
+
+// Audit returns a page of the audit log entries matching the request.
+func (t *Quorum) Audit(ctx context.Context, req *agent.AuditRequest) (_ *agent.AuditResponse, err error) {
+	var (
+		resp agent.AuditResponse
+	)
+
+	if _, err = t.quorumOnly(); err != nil {
+		return nil, errors.Wrap(err, "audit")
+	}
+
+	resp.Entries, resp.Next = t.audit.Entries(time.Unix(req.Since, 0), req.Fingerprint, req.Cursor, int(req.Limit))
+
+	return &resp, nil
+}
+
 // Watch watch for events.
 func (t *Quorum) History(context.Context) (_ []*agent.Message, err error) {
 	if _, err = t.quorumOnly(); err != nil {
@@ -319,6 +453,21 @@ func (t *Quorum) Dispatch(ctx context.Context, m ...*agent.Message) (err error)
 	return nil
 }
 
+// Record an entry into the audit log.
+func (t *Quorum) Record(ctx context.Context, e *agent.AuditEntry) (err error) {
+	return t.proxy().Record(ctx, t.c, e)
+}
+
+// Authority sealed private key of the certificate authority generation, generated by the quorum
+// the first time its requested.
+func (t *Quorum) Authority(ctx context.Context, g uint64) (_ []byte, err error) {
+	if key := t.authorities.Key(g); key != nil {
+		return key, nil
+	}
+
+	return t.proxy().Authority(ctx, t.c, t.authorities, g)
+}
+
 func (t *Quorum) proxy() stateMachine {
 	t.m.Lock()
 	defer t.m.Unlock()
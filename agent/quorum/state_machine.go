@@ -14,6 +14,8 @@ import (
 	deployments "github.com/james-lawrence/bw/deployment"
 	"github.com/james-lawrence/bw/internal/envx"
 	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/rsax"
+	"github.com/james-lawrence/bw/internal/timex"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/proto"
@@ -68,7 +70,7 @@ func (t *StateMachine) Deploy(ctx context.Context, c cluster, dialer dialers.Def
 	qd := dialers.NewQuorum(c, dialer.Defaults()...)
 	d := agentutil.NewDispatcher(qd)
 
-	cmd := agent.DeployCommandBegin(by, archive, dopts)
+	cmd := agent.DeployCommandBegin(by, archive, dopts, agent.DeployCommandOptionPeers(peers...))
 
 	if err = d.Dispatch(ctx, agent.NewDeployCommand(c.Local(), cmd)); err != nil {
 		return err
@@ -95,9 +97,30 @@ func (t *StateMachine) Deploy(ctx context.Context, c cluster, dialer dialers.Def
 
 	// At this point the deploy could take awhile, so we shunt it into the background.
 	go func() {
-		dcmd := agent.DeployCommandFailed(by, archive.DeployOption, dopts.DeployOption)
-		if _, success := deployments.RunDeploy(c.Local(), c, d, options...); success {
-			dcmd = agent.DeployCommandDone(by, archive.DeployOption, dopts.DeployOption)
+		// prestaging has its own timeout, the deploy's timeout applies to the deploy of each node.
+		if dopts.Prestage {
+			ctx, done := context.WithTimeout(context.Background(), timex.DurationOrDefault(time.Duration(dopts.PrestageTimeout), bw.DefaultPrestageTimeout))
+			cause := prestage(ctx, c.Local(), d, dialer, archive, deployments.ApplyFilter(filter, c.Peers()...)...)
+			done()
+
+			// nodes that failed to stage the archive retrieve it during the deploy.
+			if cause != nil && !dopts.IgnoreFailures {
+				dcmd := agent.DeployCommandFailed(by, archive.DeployOption, dopts.DeployOption, agent.DeployCommandOptionPeers(peers...), agent.DeployCommandOptionError(cause))
+				errorsx.Log(d.Dispatch(context.Background(), agent.NewDeployCommand(c.Local(), dcmd)))
+				return
+			}
+		}
+
+		failures, success := deployments.RunDeploy(c.Local(), c, d, options...)
+		dcmd := agent.DeployCommandFailed(
+			by,
+			archive.DeployOption,
+			dopts.DeployOption,
+			agent.DeployCommandOptionPeers(peers...),
+			agent.DeployCommandOptionError(errors.Errorf("deploy failed on %d node(s)", failures)),
+		)
+		if success {
+			dcmd = agent.DeployCommandDone(by, archive.DeployOption, dopts.DeployOption, agent.DeployCommandOptionPeers(peers...))
 		}
 
 		if envx.Boolean(false, bw.EnvLogsDeploy, bw.EnvLogsVerbose) {
@@ -109,6 +132,40 @@ func (t *StateMachine) Deploy(ctx context.Context, c cluster, dialer dialers.Def
 	return nil
 }
 
+// Stage distributes the archive to the peers ahead of its deploy. the staged archive
+// is only recorded once every peer has staged it.
+func (t *StateMachine) Stage(ctx context.Context, c cluster, dialer dialers.Defaults, by string, archive *agent.Archive, peers ...*agent.Peer) (s *agent.Staged, err error) {
+	var (
+		filter deployments.Filter
+	)
+
+	qd := dialers.NewQuorum(c, dialer.Defaults()...)
+	d := agentutil.NewDispatcher(qd)
+
+	filter = deployments.AlwaysMatch
+	if len(peers) > 0 {
+		filter = deployments.Peers(peers...)
+	}
+
+	nodes := deployments.ApplyFilter(filter, c.Peers()...)
+	if err = prestage(ctx, c.Local(), d, dialer, archive, nodes...); err != nil {
+		return nil, err
+	}
+
+	s = &agent.Staged{
+		Archive:   archive,
+		Initiator: by,
+		Peers:     nodes,
+		Ts:        time.Now().UTC().Unix(),
+	}
+
+	if err = d.Dispatch(ctx, agent.StagedEvent(c.Local(), s)); err != nil {
+		return nil, errors.Wrap(err, "unable to record staged archive")
+	}
+
+	return s, nil
+}
+
 func check(d dialers.Defaults) func(ctx context.Context, n *agent.Peer) (*agent.Deploy, error) {
 	return func(ctx context.Context, n *agent.Peer) (_d *agent.Deploy, err error) {
 		var (
@@ -162,6 +219,45 @@ func (t *StateMachine) Dispatch(ctx context.Context, messages ...*agent.Message)
 	return nil
 }
 
+// Record an entry into the audit log, the entry is chained as its applied to the WAL.
+func (t *StateMachine) Record(ctx context.Context, c cluster, e *agent.AuditEntry) (err error) {
+	return t.Dispatch(ctx, agent.NewAuditEvent(c.Local(), e))
+}
+
+// Authority generates the generation's private key unless the quorum already has one.
+// the key is sealed with the cluster's secret and retained by the authorities once its applied to the WAL.
+func (t *StateMachine) Authority(ctx context.Context, c cluster, a *Authorities, g uint64) (_ []byte, err error) {
+	var (
+		generated []byte
+	)
+
+	if key := a.Key(g); key != nil {
+		return key, nil
+	}
+
+	if err = a.generatable(g); err != nil {
+		return nil, err
+	}
+
+	if generated, err = rsax.Auto(); err != nil {
+		return nil, errors.Wrap(err, "unable to generate authority key")
+	}
+
+	if generated, err = a.seal(generated); err != nil {
+		return nil, errors.Wrap(err, "unable to seal authority key")
+	}
+
+	if err = t.Dispatch(ctx, agent.NewAuthorityEvent(c.Local(), &agent.AuthorityGeneration{Generation: g, Key: generated})); err != nil {
+		return nil, err
+	}
+
+	if key := a.Key(g); key != nil {
+		return key, nil
+	}
+
+	return nil, errors.Errorf("authority key for generation %d was not recorded", g)
+}
+
 func (t *StateMachine) writeWAL(m *agent.Message, d time.Duration) (err error) {
 	var (
 		encoded []byte
@@ -0,0 +1,152 @@
+package quorum
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/storage"
+)
+
+// uploadSessionTTL duration an interrupted upload remains resumable.
+const uploadSessionTTL = time.Hour
+
+// NewUploads tracks interrupted uploads so they can be resumed.
+// sessions live in the memory of the quorum node receiving the upload, they are not
+// replicated. an upload retried against another member of quorum, or after a restart,
+// is unknown to the node and restarts from the beginning.
+func NewUploads(ttl time.Duration) *Uploads {
+	return &Uploads{
+		m:        &sync.Mutex{},
+		ttl:      ttl,
+		sessions: make(map[string]*upload),
+	}
+}
+
+// Uploads resumable upload sessions.
+type Uploads struct {
+	m        *sync.Mutex
+	ttl      time.Duration
+	sessions map[string]*upload
+}
+
+type upload struct {
+	id       string
+	dst      agent.Uploader
+	metadata *agent.UploadMetadata
+	offset   uint64
+	active   bool
+	updated  time.Time
+}
+
+// Offset of the session, zero when the session is unknown.
+func (t *Uploads) Offset(id string) uint64 {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.expire()
+
+	if u, ok := t.sessions[id]; ok {
+		return u.offset
+	}
+
+	return 0
+}
+
+// acquire the upload for the metadata, creating a new upload when the metadata starts
+// from the beginning of the archive. the upload must be released once the stream completes.
+func (t *Uploads) acquire(metadata *agent.UploadMetadata, uploads storage.UploadProtocol) (u *upload, err error) {
+	var (
+		dst agent.Uploader
+	)
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.expire()
+
+	if u, ok := t.sessions[metadata.Session]; ok {
+		if u.active {
+			return nil, status.Errorf(codes.Aborted, "upload %s is in progress", metadata.Session)
+		}
+
+		if u.offset != metadata.Offset {
+			return nil, status.Errorf(codes.OutOfRange, "upload %s is at offset %d not %d", metadata.Session, u.offset, metadata.Offset)
+		}
+
+		u.active = true
+		return u, nil
+	}
+
+	if metadata.Offset != 0 {
+		return nil, status.Errorf(codes.OutOfRange, "upload %s not found, it must restart from the beginning", metadata.Session)
+	}
+
+	if dst, err = uploads.NewUpload(metadata.Bytes); err != nil {
+		return nil, err
+	}
+
+	u = &upload{id: metadata.Session, dst: dst, metadata: metadata, active: true}
+
+	// uploads without a session are not resumable.
+	if u.id != "" {
+		t.sessions[u.id] = u
+	}
+
+	return u, nil
+}
+
+// advance the offset of the upload once a chunk is written.
+func (t *Uploads) advance(u *upload, n uint64) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	u.offset += n
+}
+
+// release the upload, completed uploads are forgotten. interrupted uploads without a session
+// can never be resumed and are aborted.
+func (t *Uploads) release(u *upload, completed bool) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	u.active = false
+	u.updated = time.Now()
+
+	if completed {
+		delete(t.sessions, u.id)
+		return
+	}
+
+	if u.id == "" {
+		u.abort()
+	}
+}
+
+// discard the upload, its partially written data is removed and the session forgotten.
+func (t *Uploads) discard(u *upload) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	u.active = false
+	delete(t.sessions, u.id)
+	u.abort()
+}
+
+func (t *Uploads) expire() {
+	for id, u := range t.sessions {
+		if !u.active && time.Since(u.updated) > t.ttl {
+			delete(t.sessions, id)
+			u.abort()
+		}
+	}
+}
+
+func (t *upload) abort() {
+	if err := t.dst.Abort(); err != nil {
+		log.Println("unable to remove abandoned upload", t.id, err)
+	}
+}
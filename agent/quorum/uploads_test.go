@@ -0,0 +1,113 @@
+package quorum
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/grpcx"
+	"github.com/james-lawrence/bw/storage"
+	"google.golang.org/grpc/codes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type countingUploads struct {
+	created *int
+	aborted *int
+}
+
+func (t countingUploads) NewUpload(uint64) (storage.Uploader, error) {
+	*t.created++
+	return abortingUpload{Uploader: storage.NewNoopUpload(nil), aborted: t.aborted}, nil
+}
+
+type abortingUpload struct {
+	storage.Uploader
+	aborted *int
+}
+
+func (t abortingUpload) Abort() error {
+	*t.aborted++
+	return nil
+}
+
+var _ = Describe("Uploads", func() {
+	var (
+		created int
+		aborted int
+		uploads storage.UploadProtocol
+	)
+
+	BeforeEach(func() {
+		created = 0
+		aborted = 0
+		uploads = countingUploads{created: &created, aborted: &aborted}
+	})
+
+	It("should resume interrupted uploads from their offset", func() {
+		u := NewUploads(time.Hour)
+		s, err := u.acquire(&agent.UploadMetadata{Session: "s1"}, uploads)
+		Expect(err).To(Succeed())
+		u.advance(s, 10)
+		u.release(s, false)
+		Expect(u.Offset("s1")).To(Equal(uint64(10)))
+
+		_, err = u.acquire(&agent.UploadMetadata{Session: "s1", Offset: 5}, uploads)
+		Expect(grpcx.IsCode(err, codes.OutOfRange)).To(BeTrue())
+
+		resumed, err := u.acquire(&agent.UploadMetadata{Session: "s1", Offset: 10}, uploads)
+		Expect(err).To(Succeed())
+		Expect(resumed).To(BeIdenticalTo(s))
+		Expect(created).To(Equal(1))
+
+		_, err = u.acquire(&agent.UploadMetadata{Session: "s1", Offset: 10}, uploads)
+		Expect(grpcx.IsCode(err, codes.Aborted)).To(BeTrue())
+
+		u.release(resumed, true)
+		Expect(u.Offset("s1")).To(BeZero())
+		Expect(aborted).To(BeZero())
+	})
+
+	It("should require unknown uploads to start from the beginning", func() {
+		_, err := NewUploads(time.Hour).acquire(&agent.UploadMetadata{Session: "s1", Offset: 10}, uploads)
+		Expect(grpcx.IsCode(err, codes.OutOfRange)).To(BeTrue())
+	})
+
+	It("should expire interrupted uploads", func() {
+		u := NewUploads(time.Millisecond)
+		s, err := u.acquire(&agent.UploadMetadata{Session: "s1"}, uploads)
+		Expect(err).To(Succeed())
+		u.advance(s, 10)
+		u.release(s, false)
+		Eventually(func() uint64 { return u.Offset("s1") }).Should(BeZero())
+		Expect(aborted).To(Equal(1))
+	})
+
+	It("should abort discarded uploads", func() {
+		u := NewUploads(time.Hour)
+		s, err := u.acquire(&agent.UploadMetadata{Session: "s1"}, uploads)
+		Expect(err).To(Succeed())
+		u.advance(s, 10)
+		u.discard(s)
+		Expect(u.Offset("s1")).To(BeZero())
+		Expect(aborted).To(Equal(1))
+	})
+
+	It("should abort interrupted uploads without a session", func() {
+		u := NewUploads(time.Hour)
+		s, err := u.acquire(&agent.UploadMetadata{}, uploads)
+		Expect(err).To(Succeed())
+		u.release(s, false)
+		Expect(aborted).To(Equal(1))
+	})
+
+	It("should not track uploads without a session", func() {
+		u := NewUploads(time.Hour)
+		s, err := u.acquire(&agent.UploadMetadata{}, uploads)
+		Expect(err).To(Succeed())
+		Expect(s.dst.Upload(bytes.NewBufferString("hello"))).Error().To(Succeed())
+		Expect(u.sessions).To(BeEmpty())
+	})
+})
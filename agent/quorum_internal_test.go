@@ -0,0 +1,132 @@
+package agent
+
+import (
+	"context"
+	"net"
+
+	"github.com/hashicorp/memberlist"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type staticmembers []*memberlist.Node
+
+func (t staticmembers) Members() []*memberlist.Node {
+	return t
+}
+
+var _ = Describe("Quorum.resolve", func() {
+	staging := NewPeer("staging-1", PeerOptionIP([]byte{10, 0, 0, 1}), PeerOptionLabels(map[string]string{"environment": "staging"}))
+	production := NewPeer("production-1", PeerOptionIP([]byte{10, 0, 1, 1}), PeerOptionLabels(map[string]string{"environment": "production"}))
+	q := NewQuorum(nil, staticmembers(PeersToNodes(staging, production)), noauth{})
+
+	It("should resolve the entire cluster when no peers are requested", func() {
+		resolved := q.resolve()
+		Expect(resolved).To(HaveLen(2))
+		Expect(resolved[0].GetLabels()).To(Equal(staging.Labels))
+		Expect(resolved[1].GetLabels()).To(Equal(production.Labels))
+	})
+
+	It("should use the cluster's labels for the requested peers", func() {
+		forged := NewPeer("production-1", PeerOptionIP([]byte{10, 0, 1, 1}), PeerOptionLabels(map[string]string{"environment": "staging"}))
+		resolved := q.resolve(forged)
+		Expect(resolved).To(HaveLen(1))
+		Expect(resolved[0].GetLabels()).To(Equal(production.Labels))
+	})
+})
+
+type deployauth struct {
+	noauth
+}
+
+func (t deployauth) Deploy(context.Context) error {
+	return nil
+}
+
+// scopedauth only permits deploys to the peers within the environment.
+type scopedauth struct {
+	deployauth
+	environment string
+}
+
+func (t scopedauth) DeployTo(ctx context.Context, peers ...scopedPeer) error {
+	for _, p := range peers {
+		if p.GetLabels()["environment"] != t.environment {
+			return status.Error(codes.PermissionDenied, "outside of the permitted scope")
+		}
+	}
+
+	return nil
+}
+
+var _ = Describe("Quorum.Dispatch", func() {
+	It("should reject audit events", func() {
+		q := NewQuorum(nil, staticmembers(nil), deployauth{})
+		_, err := q.Dispatch(context.Background(), &DispatchRequest{Messages: []*Message{
+			NewAuditEvent(nil, &AuditEntry{Sequence: 1, Fingerprint: "forged"}),
+		}})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("should reject authority events", func() {
+		q := NewQuorum(nil, staticmembers(nil), deployauth{})
+		_, err := q.Dispatch(context.Background(), &DispatchRequest{Messages: []*Message{
+			NewAuthorityEvent(nil, &AuthorityGeneration{Generation: 1, Key: []byte("forged")}),
+		}})
+		Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+	})
+
+	It("should only permit scoped permissions to dispatch logs of peers within the scope", func() {
+		staging := NewPeer("staging-1", PeerOptionIP([]byte{10, 0, 0, 1}), PeerOptionLabels(map[string]string{"environment": "staging"}))
+		production := NewPeer("production-1", PeerOptionIP([]byte{10, 0, 1, 1}), PeerOptionLabels(map[string]string{"environment": "production"}))
+		q := NewQuorum(nil, staticmembers(PeersToNodes(staging, production)), scopedauth{environment: "staging"})
+
+		for _, m := range []*Message{
+			LogEvent(production, "forged"),
+			StagedEvent(staging, &Staged{Archive: &Archive{}, Peers: []*Peer{staging}}),
+			NewDeployCommand(staging, DeployCommandCancel("forged")),
+		} {
+			_, err := q.Dispatch(context.Background(), &DispatchRequest{Messages: []*Message{m}})
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		}
+	})
+})
+
+var _ = Describe("Quorum.Record", func() {
+	It("should only permit agents", func() {
+		q := NewQuorum(nil, staticmembers(nil), deployauth{})
+		_, err := q.Record(context.Background(), &AuditRecordRequest{Entry: &AuditEntry{Fingerprint: "forged"}})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	})
+})
+
+type agentauth struct {
+	noauth
+}
+
+func (t agentauth) Agent(context.Context) error {
+	return nil
+}
+
+var _ = Describe("Quorum.Authority", func() {
+	It("should only permit agents", func() {
+		q := NewQuorum(nil, staticmembers(nil), deployauth{})
+		_, err := q.Authority(context.Background(), &AuthorityRequest{Generation: 1})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	})
+
+	It("should only permit agents connecting from a member of the cluster", func() {
+		member := NewPeer("member-1", PeerOptionIP([]byte{10, 0, 0, 1}))
+		q := NewQuorum(nil, staticmembers(PeersToNodes(member)), agentauth{})
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 9), Port: 2000}})
+		_, err := q.Authority(ctx, &AuthorityRequest{Generation: 1})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+
+		_, err = q.Authority(context.Background(), &AuthorityRequest{Generation: 1})
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	})
+})
@@ -13,6 +13,8 @@ import (
 	"github.com/james-lawrence/bw/internal/iox"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type connector interface {
@@ -24,11 +26,14 @@ type connector interface {
 type deployer interface {
 	// Deploy trigger a deploy
 	Deploy(context.Context, string, *DeployOptions, *Archive) (*Deploy, error)
+	// Stage retrieve the archive ahead of its deploy.
+	Stage(context.Context, *Archive) error
 	// Cancel current deploy
 	Cancel()
 	Reset() error
 	Deployments() ([]*Deploy, error)
 	Logs([]byte) io.ReadCloser
+	SearchLogs(context.Context, *LogSearchRequest, func(*LogSearchMatch) error) error
 }
 
 type noopDeployer struct{}
@@ -37,6 +42,8 @@ func (t noopDeployer) Deploy(context.Context, string, *DeployOptions, *Archive)
 	return &Deploy{}, err
 }
 
+func (t noopDeployer) Stage(context.Context, *Archive) error { return nil }
+
 func (t noopDeployer) Reset() error { return nil }
 func (t noopDeployer) Cancel()      {}
 
@@ -48,6 +55,20 @@ func (t noopDeployer) Logs(deploymentID []byte) io.ReadCloser {
 	return io.NopCloser(strings.NewReader(fmt.Sprintf("INFO: %s", string(deploymentID))))
 }
 
+func (t noopDeployer) SearchLogs(context.Context, *LogSearchRequest, func(*LogSearchMatch) error) error {
+	return nil
+}
+
+// reports the tls credentials of the agent.
+type tlsinspector interface {
+	TLSStatus() (*TLSStatusResponse, error)
+}
+
+// removes the archives of the agent's storage that are no longer retained.
+type collector interface {
+	GC(dryrun bool) (*StorageGCResponse, error)
+}
+
 // ServerOption ...
 type ServerOption func(*Server)
 
@@ -81,6 +102,20 @@ func ServerOptionAuth(a auth) ServerOption {
 	}
 }
 
+// ServerOptionTLS inspector used to report the tls credentials of the agent.
+func ServerOptionTLS(i tlsinspector) ServerOption {
+	return func(s *Server) {
+		s.tls = i
+	}
+}
+
+// ServerOptionStorage collector used to garbage collect the archives held by the agent.
+func ServerOptionStorage(c collector) ServerOption {
+	return func(s *Server) {
+		s.storage = c
+	}
+}
+
 // NewServer ...
 func NewServer(c connector, options ...ServerOption) Server {
 	s := Server{
@@ -106,6 +141,8 @@ type Server struct {
 	shutdown  context.CancelFunc
 	Deployer  deployer
 	connector connector
+	tls       tlsinspector
+	storage   collector
 }
 
 // Bind to a grpc server.
@@ -116,7 +153,7 @@ func (t Server) Bind(srv *grpc.Server) Server {
 
 // Shutdown when invoked the agent will self shutdown.
 func (t Server) Shutdown(ctx context.Context, req *ShutdownRequest) (*ShutdownResponse, error) {
-	if err := t.auth.Deploy(ctx); err != nil {
+	if err := t.auth.Restart(ctx); err != nil {
 		return nil, err
 	}
 
@@ -135,7 +172,7 @@ func (t Server) Deploy(ctx context.Context, dreq *DeployRequest) (*DeployRespons
 		d   *Deploy
 	)
 
-	if err := t.auth.Deploy(ctx); err != nil {
+	if err := t.auth.DeployTo(ctx, t.connector.Local()); err != nil {
 		return nil, err
 	}
 
@@ -148,7 +185,7 @@ func (t Server) Deploy(ctx context.Context, dreq *DeployRequest) (*DeployRespons
 
 // Cancel ...
 func (t Server) Cancel(ctx context.Context, req *CancelRequest) (_ *CancelResponse, err error) {
-	if err := t.auth.Deploy(ctx); err != nil {
+	if err := t.auth.DeployTo(ctx, t.connector.Local()); err != nil {
 		return nil, err
 	}
 
@@ -191,7 +228,7 @@ func (t Server) Connect(ctx context.Context, _ *ConnectRequest) (_zeror *Connect
 
 // Logs retrieve logs for the given deploy.
 func (t Server) Logs(req *LogRequest, out Agent_LogsServer) (err error) {
-	if err := t.auth.Deploy(out.Context()); err != nil {
+	if err := t.auth.DeployTo(out.Context(), t.connector.Local()); err != nil {
 		return err
 	}
 
@@ -209,3 +246,71 @@ func (t Server) Logs(req *LogRequest, out Agent_LogsServer) (err error) {
 
 	return errorsx.Compact(iox.IgnoreEOF(err), logs.Close())
 }
+
+// SearchLogs search the deploy logs of the agent for lines matching the pattern.
+func (t Server) SearchLogs(req *LogSearchRequest, out Agent_SearchLogsServer) (err error) {
+	if err := t.auth.DeployTo(out.Context(), t.connector.Local()); err != nil {
+		return err
+	}
+
+	local := t.connector.Local()
+	return t.Deployer.SearchLogs(out.Context(), req, func(m *LogSearchMatch) error {
+		m.Peer = local
+		return out.Send(m)
+	})
+}
+
+// TLSStatus reports the tls credentials of the agent.
+func (t Server) TLSStatus(ctx context.Context, _ *TLSStatusRequest) (s *TLSStatusResponse, err error) {
+	if err := t.auth.Deploy(ctx); err != nil {
+		return nil, err
+	}
+
+	if t.tls == nil {
+		return nil, status.Error(codes.Unimplemented, "tls status is not available")
+	}
+
+	if s, err = t.tls.TLSStatus(); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.Peer = t.connector.Local()
+
+	return s, nil
+}
+
+// StorageGC removes the archives held by the agent that are no longer retained.
+func (t Server) StorageGC(ctx context.Context, req *StorageGCRequest) (s *StorageGCResponse, err error) {
+	if err := t.auth.DeployTo(ctx, t.connector.Local()); err != nil {
+		return nil, err
+	}
+
+	if t.storage == nil {
+		return nil, status.Error(codes.Unimplemented, "storage garbage collection is not available")
+	}
+
+	if s, err = t.storage.GC(req.DryRun); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	s.Peer = t.connector.Local()
+
+	return s, nil
+}
+
+// Stage retrieves the archive ahead of its deploy.
+func (t Server) Stage(ctx context.Context, req *StageRequest) (_ *StageResponse, err error) {
+	if err := t.auth.DeployTo(ctx, t.connector.Local()); err != nil {
+		return nil, err
+	}
+
+	if req.Archive == nil {
+		return nil, status.Error(codes.InvalidArgument, "archive is required")
+	}
+
+	if err = t.Deployer.Stage(ctx, req.Archive); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &StageResponse{}, nil
+}
@@ -9,8 +9,12 @@ import (
 	"github.com/james-lawrence/bw/cluster"
 	"github.com/james-lawrence/bw/clustering"
 	"github.com/james-lawrence/bw/clustering/clusteringtestutil"
+	"github.com/james-lawrence/bw/notary"
+	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/icrowley/fake"
@@ -24,6 +28,34 @@ func (t testauth) Deploy(ctx context.Context) error {
 	return nil
 }
 
+func (t testauth) Restart(ctx context.Context) error {
+	return nil
+}
+
+func (t testauth) DeployTo(ctx context.Context, peers ...notary.ScopedPeer) error {
+	return nil
+}
+
+func (t testauth) Agent(ctx context.Context) error {
+	return nil
+}
+
+// scopedauth only permits deploys to the peers within the scope.
+type scopedauth struct {
+	testauth
+	scope *notary.Scope
+}
+
+func (t scopedauth) DeployTo(ctx context.Context, peers ...notary.ScopedPeer) error {
+	for _, p := range peers {
+		if !t.scope.Match(p) {
+			return status.Error(codes.PermissionDenied, "outside of the permitted scope")
+		}
+	}
+
+	return nil
+}
+
 type harness struct {
 	client   Client
 	cluster  cluster.Cluster
@@ -34,7 +66,7 @@ func (t harness) Cleanup() {
 	Expect(t.listener.Close()).ToNot(HaveOccurred())
 }
 
-func testClient() harness {
+func testClient(options ...ServerOption) harness {
 	socket, err := net.Listen("tcp", ":0")
 	Expect(err).ToNot(HaveOccurred())
 	peers := clusteringtestutil.NewNodes(5)
@@ -43,7 +75,7 @@ func testClient() harness {
 		NewPeer(fake.CharactersN(10)),
 		clustering.NewMock(peers[0], peers[1:]...),
 	)
-	s := NewServer(c, ServerOptionAuth(testauth{}))
+	s := NewServer(c, append([]ServerOption{ServerOptionAuth(testauth{})}, options...)...)
 
 	grpcs := grpc.NewServer()
 	RegisterAgentServer(grpcs, s)
@@ -105,4 +137,24 @@ var _ = Describe("Server", func() {
 			Expect(string(raw)).To(Equal("INFO: fake"))
 		})
 	})
+
+	Context("Scope", func() {
+		It("should refuse requests outside of the permitted scope", func() {
+			h := testClient(ServerOptionAuth(scopedauth{scope: &notary.Scope{Labels: []string{"env=production"}}}))
+			defer h.Cleanup()
+
+			denied := func(err error) {
+				Expect(status.Code(errors.Cause(err))).To(Equal(codes.PermissionDenied))
+			}
+
+			rpc := NewAgentClient(h.client.Conn())
+			_, err := rpc.Stage(context.Background(), &StageRequest{Archive: &Archive{}})
+			denied(err)
+			_, err = rpc.StorageGC(context.Background(), &StorageGCRequest{DryRun: true})
+			denied(err)
+			denied(h.client.SearchLogs(context.Background(), &LogSearchRequest{Pattern: "fake"}, func(*LogSearchMatch) error { return nil }))
+			_, err = io.ReadAll(h.client.Logs(context.Background(), h.cluster.Local(), []byte("fake")))
+			denied(err)
+		})
+	})
 })
@@ -108,6 +108,19 @@ func (t *Dispatcher) Dispatch(ctx context.Context, m ...*agent.Message) (err err
 	return t.dropClient(c, c.Dispatch(ctx, m...))
 }
 
+// Record an entry into the audit log.
+func (t *Dispatcher) Record(ctx context.Context, e *agent.AuditEntry) (err error) {
+	var (
+		c agent.Client
+	)
+
+	if c, err = t.getClient(ctx); err != nil {
+		return err
+	}
+
+	return t.dropClient(c, c.Record(ctx, e))
+}
+
 func (t *Dispatcher) getClient(ctx context.Context) (c agent.Client, err error) {
 	t.m.Lock()
 	defer t.m.Unlock()
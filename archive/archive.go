@@ -2,62 +2,137 @@ package archive
 
 import (
 	"archive/tar"
-	"compress/gzip"
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
-// Pack ...
+// Pack the paths into a gzip compressed tar archive using the default packer.
 func Pack(dst io.Writer, paths ...string) (err error) {
+	return NewPacker().Pack(dst, paths...)
+}
+
+// PackOption options for packing archives.
+type PackOption func(*Packer)
+
+// PackOptionCompression the compression algorithm and level of the archive, a level
+// of zero uses the default level of the algorithm.
+func PackOptionCompression(algorithm string, level int) PackOption {
+	return func(p *Packer) {
+		p.compression = algorithm
+		p.level = level
+	}
+}
+
+// PackOptionReproducible normalizes the metadata of the archive's entries, identical
+// contents produce identical archives.
+func PackOptionReproducible(b bool) PackOption {
+	return func(p *Packer) {
+		p.reproducible = b
+	}
+}
+
+// PackOptionProgress reports the number of bytes of file contents archived
+// as they're written. see Size for the total.
+func PackOptionProgress(fn func(n int64)) PackOption {
+	return func(p *Packer) {
+		p.progress = fn
+	}
+}
+
+// NewPacker packs directories into archives.
+func NewPacker(options ...PackOption) Packer {
+	p := Packer{
+		compression: CompressionGzip,
+		progress:    func(int64) {},
+	}
+
+	for _, opt := range options {
+		opt(&p)
+	}
+
+	return p
+}
+
+// Packer packs directories into tar archives. the archive is compressed in
+// content defined chunks, see Chunks.
+type Packer struct {
+	compression  string
+	level        int
+	reproducible bool
+	progress     func(int64)
+}
+
+// Pack the paths into the destination. entries are written in lexical order,
+// paths matched by the IgnoreFile of each path are excluded.
+func (t Packer) Pack(dst io.Writer, paths ...string) (err error) {
 	var (
-		gw *gzip.Writer
+		c  compressor
+		cw *chunkWriter
 		tw *tar.Writer
 	)
 
-	gw = gzip.NewWriter(dst)
-	defer gw.Close()
-	tw = tar.NewWriter(gw)
-	defer tw.Close()
+	if c, err = newCompressor(t.compression, t.level); err != nil {
+		return err
+	}
+
+	cw = newChunkWriter(dst, c)
+	tw = tar.NewWriter(cw)
 
 	for _, basepath := range paths {
-		walker := func(path string, info os.FileInfo, err error) error {
-			if err != nil {
-				return err
-			}
+		walker := func(path string, info os.FileInfo) error {
+			return t.write(basepath, path, tw, info)
+		}
 
-			// skip the root directory itself.
-			if basepath == path && info.IsDir() {
-				return nil
+		if err = walk(basepath, walker); err != nil {
+			return err
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return errors.Wrap(err, "failed to flush archive")
+	}
+
+	return errors.Wrap(cw.Close(), "failed to flush archive")
+}
+
+// Size of the file contents within the paths, excluding ignored paths.
+func Size(paths ...string) (n int64, err error) {
+	for _, basepath := range paths {
+		walker := func(path string, info os.FileInfo) error {
+			if info.Mode().IsRegular() {
+				n += info.Size()
 			}
 
-			return write(basepath, path, tw, info)
+			return nil
 		}
 
-		if err = filepath.Walk(basepath, walker); err != nil {
-			return err
+		if err = walk(basepath, walker); err != nil {
+			return 0, err
 		}
 	}
 
-	return errors.Wrap(tw.Flush(), "failed to flush archive")
+	return n, nil
 }
 
 // Unpack unpacks the archive from the reader into the root directory.
 func Unpack(root string, r io.Reader) (err error) {
 	var (
 		dst *os.File
-		gzr *gzip.Reader
+		dec io.ReadCloser
 		tr  *tar.Reader
 	)
 
-	if gzr, err = gzip.NewReader(r); err != nil {
-		return errors.Wrap(err, "failed to create gzip reader")
+	// the compression is detected from the archive.
+	if dec, err = decompress(r); err != nil {
+		return err
 	}
-	defer gzr.Close()
+	defer dec.Close()
 
-	tr = tar.NewReader(gzr)
+	tr = tar.NewReader(dec)
 
 	for {
 		header, err := tr.Next()
@@ -110,7 +185,7 @@ func Unpack(root string, r io.Reader) (err error) {
 	}
 }
 
-func write(basepath, path string, tw *tar.Writer, info os.FileInfo) (err error) {
+func (t Packer) write(basepath, path string, tw *tar.Writer, info os.FileInfo) (err error) {
 	var (
 		src    *os.File
 		header *tar.Header
@@ -131,6 +206,10 @@ func write(basepath, path string, tw *tar.Writer, info os.FileInfo) (err error)
 	}
 	header.Name = target
 
+	if t.reproducible {
+		normalize(header)
+	}
+
 	if err = tw.WriteHeader(header); err != nil {
 		return errors.Wrapf(err, "failed to write header to tar archive: %s", path)
 	}
@@ -140,9 +219,28 @@ func write(basepath, path string, tw *tar.Writer, info os.FileInfo) (err error)
 		return nil
 	}
 
-	if _, err = io.Copy(tw, src); err != nil {
+	if _, err = io.Copy(io.MultiWriter(tw, progressWriter(t.progress)), src); err != nil {
 		return errors.Wrapf(err, "failed to write contexts to tar archive: %s", path)
 	}
 
 	return nil
 }
+
+// normalize the metadata of the header that varies between systems and checkouts.
+func normalize(header *tar.Header) {
+	header.ModTime = time.Unix(0, 0)
+	header.AccessTime = time.Time{}
+	header.ChangeTime = time.Time{}
+	header.Uid, header.Gid = 0, 0
+	header.Uname, header.Gname = "", ""
+	header.Xattrs = nil //nolint:staticcheck // deprecated in favor of PAXRecords, cleared for completeness.
+	header.PAXRecords = nil
+	header.Format = tar.FormatPAX
+}
+
+type progressWriter func(int64)
+
+func (t progressWriter) Write(b []byte) (int, error) {
+	t(int64(len(b)))
+	return len(b), nil
+}
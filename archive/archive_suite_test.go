@@ -0,0 +1,16 @@
+package archive_test
+
+import (
+	"github.com/james-lawrence/bw/internal/testingx"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestArchive(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Archive Suite")
+}
+
+var _ = SynchronizedAfterSuite(func() {}, testingx.Cleanup)
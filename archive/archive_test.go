@@ -0,0 +1,84 @@
+package archive_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/archive"
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+func packWith(dir string, options ...archive.PackOption) []byte {
+	buf := bytes.NewBuffer(nil)
+	Expect(archive.NewPacker(options...).Pack(buf, dir)).To(Succeed())
+	return buf.Bytes()
+}
+
+var _ = Describe("Packer", func() {
+	DescribeTable("should round trip archives",
+		func(options ...archive.PackOption) {
+			src := testingx.TempDir()
+			dst := testingx.TempDir()
+			randomFile(filepath.Join(src, "a"), 1, 8*1024*1024)
+			Expect(os.MkdirAll(filepath.Join(src, "nested"), 0700)).To(Succeed())
+			randomFile(filepath.Join(src, "nested", "b"), 2, 1024)
+
+			encoded := packWith(src, options...)
+			chunks, err := archive.Chunks(bytes.NewReader(encoded))
+			Expect(err).To(Succeed())
+			Expect(len(chunks)).To(BeNumerically(">", 1))
+
+			total := int64(0)
+			for _, c := range chunks {
+				total += c.Length
+			}
+			Expect(total).To(Equal(int64(len(encoded))))
+
+			Expect(archive.Unpack(dst, bytes.NewReader(encoded))).To(Succeed())
+			for _, name := range []string{"a", filepath.Join("nested", "b")} {
+				expected, err := os.ReadFile(filepath.Join(src, name))
+				Expect(err).To(Succeed())
+				Expect(os.ReadFile(filepath.Join(dst, name))).To(Equal(expected))
+			}
+		},
+		Entry("gzip", archive.PackOptionCompression(archive.CompressionGzip, 0)),
+		Entry("gzip with level", archive.PackOptionCompression(archive.CompressionGzip, 1)),
+		Entry("zstd", archive.PackOptionCompression(archive.CompressionZstd, 0)),
+		Entry("zstd with level", archive.PackOptionCompression(archive.CompressionZstd, 19)),
+		Entry("reproducible zstd", archive.PackOptionCompression(archive.CompressionZstd, 0), archive.PackOptionReproducible(true)),
+	)
+
+	It("should reject unsupported compression", func() {
+		buf := bytes.NewBuffer(nil)
+		Expect(archive.NewPacker(archive.PackOptionCompression("lz4", 0)).Pack(buf, testingx.TempDir())).ToNot(Succeed())
+	})
+
+	It("should reject archives with an unknown format", func() {
+		Expect(archive.Unpack(testingx.TempDir(), bytes.NewReader([]byte("not an archive")))).ToNot(Succeed())
+	})
+
+	It("should produce identical reproducible archives", func() {
+		dir := testingx.TempDir()
+		randomFile(filepath.Join(dir, "a"), 1, 1024*1024)
+		randomFile(filepath.Join(dir, "b"), 2, 1024)
+
+		reproducible := []archive.PackOption{
+			archive.PackOptionCompression(archive.CompressionZstd, 0),
+			archive.PackOptionReproducible(true),
+		}
+
+		original := packWith(dir, reproducible...)
+
+		ts := time.Now().Add(-time.Hour)
+		Expect(os.Chtimes(filepath.Join(dir, "a"), ts, ts)).To(Succeed())
+		Expect(os.Chtimes(filepath.Join(dir, "b"), ts, ts)).To(Succeed())
+
+		Expect(packWith(dir, reproducible...)).To(Equal(original))
+		Expect(packWith(dir, archive.PackOptionCompression(archive.CompressionZstd, 0))).ToNot(Equal(original))
+	})
+})
@@ -0,0 +1,270 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// content defined chunking parameters, boundaries are chosen by the content of the
+// uncompressed archive so unchanged files produce identical chunks between deploys.
+const (
+	chunkMin  = 256 * 1024
+	chunkMax  = 4 * 1024 * 1024
+	chunkMask = (1 << 20) - 1 // ~1MiB average chunk beyond the minimum.
+)
+
+// gear hash table, generated with splitmix64 so every version produces the same boundaries.
+var gear = func() (table [256]uint64) {
+	state := uint64(0x6277617263686976) // "bwarchiv"
+	for i := range table {
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		table[i] = z ^ (z >> 31)
+	}
+	return table
+}()
+
+// Chunk of a packed archive, every chunk is an independent gzip member.
+type Chunk struct {
+	Digest []byte // sha256 digest of the compressed chunk.
+	Length int64  // length of the compressed chunk.
+}
+
+// newChunkWriter compresses each content defined chunk of the written data as
+// a separate member, i.e.) a gzip member or zstd frame. the members concatenate
+// into a valid stream.
+func newChunkWriter(dst io.Writer, c compressor) *chunkWriter {
+	return &chunkWriter{
+		dst: dst,
+		c:   c,
+		buf: make([]byte, 0, chunkMax),
+	}
+}
+
+type chunkWriter struct {
+	dst io.Writer
+	c   compressor
+	buf []byte
+	h   uint64
+}
+
+func (t *chunkWriter) Write(p []byte) (n int, err error) {
+	for _, b := range p {
+		t.buf = append(t.buf, b)
+		if len(t.buf) < chunkMin {
+			continue
+		}
+
+		t.h = (t.h << 1) + gear[b]
+		if t.h&chunkMask != 0 && len(t.buf) < chunkMax {
+			continue
+		}
+
+		if err = t.flush(); err != nil {
+			return n, err
+		}
+	}
+
+	return len(p), nil
+}
+
+func (t *chunkWriter) flush() (err error) {
+	if len(t.buf) == 0 {
+		return nil
+	}
+
+	if err = t.c.compress(t.dst, t.buf); err != nil {
+		return err
+	}
+
+	t.buf = t.buf[:0]
+	t.h = 0
+
+	return nil
+}
+
+// Close writes the final chunk.
+func (t *chunkWriter) Close() error {
+	return t.flush()
+}
+
+// Chunks reads the chunks of a packed archive. archives that were not packed
+// in chunks are returned as a single chunk.
+func Chunks(r io.Reader) (chunks []Chunk, err error) {
+	var (
+		algorithm string
+		cr        = &countingReader{r: bufio.NewReader(r), digest: sha256.New()}
+		next      func(*countingReader) error
+	)
+
+	if algorithm, err = detect(cr.r); err != nil {
+		return nil, err
+	}
+
+	switch algorithm {
+	case CompressionZstd:
+		next = zstdFrame
+	default:
+		if next, err = gzipMembers(cr); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		if err = next(cr); err == io.EOF {
+			return chunks, nil
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to read chunk")
+		}
+
+		chunks = append(chunks, Chunk{Digest: cr.digest.Sum(nil), Length: cr.n})
+		cr.digest.Reset()
+		cr.n = 0
+	}
+}
+
+// gzipMembers reads a gzip member at a time.
+func gzipMembers(cr *countingReader) (func(*countingReader) error, error) {
+	var (
+		err     error
+		gzr     *gzip.Reader
+		started bool
+	)
+
+	if gzr, err = gzip.NewReader(cr); err != nil {
+		return nil, errors.Wrap(err, "failed to create gzip reader")
+	}
+
+	return func(cr *countingReader) (err error) {
+		if started {
+			if err = gzr.Reset(cr); err != nil {
+				return err
+			}
+		}
+
+		started = true
+		gzr.Multistream(false)
+		_, err = io.Copy(io.Discard, gzr)
+		return err
+	}, nil
+}
+
+// zstdFrame reads a single zstd frame, frames are delimited by their
+// headers without decompressing their contents. see RFC 8878.
+func zstdFrame(cr *countingReader) (err error) {
+	var (
+		header [4]byte
+	)
+
+	if _, err = io.ReadFull(cr, header[:]); err != nil {
+		return err
+	}
+
+	magic := binary.LittleEndian.Uint32(header[:])
+
+	// skippable frames.
+	if magic&0xFFFFFFF0 == 0x184D2A50 {
+		if _, err = io.ReadFull(cr, header[:]); err != nil {
+			return errors.Wrap(err, "truncated skippable frame")
+		}
+
+		return discard(cr, int64(binary.LittleEndian.Uint32(header[:])))
+	}
+
+	if !bytes.Equal(header[:], magicZstd) {
+		return errors.Errorf("invalid zstd frame magic: %x", header)
+	}
+
+	descriptor, err := cr.ReadByte()
+	if err != nil {
+		return errors.Wrap(err, "truncated zstd frame header")
+	}
+
+	single := descriptor&0x20 != 0
+	fcs := [4]int64{0, 2, 4, 8}[descriptor>>6]
+	if fcs == 0 && single {
+		fcs = 1
+	}
+
+	size := [4]int64{0, 1, 2, 4}[descriptor&0x3] + fcs
+	if !single {
+		size++ // window descriptor.
+	}
+
+	if err = discard(cr, size); err != nil {
+		return errors.Wrap(err, "truncated zstd frame header")
+	}
+
+	for last := false; !last; {
+		var block [3]byte
+
+		if _, err = io.ReadFull(cr, block[:]); err != nil {
+			return errors.Wrap(err, "truncated zstd block header")
+		}
+
+		bh := uint32(block[0]) | uint32(block[1])<<8 | uint32(block[2])<<16
+		last = bh&0x1 != 0
+		length := int64(bh >> 3)
+
+		switch (bh >> 1) & 0x3 {
+		case 1: // rle blocks contain a single byte.
+			length = 1
+		case 3:
+			return errors.New("reserved zstd block type")
+		}
+
+		if err = discard(cr, length); err != nil {
+			return errors.Wrap(err, "truncated zstd block")
+		}
+	}
+
+	// content checksum.
+	if descriptor&0x4 != 0 {
+		return errors.Wrap(discard(cr, 4), "truncated zstd checksum")
+	}
+
+	return nil
+}
+
+func discard(r io.Reader, n int64) error {
+	_, err := io.CopyN(io.Discard, r, n)
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// countingReader tracks the exact bytes consumed by the gzip reader, gzip
+// does not buffer readers implementing io.ByteReader.
+type countingReader struct {
+	r      *bufio.Reader
+	digest hash.Hash
+	n      int64
+}
+
+func (t *countingReader) Read(p []byte) (n int, err error) {
+	n, err = t.r.Read(p)
+	t.n += int64(n)
+	t.digest.Write(p[:n])
+	return n, err
+}
+
+func (t *countingReader) ReadByte() (b byte, err error) {
+	if b, err = t.r.ReadByte(); err != nil {
+		return b, err
+	}
+
+	t.n++
+	t.digest.Write([]byte{b})
+	return b, nil
+}
@@ -0,0 +1,78 @@
+package archive_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/rand"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/archive"
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+func randomFile(path string, seed int64, n int) {
+	buf := make([]byte, n)
+	_, err := rand.New(rand.NewSource(seed)).Read(buf)
+	Expect(err).To(Succeed())
+	Expect(os.WriteFile(path, buf, 0600)).To(Succeed())
+}
+
+func pack(dir string) []byte {
+	buf := bytes.NewBuffer(nil)
+	Expect(archive.Pack(buf, dir)).To(Succeed())
+	return buf.Bytes()
+}
+
+func digests(chunks []archive.Chunk) (r []string) {
+	for _, c := range chunks {
+		r = append(r, hex.EncodeToString(c.Digest))
+	}
+	return r
+}
+
+var _ = Describe("Chunks", func() {
+	It("should unpack chunked archives", func() {
+		src := testingx.TempDir()
+		dst := testingx.TempDir()
+		randomFile(filepath.Join(src, "a"), 1, 8*1024*1024)
+		randomFile(filepath.Join(src, "b"), 2, 1024)
+
+		encoded := pack(src)
+		chunks, err := archive.Chunks(bytes.NewReader(encoded))
+		Expect(err).To(Succeed())
+		Expect(len(chunks)).To(BeNumerically(">", 1))
+
+		total := int64(0)
+		for _, c := range chunks {
+			total += c.Length
+		}
+		Expect(total).To(Equal(int64(len(encoded))))
+
+		Expect(archive.Unpack(dst, bytes.NewReader(encoded))).To(Succeed())
+		for _, name := range []string{"a", "b"} {
+			expected, err := os.ReadFile(filepath.Join(src, name))
+			Expect(err).To(Succeed())
+			Expect(os.ReadFile(filepath.Join(dst, name))).To(Equal(expected))
+		}
+	})
+
+	It("should reuse chunks of unchanged files", func() {
+		dir := testingx.TempDir()
+		randomFile(filepath.Join(dir, "a"), 1, 8*1024*1024)
+		randomFile(filepath.Join(dir, "b"), 2, 1024)
+
+		original, err := archive.Chunks(bytes.NewReader(pack(dir)))
+		Expect(err).To(Succeed())
+
+		randomFile(filepath.Join(dir, "b"), 3, 1024)
+		modified, err := archive.Chunks(bytes.NewReader(pack(dir)))
+		Expect(err).To(Succeed())
+
+		Expect(digests(modified)).ToNot(Equal(digests(original)))
+		Expect(digests(modified)[:len(modified)-1]).To(Equal(digests(original)[:len(original)-1]))
+	})
+})
@@ -0,0 +1,126 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// compression algorithms supported by archives.
+const (
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+var (
+	magicGzip = []byte{0x1f, 0x8b}
+	magicZstd = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// compressor compresses chunks into independent members of the archive.
+type compressor interface {
+	compress(dst io.Writer, b []byte) error
+}
+
+func newCompressor(algorithm string, level int) (compressor, error) {
+	switch algorithm {
+	case CompressionGzip, "":
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+
+		gzw, err := gzip.NewWriterLevel(io.Discard, level)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid gzip compression level: %d", level)
+		}
+
+		return gzipCompressor{gzw: gzw}, nil
+	case CompressionZstd:
+		speed := zstd.SpeedDefault
+		if level != 0 {
+			speed = zstd.EncoderLevelFromZstd(level)
+		}
+
+		enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(speed), zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create zstd encoder")
+		}
+
+		return zstdCompressor{enc: enc}, nil
+	default:
+		return nil, errors.Errorf("unsupported compression: %s", algorithm)
+	}
+}
+
+type gzipCompressor struct {
+	gzw *gzip.Writer
+}
+
+func (t gzipCompressor) compress(dst io.Writer, b []byte) (err error) {
+	t.gzw.Reset(dst)
+
+	if _, err = t.gzw.Write(b); err != nil {
+		return errors.Wrap(err, "failed to compress chunk")
+	}
+
+	return errors.Wrap(t.gzw.Close(), "failed to compress chunk")
+}
+
+type zstdCompressor struct {
+	enc *zstd.Encoder
+}
+
+func (t zstdCompressor) compress(dst io.Writer, b []byte) (err error) {
+	_, err = dst.Write(t.enc.EncodeAll(b, nil))
+	return errors.Wrap(err, "failed to compress chunk")
+}
+
+// detect the compression of the archive by its magic bytes.
+func detect(r *bufio.Reader) (string, error) {
+	magic, err := r.Peek(len(magicZstd))
+	if err != nil && err != io.EOF {
+		return "", errors.Wrap(err, "failed to read archive")
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, magicZstd):
+		return CompressionZstd, nil
+	case bytes.HasPrefix(magic, magicGzip):
+		return CompressionGzip, nil
+	default:
+		return "", errors.New("unknown archive compression")
+	}
+}
+
+// decompress the archive.
+func decompress(r io.Reader) (io.ReadCloser, error) {
+	var (
+		br = bufio.NewReader(r)
+	)
+
+	algorithm, err := detect(br)
+	if err != nil {
+		return nil, err
+	}
+
+	switch algorithm {
+	case CompressionZstd:
+		dec, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create zstd reader")
+		}
+
+		return dec.IOReadCloser(), nil
+	default:
+		gzr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create gzip reader")
+		}
+
+		return gzr, nil
+	}
+}
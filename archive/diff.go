@@ -0,0 +1,266 @@
+package archive
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/pkg/errors"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// kinds of changes between two directories.
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// Change to a file between two directories.
+type Change struct {
+	Path string // path relative to the directories.
+	Kind string
+}
+
+// Diff the files of the directories, paths matched by the IgnoreFile of either
+// directory are excluded. changes are in lexical order of their paths.
+func Diff(from, to string) (changes []Change, err error) {
+	var (
+		a, b  map[string]Entry
+		paths []string
+	)
+
+	if a, err = files(from); err != nil {
+		return nil, err
+	}
+
+	if b, err = files(to); err != nil {
+		return nil, err
+	}
+
+	for p := range a {
+		paths = append(paths, p)
+	}
+
+	for p := range b {
+		if _, ok := a[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		ea, inA := a[p]
+		eb, inB := b[p]
+
+		switch {
+		case !inB:
+			changes = append(changes, Change{Path: p, Kind: ChangeRemoved})
+		case !inA:
+			changes = append(changes, Change{Path: p, Kind: ChangeAdded})
+		case ea.Mode != eb.Mode || ea.Size != eb.Size:
+			changes = append(changes, Change{Path: p, Kind: ChangeModified})
+		default:
+			same, err := identical(filepath.Join(from, p), filepath.Join(to, p))
+			if err != nil {
+				return nil, err
+			}
+
+			if !same {
+				changes = append(changes, Change{Path: p, Kind: ChangeModified})
+			}
+		}
+	}
+
+	return changes, nil
+}
+
+// Patch writes the unified diffs of the changes between the directories, changes
+// to binary files are only reported.
+func Patch(dst io.Writer, from, to string, changes ...Change) (err error) {
+	p := patch{}
+
+	for _, c := range changes {
+		var fp filePatch
+
+		if fp, err = newFilePatch(from, to, c); err != nil {
+			return err
+		}
+
+		p = append(p, fp)
+	}
+
+	return errors.Wrap(fdiff.NewUnifiedEncoder(dst, fdiff.DefaultContextLines).Encode(p), "failed to write patch")
+}
+
+// files the regular files of the directory by path.
+func files(dir string) (_ map[string]Entry, err error) {
+	var (
+		entries []Entry
+	)
+
+	if entries, err = List(dir); err != nil {
+		return nil, errors.Wrapf(err, "failed to list directory: %s", dir)
+	}
+
+	m := make(map[string]Entry, len(entries))
+	for _, e := range entries {
+		if e.Mode.IsRegular() {
+			m[e.Path] = e
+		}
+	}
+
+	return m, nil
+}
+
+func identical(a, b string) (_ bool, err error) {
+	var (
+		ac, bc []byte
+	)
+
+	if ac, err = os.ReadFile(a); err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	if bc, err = os.ReadFile(b); err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return bytes.Equal(ac, bc), nil
+}
+
+// isBinary content is reported instead of diffed.
+func isBinary(content []byte) bool {
+	return bytes.IndexByte(content, 0) != -1 || !utf8.Valid(content)
+}
+
+type patch []fdiff.FilePatch
+
+func (t patch) FilePatches() []fdiff.FilePatch {
+	return t
+}
+
+func (t patch) Message() string {
+	return ""
+}
+
+type filePatch struct {
+	from, to fdiff.File
+	binary   bool
+	chunks   []fdiff.Chunk
+}
+
+func newFilePatch(from, to string, c Change) (p filePatch, err error) {
+	var (
+		before, after []byte
+		f, t          *file
+	)
+
+	if c.Kind != ChangeAdded {
+		if f, before, err = readFile(from, c.Path); err != nil {
+			return p, err
+		}
+		p.from = f
+	}
+
+	if c.Kind != ChangeRemoved {
+		if t, after, err = readFile(to, c.Path); err != nil {
+			return p, err
+		}
+		p.to = t
+	}
+
+	if p.binary = isBinary(before) || isBinary(after); p.binary {
+		return p, nil
+	}
+
+	for _, d := range diff.Do(string(before), string(after)) {
+		op := fdiff.Equal
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = fdiff.Add
+		case diffmatchpatch.DiffDelete:
+			op = fdiff.Delete
+		}
+
+		p.chunks = append(p.chunks, chunk{content: d.Text, op: op})
+	}
+
+	return p, nil
+}
+
+func (t filePatch) IsBinary() bool {
+	return t.binary
+}
+
+func (t filePatch) Files() (from, to fdiff.File) {
+	return t.from, t.to
+}
+
+func (t filePatch) Chunks() []fdiff.Chunk {
+	return t.chunks
+}
+
+type file struct {
+	path string
+	hash plumbing.Hash
+	mode filemode.FileMode
+}
+
+func readFile(dir, path string) (_ *file, content []byte, err error) {
+	var (
+		info os.FileInfo
+		mode filemode.FileMode
+	)
+
+	if info, err = os.Stat(filepath.Join(dir, path)); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	if mode, err = filemode.NewFromOSFileMode(info.Mode()); err != nil {
+		return nil, nil, errors.Wrapf(err, "unsupported file mode: %s", path)
+	}
+
+	if content, err = os.ReadFile(filepath.Join(dir, path)); err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+
+	return &file{
+		path: filepath.ToSlash(path),
+		hash: plumbing.ComputeHash(plumbing.BlobObject, content),
+		mode: mode,
+	}, content, nil
+}
+
+func (t *file) Hash() plumbing.Hash {
+	return t.hash
+}
+
+func (t *file) Mode() filemode.FileMode {
+	return t.mode
+}
+
+func (t *file) Path() string {
+	return t.path
+}
+
+type chunk struct {
+	content string
+	op      fdiff.Operation
+}
+
+func (t chunk) Content() string {
+	return t.content
+}
+
+func (t chunk) Type() fdiff.Operation {
+	return t.op
+}
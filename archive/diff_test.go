@@ -0,0 +1,75 @@
+package archive_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/archive"
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+var _ = Describe("Diff", func() {
+	write := func(path, content string) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0700)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+	}
+
+	directories := func() (from, to string) {
+		from, to = testingx.TempDir(), testingx.TempDir()
+
+		write(filepath.Join(from, "unchanged"), "same\n")
+		write(filepath.Join(to, "unchanged"), "same\n")
+		write(filepath.Join(from, "removed"), "gone\n")
+		write(filepath.Join(to, "nested", "added"), "new\n")
+		write(filepath.Join(from, "modified"), "line 1\nline 2\nline 3\n")
+		write(filepath.Join(to, "modified"), "line 1\nline two\nline 3\n")
+		randomFile(filepath.Join(from, "binary"), 1, 1024)
+		randomFile(filepath.Join(to, "binary"), 2, 1024)
+
+		return from, to
+	}
+
+	It("should report the added, removed, and modified files", func() {
+		changes, err := archive.Diff(directories())
+		Expect(err).To(Succeed())
+		Expect(changes).To(Equal([]archive.Change{
+			{Path: "binary", Kind: archive.ChangeModified},
+			{Path: "modified", Kind: archive.ChangeModified},
+			{Path: filepath.Join("nested", "added"), Kind: archive.ChangeAdded},
+			{Path: "removed", Kind: archive.ChangeRemoved},
+		}))
+	})
+
+	It("should exclude ignored paths", func() {
+		from, to := directories()
+		Expect(os.WriteFile(filepath.Join(from, archive.IgnoreFile), []byte("binary\nmodified\n"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(to, archive.IgnoreFile), []byte("binary\nmodified\n"), 0600)).To(Succeed())
+
+		changes, err := archive.Diff(from, to)
+		Expect(err).To(Succeed())
+		Expect(changes).To(Equal([]archive.Change{
+			{Path: filepath.Join("nested", "added"), Kind: archive.ChangeAdded},
+			{Path: "removed", Kind: archive.ChangeRemoved},
+		}))
+	})
+
+	It("should write unified diffs of text files", func() {
+		from, to := directories()
+		changes, err := archive.Diff(from, to)
+		Expect(err).To(Succeed())
+
+		buf := bytes.NewBuffer(nil)
+		Expect(archive.Patch(buf, from, to, changes...)).To(Succeed())
+
+		patch := buf.String()
+		Expect(patch).To(ContainSubstring("--- a/modified\n+++ b/modified\n"))
+		Expect(patch).To(ContainSubstring("-line 2\n+line two\n"))
+		Expect(patch).To(ContainSubstring("--- /dev/null\n+++ b/nested/added\n"))
+		Expect(patch).To(ContainSubstring("--- a/removed\n+++ /dev/null\n"))
+		Expect(patch).To(ContainSubstring("Binary files a/binary and b/binary differ"))
+	})
+})
@@ -0,0 +1,111 @@
+package archive
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/pkg/errors"
+)
+
+// IgnoreFile gitignore syntax file at the root of a packed directory, matching
+// paths are excluded from the archive.
+const IgnoreFile = ".bwignore"
+
+// Entry of an archive.
+type Entry struct {
+	Path string // path relative to the packed directory.
+	Size int64
+	Mode os.FileMode
+}
+
+// List the entries that would be packed from the paths, in the order they'd be packed.
+func List(paths ...string) (entries []Entry, err error) {
+	for _, basepath := range paths {
+		err = walk(basepath, func(path string, info os.FileInfo) error {
+			rel, err := filepath.Rel(basepath, path)
+			if err != nil {
+				return errors.Wrapf(err, "failed to compute path: %s", path)
+			}
+
+			entries = append(entries, Entry{Path: rel, Size: info.Size(), Mode: info.Mode()})
+			return nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+// walk the basepath in lexical order, skipping the root directory itself and
+// any paths matched by its ignore file.
+func walk(basepath string, fn func(path string, info os.FileInfo) error) (err error) {
+	var (
+		m gitignore.Matcher
+	)
+
+	if m, err = ignores(basepath); err != nil {
+		return err
+	}
+
+	return filepath.Walk(basepath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if basepath == path && info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(basepath, path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to compute path: %s", path)
+		}
+
+		if m.Match(strings.Split(filepath.ToSlash(rel), "/"), info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		return fn(path, info)
+	})
+}
+
+// ignores reads the ignore file of the basepath, a missing file ignores nothing.
+func ignores(basepath string) (_ gitignore.Matcher, err error) {
+	var (
+		src      *os.File
+		patterns []gitignore.Pattern
+	)
+
+	if src, err = os.Open(filepath.Join(basepath, IgnoreFile)); os.IsNotExist(err) {
+		return gitignore.NewMatcher(nil), nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %s", IgnoreFile)
+	}
+	defer src.Close()
+
+	scanner := bufio.NewScanner(src)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", IgnoreFile)
+	}
+
+	return gitignore.NewMatcher(patterns), nil
+}
@@ -0,0 +1,85 @@
+package archive_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/archive"
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+var _ = Describe("IgnoreFile", func() {
+	ignored := func() string {
+		dir := testingx.TempDir()
+		for _, name := range []string{".git", "cache", "nested"} {
+			Expect(os.MkdirAll(filepath.Join(dir, name), 0700)).To(Succeed())
+		}
+
+		randomFile(filepath.Join(dir, "a"), 1, 1024)
+		randomFile(filepath.Join(dir, "a.swp"), 2, 1024)
+		randomFile(filepath.Join(dir, "keep.swp"), 3, 1024)
+		randomFile(filepath.Join(dir, ".git", "HEAD"), 4, 1024)
+		randomFile(filepath.Join(dir, "cache", "b"), 5, 1024)
+		randomFile(filepath.Join(dir, "nested", "c"), 6, 1024)
+		randomFile(filepath.Join(dir, "nested", "c.swp"), 7, 1024)
+
+		ignore := "# editor and vcs artifacts\n*.swp\n!keep.swp\n.git/\n/cache\n"
+		Expect(os.WriteFile(filepath.Join(dir, archive.IgnoreFile), []byte(ignore), 0600)).To(Succeed())
+		return dir
+	}
+
+	expected := []string{archive.IgnoreFile, "a", "keep.swp", "nested", filepath.Join("nested", "c")}
+
+	It("should list the paths that are not ignored", func() {
+		entries, err := archive.List(ignored())
+		Expect(err).To(Succeed())
+
+		paths := []string{}
+		for _, e := range entries {
+			paths = append(paths, e.Path)
+		}
+
+		Expect(paths).To(Equal(expected))
+	})
+
+	It("should exclude ignored paths from the archive", func() {
+		src := ignored()
+		dst := testingx.TempDir()
+
+		Expect(archive.Unpack(dst, bytes.NewReader(pack(src)))).To(Succeed())
+
+		// walk the unpacked directory directly, listing it would apply the unpacked ignore file.
+		paths := []string{}
+		Expect(filepath.Walk(dst, func(path string, info os.FileInfo, err error) error {
+			if err != nil || path == dst {
+				return err
+			}
+
+			rel, err := filepath.Rel(dst, path)
+			paths = append(paths, rel)
+			return err
+		})).To(Succeed())
+
+		Expect(paths).To(Equal(expected))
+	})
+
+	It("should exclude ignored paths from the size", func() {
+		n, err := archive.Size(ignored())
+		Expect(err).To(Succeed())
+		Expect(n).To(Equal(int64(3*1024 + len("# editor and vcs artifacts\n*.swp\n!keep.swp\n.git/\n/cache\n"))))
+	})
+
+	It("should include everything without an ignore file", func() {
+		dir := testingx.TempDir()
+		Expect(os.MkdirAll(filepath.Join(dir, ".git"), 0700)).To(Succeed())
+		randomFile(filepath.Join(dir, ".git", "HEAD"), 1, 1024)
+
+		entries, err := archive.List(dir)
+		Expect(err).To(Succeed())
+		Expect(entries).To(HaveLen(2))
+	})
+})
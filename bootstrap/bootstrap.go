@@ -56,6 +56,13 @@ func OptionBackoff(bs backoff.Strategy) func(*UntilSuccess) {
 	}
 }
 
+// OptionCoordinator additional options for the coordinator performing the bootstrap deploy.
+func OptionCoordinator(options ...deployment.CoordinatorOption) func(*UntilSuccess) {
+	return func(us *UntilSuccess) {
+		us.coordinator = append(us.coordinator, options...)
+	}
+}
+
 // NewUntilSuccess continuously bootstraps until it succeeds or hits maximum attempts.
 func NewUntilSuccess(options ...option) UntilSuccess {
 	us := UntilSuccess{
@@ -77,6 +84,7 @@ func NewUntilSuccess(options ...option) UntilSuccess {
 type UntilSuccess struct {
 	maxAttempts int
 	bs          backoff.Strategy
+	coordinator []deployment.CoordinatorOption
 }
 
 type deployer interface {
@@ -84,16 +92,16 @@ type deployer interface {
 }
 
 // Run bootstrapping process until it succeeds
-func (t UntilSuccess) Run(ctx context.Context, c agent.Config, d deployer, dl storage.DownloadProtocol, results chan *deployment.DeployResult) (err error) {
+func (t UntilSuccess) Run(ctx context.Context, c agent.Config, d deployer, dl storage.DownloadFactory, results chan *deployment.DeployResult) (err error) {
 	coord := deployment.New(
 		c.Peer(),
 		d,
-		deployment.CoordinatorOptionRoot(c.Root),
-		deployment.CoordinatorOptionKeepN(c.KeepN),
-		deployment.CoordinatorOptionStorage(
-			storage.New(storage.OptionProtocols(dl)),
-		),
-		deployment.CoordinatorOptionDispatcher(agentutil.LogDispatcher{}),
+		append([]deployment.CoordinatorOption{
+			deployment.CoordinatorOptionRoot(c.Root),
+			deployment.CoordinatorOptionKeepN(c.KeepN),
+			deployment.CoordinatorOptionStorage(dl),
+			deployment.CoordinatorOptionDispatcher(agentutil.LogDispatcher{}),
+		}, t.coordinator...)...,
 	)
 
 	for i := 0; i < t.maxAttempts; i++ {
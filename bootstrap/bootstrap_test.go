@@ -2,6 +2,7 @@ package bootstrap_test
 
 import (
 	"context"
+	"crypto/sha256"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -14,6 +15,7 @@ import (
 	. "github.com/james-lawrence/bw/bootstrap"
 	"github.com/james-lawrence/bw/deployment"
 	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/notary"
 	"github.com/james-lawrence/bw/storage"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -174,6 +176,50 @@ var _ = Describe("Bootstrap", func() {
 		Expect(Bootstrap(context.Background(), c, dc, nil)).To(Succeed())
 	})
 
+	Context("unsigned archives", func() {
+		var (
+			encoded = tarball("unsigned")
+			digest  = sha256.Sum256(encoded)
+		)
+
+		bootstrap := func(v deployment.CoordinatorOption) error {
+			c := agent.Config{
+				Root: testingx.TempDir(),
+			}
+			current := &agent.Deploy{
+				Stage: agent.Deploy_Completed,
+				Archive: &agent.Archive{
+					Peer:         peer1,
+					Ts:           time.Now().Unix(),
+					DeploymentID: bw.MustGenerateID(),
+					Checksum:     digest[:],
+				},
+				Options: &dopts1,
+			}
+
+			dc := deployment.New(
+				agent.NewPeer("local"),
+				noopDeployer{err: nil},
+				deployment.CoordinatorOptionStorage(archiveRegistry(encoded)),
+				deployment.CoordinatorOptionRoot(testingx.TempDir()),
+				v,
+			)
+			Expect(Run(context.Background(), SocketLocal(c), &agenttestutil.Mock{Fail: missing})).To(Succeed())
+			Expect(Run(context.Background(), SocketQuorum(c), &agenttestutil.Mock{Current: current})).To(Succeed())
+			return Bootstrap(context.Background(), c, dc, nil)
+		}
+
+		It("should bootstrap unsigned archives when unsigned archives are accepted", func() {
+			v := notary.NewArchiveVerifier(notary.NewMem(), notary.ArchiveVerifierOptionUnsigned(true))
+			Expect(bootstrap(deployment.CoordinatorOptionVerifier(v))).To(Succeed())
+		})
+
+		It("should reject unsigned archives by default", func() {
+			v := notary.NewArchiveVerifier(notary.NewMem())
+			Expect(bootstrap(deployment.CoordinatorOptionVerifier(v))).To(MatchError(ContainSubstring("archive is not signed")))
+		})
+	})
+
 	Context("active deploy", func() {
 		It("should deploy an active deploy from quorum", func() {
 			c := agent.Config{
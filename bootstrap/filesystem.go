@@ -239,6 +239,7 @@ func (t Filesystem) upload() (err error) {
 	meta := &agent.UploadMetadata{
 		Bytes:     uint64(i.Size()),
 		Vcscommit: dc.Archive.Commit,
+		Signature: dc.Archive.Signature,
 	}
 
 	if dc.Archive, err = agent.NewConn(conn).Upload(context.Background(), meta, src); err != nil {
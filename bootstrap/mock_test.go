@@ -1,7 +1,15 @@
 package bootstrap_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+
+	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/deployment"
+	"github.com/james-lawrence/bw/storage"
 )
 
 type noopDeployer struct {
@@ -11,3 +19,39 @@ type noopDeployer struct {
 func (t noopDeployer) Deploy(dctx *deployment.DeployContext) {
 	dctx.Done(t.err)
 }
+
+// archiveRegistry downloads the same archive for every location.
+type archiveRegistry []byte
+
+func (t archiveRegistry) New(string) storage.Downloader {
+	return t
+}
+
+func (t archiveRegistry) Download(context.Context, *agent.Archive) io.ReadCloser {
+	return io.NopCloser(bytes.NewReader(t))
+}
+
+// tarball with a single file containing the content.
+func tarball(content string) []byte {
+	buf := bytes.NewBuffer(nil)
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+
+	if err := tw.WriteHeader(&tar.Header{Name: "example", Typeflag: tar.TypeReg, Size: int64(len(content)), Mode: 0600}); err != nil {
+		panic(err)
+	}
+
+	if _, err := tw.Write([]byte(content)); err != nil {
+		panic(err)
+	}
+
+	if err := tw.Close(); err != nil {
+		panic(err)
+	}
+
+	if err := gzw.Close(); err != nil {
+		panic(err)
+	}
+
+	return buf.Bytes()
+}
@@ -29,6 +29,10 @@ const (
 	DirDeploys = "deploys"
 	// DirRaft the name of the directory dealing with the raft state.
 	DirRaft = "raft"
+	// DirLogs the name of the directory where deploy logs are retained.
+	DirLogs = "logs"
+	// DirStaged the name of the directory holding archives staged ahead of their deploy.
+	DirStaged = "staged"
 	// DirTorrents the name of the directory for storing torrent information.
 	DirTorrents = "torrents"
 	// DirAuthorizations the directory storing authorization credentials
@@ -41,6 +45,8 @@ const (
 	AuthKeysFile = "bw.auth.keys"
 	// DefaultDeployTimeout default timeout for a deployment.
 	DefaultDeployTimeout = time.Hour
+	// DefaultPrestageTimeout default timeout for staging the archive before a deploy.
+	DefaultPrestageTimeout = 15 * time.Minute
 	// DeployLog filename for the logs of a given deployment.
 	DeployLog = "deploy.log"
 	// ArchiveFile name of the archive file stored on disk
@@ -68,6 +74,8 @@ const (
 	ProtocolAgent     = "bw.agent"
 	ProtocolAutocert  = "bw.autocert"
 	ProtocolTorrent   = "bw.torrent"
+	ProtocolDashboard = "bw.dashboard"
+	ProtocolExchange  = "bw.exchange"
 )
 
 // DeployDir return the deploy directory under the given root.
@@ -75,6 +83,11 @@ func DeployDir(root string) string {
 	return filepath.Join(root, DirDeploys)
 }
 
+// LogsDir return the retained deploy logs directory under the given root.
+func LogsDir(root string) string {
+	return filepath.Join(root, DirLogs)
+}
+
 // RandomID a random identifier.
 type RandomID []byte
 
@@ -82,6 +95,19 @@ func (t RandomID) String() string {
 	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(t)
 }
 
+// ParseRandomID decodes the string representation of a RandomID.
+func ParseRandomID(s string) (_ RandomID, err error) {
+	var (
+		decoded []byte
+	)
+
+	if decoded, err = base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s); err != nil {
+		return nil, errors.Wrapf(err, "invalid id %s", s)
+	}
+
+	return decoded, nil
+}
+
 // SimpleGenerateID ...
 func SimpleGenerateID() (_ignored RandomID, err error) {
 	return GenerateID(rand.Reader)
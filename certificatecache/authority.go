@@ -2,6 +2,7 @@ package certificatecache
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
@@ -11,25 +12,56 @@ import (
 	"sync"
 	"time"
 
+	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/internal/cryptox"
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/rsax"
 	"github.com/james-lawrence/bw/internal/tlsx"
+	"github.com/james-lawrence/bw/notary"
+	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
 )
 
 // ErrAuthorityNotAvailable ...
 const ErrAuthorityNotAvailable = errorsx.String("authority not available")
 
+// duration certificate authority generations are valid for.
+const authorityGenerationDuration = 5 * 365 * 24 * time.Hour
+
+// AuthorityCacheOption options for the authority cache.
+type AuthorityCacheOption func(*AuthorityCache)
+
+// AuthorityCacheOptionKeyStore storage of the authority's private keys.
+func AuthorityCacheOptionKeyStore(ks KeyStore) AuthorityCacheOption {
+	return func(c *AuthorityCache) {
+		c.keys = ks
+	}
+}
+
+// AuthorityCacheOptionReissue refreshes the node's credentials when a rotation reissues
+// certificates and the node's certificate was issued by the previous authority.
+func AuthorityCacheOptionReissue(refresh func() error) AuthorityCacheOption {
+	return func(c *AuthorityCache) {
+		c.reissued = refresh
+	}
+}
+
 // NewAuthorityCache cached authority from the directory.
-func NewAuthorityCache(seed []byte, domain, dir string) *AuthorityCache {
-	return &AuthorityCache{
+func NewAuthorityCache(seed []byte, domain, dir string, options ...AuthorityCacheOption) *AuthorityCache {
+	c := &AuthorityCache{
 		seed:   seed,
 		dir:    dir,
 		domain: domain,
+		keys:   NewDiskKeyStore(dir, KeySourceRandom(rsax.AutoBits())),
 		m:      &sync.RWMutex{},
 	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
 }
 
 // AuthorityCache lazy creation of the authority cache if possible.
@@ -37,81 +69,244 @@ type AuthorityCache struct {
 	seed      []byte
 	dir       string
 	domain    string
+	keys      KeyStore
 	authority *Authority
+	reissued  func() error
 	m         *sync.RWMutex
 }
 
 // Create certificate.
 func (t *AuthorityCache) Create(duration time.Duration, bits int, options ...tlsx.X509Option) (ca, key, cert []byte, err error) {
-	var (
-		encoded  []byte
-		template x509.Certificate
-	)
-
 	t.m.RLock()
 	authority := t.authority
 	t.m.RUnlock()
 
-	if authority != nil && authority.CACert.NotAfter.After(time.Now()) {
+	if authority != nil && authority.valid(time.Now()) {
 		return authority.Create(duration, bits, options...)
 	}
 
 	t.m.Lock()
 	defer t.m.Unlock()
-	subject := tlsx.X509OptionSubject(pkix.Name{
-		CommonName: t.domain,
-	})
 
-	authority = &Authority{}
+	if authority, err = t.load(); err != nil {
+		log.Println("failed to initialize authority", err)
+		return ca, key, cert, ErrAuthorityNotAvailable
+	}
 
-	kpath := filepath.Join(t.dir, DefaultTLSKeyServer)
-	certpath := filepath.Join(t.dir, DefaultDirTLSAuthority, DefaultTLSGeneratedCACert)
-	protopath := filepath.Join(t.dir, DefaultTLSGeneratedCAProto)
+	t.authority = authority
 
-	log.Println("loading ca key", kpath)
-	log.Println("writing proto file", protopath)
+	return t.authority.Create(duration, bits, options...)
+}
 
-	if authority.CAKey, err = rsax.DecodeFile(kpath); err != nil {
-		log.Println("failed to initialize authority", err)
-		return ca, key, cert, ErrAuthorityNotAvailable
+// Rotate advance the rotation of the certificate authority for this node.
+func (t *AuthorityCache) Rotate(req *notary.RotateRequest) (r *notary.Rotation, err error) {
+	var (
+		ca       *x509.Certificate
+		previous *x509.Certificate
+	)
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if r, err = ReadRotation(t.dir); err != nil {
+		return r, err
 	}
 
-	if template, err = tlsx.X509TemplateRand(cryptox.NewChaCha8(t.seed), time.Minute, tlsx.DefaultClock(), subject, tlsx.X509OptionHosts(t.domain)); err != nil {
-		return ca, key, cert, ErrAuthorityNotAvailable
+	if r, err = advance(r, req); err != nil {
+		return r, err
 	}
 
-	if _, cert, err = tlsx.SelfSigned(authority.CAKey, &template); err != nil {
-		log.Println("unable to create certificate", err)
-		return ca, key, cert, ErrAuthorityNotAvailable
+	if req.Phase != notary.Rotation_Idle {
+		if err = t.rotate(r); err != nil {
+			return r, err
+		}
+
+		// force certificates to be issued by the current generation.
+		t.authority = nil
 	}
 
-	if err = tlsx.WriteCertificateFile(certpath, cert); err != nil {
-		return ca, key, cert, ErrAuthorityNotAvailable
+	for _, g := range trusted(r) {
+		if ca, _, err = t.generation(g, r.Issued); err != nil {
+			return r, err
+		}
+
+		if g+1 == r.Generation {
+			previous = ca
+		}
+
+		r.Trusted = append(r.Trusted, fingerprint(ca))
 	}
 
-	if authority.CACert, err = x509.ParseCertificate(cert); err != nil {
-		log.Println("unable to decode certificate", err)
-		return ca, key, cert, ErrAuthorityNotAvailable
+	if req.Phase == notary.Rotation_Reissue {
+		// reissuing rewrites the authority clients check their credentials against,
+		// clients with certificates from the previous generation refresh them.
+		if t.authority, err = t.load(); err != nil {
+			return r, err
+		}
+
+		go t.reissue(previous)
+	}
+
+	return r, nil
+}
+
+// reissue refreshes the node's credentials when they were issued by the previous generation.
+func (t *AuthorityCache) reissue(previous *x509.Certificate) {
+	var (
+		err     error
+		encoded []byte
+		leaf    *x509.Certificate
+	)
+
+	if t.reissued == nil || previous == nil {
+		return
+	}
+
+	certpath := bw.LocateFirstInDir(t.dir, DefaultTLSCertServer, DefaultTLSCertClient)
+	if encoded, err = os.ReadFile(certpath); err != nil {
+		errorsx.Log(errors.Wrap(err, "unable to read credentials"))
+		return
+	}
+
+	if leaf, err = tlsx.DecodePEMCertificate(encoded); err != nil || leaf == nil {
+		errorsx.Log(errors.Wrapf(errorsx.Compact(err, errorsx.String("missing certificate")), "unable to decode credentials: %s", certpath))
+		return
 	}
 
-	prototlscert := agent.NewTLSCertificates(cert, cert)
+	if previous.CheckSignature(leaf.SignatureAlgorithm, leaf.RawTBSCertificate, leaf.Signature) != nil {
+		return
+	}
+
+	log.Println("refreshing credentials issued by the previous authority", certpath)
+	errorsx.Log(errors.Wrap(t.reissued(), "unable to refresh credentials"))
+}
+
+func (t *AuthorityCache) rotate(r *notary.Rotation) (err error) {
+	if r.Phase == notary.Rotation_Retire {
+		retired := r.Generation - 1
+		if err = os.Remove(generationCertPath(t.dir, retired)); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "unable to remove retired authority")
+		}
+
+		if err = t.keys.Retire(retired); err != nil {
+			return err
+		}
+	}
+
+	return writeRotation(t.dir, r)
+}
+
+// load the authority issuing certificates, along with any other trusted authorities.
+func (t *AuthorityCache) load() (authority *Authority, err error) {
+	var (
+		r       *notary.Rotation
+		encoded []byte
+		ca      *x509.Certificate
+		cakey   crypto.Signer
+	)
+
+	if r, err = ReadRotation(t.dir); err != nil {
+		return authority, err
+	}
+
+	authority = &Authority{}
+	for _, g := range trusted(r) {
+		if ca, cakey, err = t.generation(g, r.Issued); err != nil {
+			return authority, err
+		}
+
+		if authority.CACert == nil {
+			authority.CACert, authority.CAKey = ca, cakey
+			continue
+		}
+
+		authority.Trusted = append(authority.Trusted, ca)
+	}
+
+	protopath := filepath.Join(t.dir, DefaultTLSGeneratedCAProto)
+	log.Println("writing proto file", protopath)
+
+	prototlscert := agent.NewTLSCertificates(authority.CACert.Raw, authority.CACert.Raw)
 	if encoded, err = proto.Marshal(&prototlscert); err != nil {
-		return ca, key, cert, ErrAuthorityNotAvailable
+		return authority, errors.WithStack(err)
 	}
 
 	if err = os.WriteFile(protopath, encoded, 0600); err != nil {
-		return ca, key, cert, ErrAuthorityNotAvailable
+		return authority, errors.WithStack(err)
 	}
 
-	t.authority = authority
+	return authority, nil
+}
 
-	return t.authority.Create(duration, bits, options...)
+// generation loads the certificate authority of the generation.
+// generation 0 is regenerated frequently, later generations are deterministic so every node
+// sharing the generation's key produces an identical certificate.
+func (t *AuthorityCache) generation(g uint64, issued int64) (ca *x509.Certificate, key crypto.Signer, err error) {
+	var (
+		template x509.Certificate
+		cert     []byte
+		rand     = cryptox.NewChaCha8(t.seed)
+		options  = []tlsx.X509Option{
+			tlsx.X509OptionSubject(pkix.Name{
+				CommonName: t.domain,
+			}),
+			tlsx.X509OptionHosts(t.domain),
+		}
+	)
+
+	if key, err = t.keys.Signer(g); err != nil {
+		return ca, key, errors.Wrapf(err, "unable to load authority key for generation %d", g)
+	}
+
+	if g == 0 {
+		template, err = tlsx.X509TemplateRand(rand, time.Minute, tlsx.DefaultClock(), options...)
+	} else {
+		rand = cryptox.NewChaCha8(generationSeed(t.seed, g))
+		options = append(options, tlsx.X509OptionCA())
+		template, err = tlsx.X509TemplateRand(rand, authorityGenerationDuration, tlsx.FixedClock(time.Unix(issued, 0)), options...)
+	}
+
+	if err != nil {
+		return ca, key, err
+	}
+
+	if cert, err = x509.CreateCertificate(rand, &template, &template, key.Public(), key); err != nil {
+		return ca, key, errors.Wrap(err, "unable to create certificate")
+	}
+
+	certpath := generationCertPath(t.dir, g)
+	if err = os.MkdirAll(filepath.Dir(certpath), 0700); err != nil {
+		return ca, key, errors.WithStack(err)
+	}
+
+	if err = tlsx.WriteCertificateFile(certpath, cert); err != nil {
+		return ca, key, err
+	}
+
+	if ca, err = x509.ParseCertificate(cert); err != nil {
+		return ca, key, errors.Wrap(err, "unable to decode certificate")
+	}
+
+	return ca, key, nil
 }
 
 // Authority used to generate certificates.
 type Authority struct {
 	CACert *x509.Certificate
-	CAKey  *rsa.PrivateKey
+	CAKey  crypto.Signer
+	// additional authorities included in the bundle returned with each certificate.
+	Trusted []*x509.Certificate
+}
+
+// valid determines if every certificate of the authority is currently valid.
+func (t Authority) valid(now time.Time) bool {
+	for _, ca := range append([]*x509.Certificate{t.CACert}, t.Trusted...) {
+		if !ca.NotAfter.After(now) {
+			return false
+		}
+	}
+
+	return true
 }
 
 // Create certificate from options
@@ -140,8 +335,10 @@ func (t Authority) Create(duration time.Duration, bits int, options ...tlsx.X509
 		return ca, key, cert, err
 	}
 
-	if err = tlsx.WriteCertificate(cabuf, t.CACert.Raw); err != nil {
-		return ca, key, cert, err
+	for _, c := range append([]*x509.Certificate{t.CACert}, t.Trusted...) {
+		if err = tlsx.WriteCertificate(cabuf, c.Raw); err != nil {
+			return ca, key, cert, err
+		}
 	}
 
 	return cabuf.Bytes(), keybuf.Bytes(), certbuf.Bytes(), nil
@@ -28,6 +28,8 @@ const (
 	// DefaultTLSGeneratedCACert name of the tls ca generated by the cluster for generating
 	// client certificates.
 	DefaultTLSGeneratedCACert = "tlsca.gen.cert"
+	// DefaultTLSRotation name of the file recording the rotation of the generated tls ca.
+	DefaultTLSRotation = "tlsca.rotation"
 	// DefaultTLSKeyClient ...
 	DefaultTLSKeyClient = "tlsclient.key"
 	// DefaultTLSCertClient ...
@@ -77,10 +79,36 @@ func AutomaticTLSAgent(seed []byte, domain, dir string) (err error) {
 // FromConfig will automatically refresh credentials in the provided directory
 // based on the mode and the configuration file.
 func FromConfig(dir, mode, configfile string, futureoffset time.Duration, fallback refresher) (err error) {
+	var (
+		r refresher
+	)
+
 	log.Printf("tls credentials mode '%s' - offset '%v'\n", mode, futureoffset)
+	if r, err = fromConfig(dir, mode, configfile, fallback); err != nil {
+		return err
+	}
+
+	return RefreshAutomatic(dir, futureoffset, r)
+}
+
+// RefreshFromConfig immediately refreshes the credentials in the provided directory
+// based on the mode and the configuration file, regardless of their expiration.
+func RefreshFromConfig(dir, mode, configfile string, fallback refresher) (err error) {
+	var (
+		r refresher
+	)
+
+	if r, err = fromConfig(dir, mode, configfile, fallback); err != nil {
+		return err
+	}
+
+	return r.Refresh()
+}
+
+func fromConfig(dir, mode, configfile string, fallback refresher) (_ refresher, err error) {
 	switch mode {
 	case ModeDisabled:
-		return RefreshAutomatic(dir, futureoffset, Noop{})
+		return Noop{}, nil
 	case ModeVault:
 		v := Vault{
 			DefaultTokenFile: VaultDefaultTokenPath(),
@@ -88,24 +116,24 @@ func FromConfig(dir, mode, configfile string, futureoffset time.Duration, fallba
 		}
 
 		if err = bw.ExpandAndDecodeFile(configfile, &v); err != nil {
-			return err
+			return nil, err
 		}
 
 		if strings.TrimSpace(v.CommonName) == "" {
-			return errors.New("server name cannot be blank for vault, please set servername in the configuration")
+			return nil, errors.New("server name cannot be blank for vault, please set servername in the configuration")
 		}
 
 		if strings.TrimSpace(v.Path) == "" {
-			return errors.New("vault PKI path cannot be blank, please set VaultPKIPath in the configuration")
+			return nil, errors.New("vault PKI path cannot be blank, please set VaultPKIPath in the configuration")
 		}
 
-		return RefreshAutomatic(dir, futureoffset, v)
+		return v, nil
 	default:
 		if err = bw.ExpandAndDecodeFile(configfile, fallback); err != nil {
-			return err
+			return nil, err
 		}
 
-		return RefreshAutomatic(dir, futureoffset, fallback)
+		return fallback, nil
 	}
 }
 
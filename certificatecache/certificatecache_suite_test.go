@@ -0,0 +1,20 @@
+package certificatecache
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+func TestCertificatecache(t *testing.T) {
+	log.SetOutput(io.Discard)
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Certificatecache Suite")
+}
+
+var _ = SynchronizedAfterSuite(func() {}, testingx.Cleanup)
@@ -38,6 +38,8 @@ func NewDirectory(serverName, dir, ca string, pool *x509.CertPool) (cache *Direc
 		dir:        dir,
 		pooldir:    filepath.Join(dir, "authorities"),
 		pool:       pool,
+		base:       pool.Clone(),
+		current:    pool.Clone(),
 		watcher:    w,
 		initialize: &sync.Once{},
 		m:          &sync.Mutex{},
@@ -57,6 +59,8 @@ type Directory struct {
 	dir        string
 	pooldir    string
 	pool       *x509.CertPool
+	base       *x509.CertPool // authorities trusted before any were loaded from the directory.
+	current    *x509.CertPool // authorities currently within the directory, rebuilt on every refresh.
 	cachedCert *tls.Certificate
 	watcher    *fsnotify.Watcher
 	initialize *sync.Once
@@ -89,6 +93,37 @@ func (t *Directory) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Cert
 	return t.cert()
 }
 
+// Pool of the authorities currently trusted. unlike the pool provided to the directory,
+// which only ever grows, authorities removed from the directory are no longer trusted.
+func (t *Directory) Pool() *x509.CertPool {
+	t.m.Lock()
+	defer t.m.Unlock()
+	return t.current
+}
+
+// VerifyPeerCertificate for use by tls.Config. rejects peers whose certificates
+// are no longer issued by a currently trusted authority, e.g.) a retired authority.
+func (t *Directory) VerifyPeerCertificate(raw [][]byte, chains [][]*x509.Certificate) (err error) {
+	// verification is disabled, or the peer didn't present a certificate.
+	if len(chains) == 0 {
+		return nil
+	}
+
+	pool := t.Pool()
+	for _, chain := range chains {
+		intermediates := x509.NewCertPool()
+		for _, c := range chain[1:] {
+			intermediates.AddCert(c)
+		}
+
+		if _, err = chain[0].Verify(x509.VerifyOptions{Roots: pool, Intermediates: intermediates, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}}); err == nil {
+			return nil
+		}
+	}
+
+	return errors.Wrap(err, "certificate is not issued by a trusted authority")
+}
+
 func (t *Directory) background() {
 	debounce := time.NewTimer(time.Second)
 	limit := rate.NewLimiter(rate.Every(10*time.Second), 2)
@@ -166,8 +201,13 @@ func (t *Directory) refresh() (err error) {
 
 	t.cachedCert = &cert
 
+	current := t.base.Clone()
+	load := func(path string) error {
+		return errorsx.Compact(LoadCert(t.pool, path), LoadCert(current, path))
+	}
+
 	if systemx.FileExists(certpath) {
-		if err = LoadCert(t.pool, certpath); err != nil {
+		if err = load(certpath); err != nil {
 			return err
 		}
 	} else {
@@ -175,7 +215,7 @@ func (t *Directory) refresh() (err error) {
 	}
 
 	if systemx.FileExists(t.caFile) {
-		if err = LoadCert(t.pool, t.caFile); err != nil {
+		if err = load(t.caFile); err != nil {
 			return err
 		}
 	} else {
@@ -184,6 +224,10 @@ func (t *Directory) refresh() (err error) {
 
 	// refresh the pool
 	log.Println("loading pool", t.pooldir)
+	defer func() {
+		t.current = current
+	}()
+
 	return filepath.Walk(t.pooldir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			log.Println("error walking authority cache", err)
@@ -198,7 +242,7 @@ func (t *Directory) refresh() (err error) {
 			return filepath.SkipDir
 		}
 
-		if err = LoadCert(t.pool, path); err != nil {
+		if err = load(path); err != nil {
 			log.Println(err)
 			return nil
 		} else {
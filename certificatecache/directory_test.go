@@ -0,0 +1,69 @@
+package certificatecache
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/internal/tlsx"
+)
+
+var _ = Describe("Directory", func() {
+	const bits = 1024
+
+	authority := func(name string) Authority {
+		template, err := tlsx.X509Template(time.Hour, tlsx.X509OptionSubject(pkix.Name{CommonName: name}), tlsx.X509OptionCA())
+		Expect(err).To(Succeed())
+		key, der, err := tlsx.SelfSignedRSAGen(bits, template)
+		Expect(err).To(Succeed())
+		ca, err := x509.ParseCertificate(der)
+		Expect(err).To(Succeed())
+		return Authority{CACert: ca, CAKey: key}
+	}
+
+	issue := func(a Authority) (key, cert []byte, leaf *x509.Certificate) {
+		_, key, cert, err := a.Create(time.Hour, bits, tlsx.X509OptionHosts("example.com"))
+		Expect(err).To(Succeed())
+		b, _ := pem.Decode(cert)
+		leaf, err = x509.ParseCertificate(b.Bytes)
+		Expect(err).To(Succeed())
+		return key, cert, leaf
+	}
+
+	It("should stop trusting authorities removed from the directory", func() {
+		dir := testingx.TempDir()
+		retired, current := authority("retired"), authority("current")
+
+		key, cert, _ := issue(current)
+		Expect(os.WriteFile(filepath.Join(dir, DefaultTLSKeyServer), key, 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, DefaultTLSCertServer), cert, 0600)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(dir, DefaultDirTLSAuthority), 0700)).To(Succeed())
+		Expect(tlsx.WriteCertificateFile(filepath.Join(dir, DefaultDirTLSAuthority, "retired.cert"), retired.CACert.Raw)).To(Succeed())
+		Expect(tlsx.WriteCertificateFile(filepath.Join(dir, DefaultDirTLSAuthority, "current.cert"), current.CACert.Raw)).To(Succeed())
+
+		d := NewDirectory("example.com", dir, "", x509.NewCertPool())
+
+		_, _, rleaf := issue(retired)
+		_, _, cleaf := issue(current)
+		Expect(d.VerifyPeerCertificate(nil, [][]*x509.Certificate{{rleaf, retired.CACert}})).To(Succeed())
+		Expect(d.VerifyPeerCertificate(nil, [][]*x509.Certificate{{cleaf, current.CACert}})).To(Succeed())
+
+		Expect(os.Remove(filepath.Join(dir, DefaultDirTLSAuthority, "retired.cert"))).To(Succeed())
+		Expect(d.refresh()).To(Succeed())
+
+		Expect(d.VerifyPeerCertificate(nil, [][]*x509.Certificate{{rleaf, retired.CACert}})).ToNot(Succeed())
+		Expect(d.VerifyPeerCertificate(nil, [][]*x509.Certificate{{cleaf, current.CACert}})).To(Succeed())
+	})
+
+	It("should not verify peers when verification is disabled", func() {
+		d := NewDirectory("example.com", testingx.TempDir(), "", x509.NewCertPool())
+		Expect(d.VerifyPeerCertificate(nil, nil)).To(Succeed())
+	})
+})
@@ -0,0 +1,145 @@
+package certificatecache
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+const (
+	// ModeACME default mode for agents, certificates are issued by an acme provider.
+	ModeACME = "acme"
+	// ModeNotary default mode for clients, certificates are issued by the cluster.
+	ModeNotary = "notary"
+)
+
+// NewInspector inspects the tls credentials within the directory.
+// offset is how far in advance of expiration the credentials are refreshed.
+func NewInspector(dir, ca, mode string, offset time.Duration) Inspector {
+	return Inspector{
+		dir:    dir,
+		ca:     ca,
+		mode:   mode,
+		offset: offset,
+	}
+}
+
+// Inspector reports on the tls credentials within a directory.
+type Inspector struct {
+	dir    string
+	ca     string
+	mode   string
+	offset time.Duration
+}
+
+// TLSStatus reports the current tls credentials.
+func (t Inspector) TLSStatus() (s *agent.TLSStatusResponse, err error) {
+	var (
+		chain       []*x509.Certificate
+		authorities []*x509.Certificate
+		encoded     []byte
+		paths       []string
+	)
+
+	s = &agent.TLSStatusResponse{
+		Mode: t.mode,
+		Path: bw.LocateFirstInDir(t.dir, DefaultTLSCertServer, DefaultTLSSelfSignedCertServer, DefaultTLSCertClient),
+	}
+
+	if filepath.Base(s.Path) == DefaultTLSSelfSignedCertServer {
+		s.Mode = s.Mode + " (self-signed bootstrap)"
+	}
+
+	if encoded, err = os.ReadFile(s.Path); err != nil {
+		s.Error = errors.Wrap(err, "unable to read certificate").Error()
+		return s, nil
+	}
+
+	if chain, err = decodeCertificates(encoded); err != nil {
+		s.Error = errors.Wrapf(err, "invalid certificate: %s", s.Path).Error()
+		return s, nil
+	}
+
+	if len(chain) == 0 {
+		s.Error = errors.Errorf("no certificates found: %s", s.Path).Error()
+		return s, nil
+	}
+
+	for _, c := range chain {
+		s.Chain = append(s.Chain, describeCertificate(c))
+	}
+
+	s.Refresh = chain[0].NotAfter.Add(-t.offset).Unix()
+
+	if paths, err = filepath.Glob(filepath.Join(t.dir, DefaultDirTLSAuthority, "*")); err != nil {
+		return s, errors.WithStack(err)
+	}
+
+	for _, path := range append([]string{t.ca}, paths...) {
+		if encoded, err = os.ReadFile(path); err != nil {
+			if err = errorsx.Ignore(err, os.ErrNotExist); err != nil {
+				return s, errors.Wrapf(err, "unable to read authority: %s", path)
+			}
+			continue
+		}
+
+		if authorities, err = decodeCertificates(encoded); err != nil {
+			return s, errors.Wrapf(err, "invalid authority: %s", path)
+		}
+
+		for _, c := range authorities {
+			s.Authorities = append(s.Authorities, describeCertificate(c))
+		}
+	}
+
+	return s, nil
+}
+
+// decodeCertificates decodes every pem encoded certificate.
+func decodeCertificates(encoded []byte) (certs []*x509.Certificate, err error) {
+	var (
+		c *x509.Certificate
+	)
+
+	for b, rest := pem.Decode(encoded); b != nil; b, rest = pem.Decode(rest) {
+		if b.Type != "CERTIFICATE" {
+			continue
+		}
+
+		if c, err = x509.ParseCertificate(b.Bytes); err != nil {
+			return certs, errors.WithStack(err)
+		}
+
+		certs = append(certs, c)
+	}
+
+	return certs, nil
+}
+
+func describeCertificate(c *x509.Certificate) *agent.TLSCertificate {
+	digest := sha256.Sum256(c.Raw)
+	sans := append([]string(nil), c.DNSNames...)
+	for _, ip := range c.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	return &agent.TLSCertificate{
+		Subject:     c.Subject.String(),
+		Issuer:      c.Issuer.String(),
+		Sans:        sans,
+		NotBefore:   c.NotBefore.Unix(),
+		NotAfter:    c.NotAfter.Unix(),
+		Fingerprint: hex.EncodeToString(digest[:]),
+		Authority:   c.IsCA,
+	}
+}
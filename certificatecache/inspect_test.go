@@ -0,0 +1,69 @@
+package certificatecache
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/internal/rsax"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/internal/tlsx"
+	"github.com/james-lawrence/bw/notary"
+)
+
+var _ = Describe("Inspector", func() {
+	const bits = 1024
+
+	It("should report the serving certificate and authorities", func() {
+		dir := testingx.TempDir()
+		pkey, err := rsax.Generate(bits)
+		Expect(err).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, DefaultTLSKeyServer), pkey, 0600)).To(Succeed())
+
+		seed := []byte("inspect")
+		c := NewAuthorityCache(seed, "example.com", dir, AuthorityCacheOptionKeyStore(diskKeyStore{dir: dir, source: KeySourceRandom(bits)}))
+		_, err = c.Rotate(&notary.RotateRequest{Phase: notary.Rotation_Distribute, Generation: 1, Issued: time.Now().Unix()})
+		Expect(err).To(Succeed())
+
+		ca, _, cert, err := c.Create(time.Hour, bits, ServerTLSOptions("node1.example.com", "127.0.0.1")...)
+		Expect(err).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, DefaultTLSCertServer), cert, 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, DefaultTLSCertCA), ca, 0600)).To(Succeed())
+
+		s, err := NewInspector(dir, filepath.Join(dir, DefaultTLSCertCA), ModeACME, 30*time.Minute).TLSStatus()
+		Expect(err).To(Succeed())
+		Expect(s.Error).To(BeEmpty())
+		Expect(s.Mode).To(Equal(ModeACME))
+		Expect(s.Path).To(Equal(filepath.Join(dir, DefaultTLSCertServer)))
+		Expect(s.Chain).To(HaveLen(1))
+		Expect(s.Chain[0].Sans).To(Equal([]string{"node1.example.com", "127.0.0.1"}))
+		Expect(s.Refresh).To(Equal(s.Chain[0].NotAfter - int64((30 * time.Minute).Seconds())))
+		// the bundle from the ca file along with the generations within the authorities directory.
+		Expect(s.Authorities).To(HaveLen(4))
+	})
+
+	It("should report the self signed bootstrap certificate", func() {
+		dir := testingx.TempDir()
+		template, err := tlsx.X509Template(time.Hour)
+		Expect(err).To(Succeed())
+		key, cert, err := tlsx.SelfSignedRSAGen(bits, template)
+		Expect(err).To(Succeed())
+		Expect(tlsx.WriteCertificateFile(filepath.Join(dir, DefaultTLSSelfSignedCertServer), cert)).To(Succeed())
+		Expect(tlsx.WritePrivateKeyFile(filepath.Join(dir, DefaultTLSKeyServer), key)).To(Succeed())
+
+		s, err := NewInspector(dir, filepath.Join(dir, DefaultTLSCertCA), ModeACME, time.Minute).TLSStatus()
+		Expect(err).To(Succeed())
+		Expect(s.Mode).To(Equal(ModeACME + " (self-signed bootstrap)"))
+		Expect(s.Chain).To(HaveLen(1))
+		Expect(s.Authorities).To(BeEmpty())
+	})
+
+	It("should report missing credentials", func() {
+		s, err := NewInspector(testingx.TempDir(), "", ModeNotary, time.Minute).TLSStatus()
+		Expect(err).To(Succeed())
+		Expect(s.Error).ToNot(BeEmpty())
+	})
+})
@@ -0,0 +1,132 @@
+package certificatecache
+
+import (
+	"crypto"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/internal/rsax"
+)
+
+// key storage backends for the certificate authority.
+const (
+	// KeyStoreDisk private keys are stored within the credentials directory.
+	KeyStoreDisk = "disk"
+	// KeyStorePKCS11 private keys are held by a PKCS#11 token.
+	KeyStorePKCS11 = "pkcs11"
+	// KeyStoreVault private keys are held by a vault transit engine.
+	KeyStoreVault = "vault"
+)
+
+// ValidateKeyStore ensures the key storage is known and supported by this build.
+func ValidateKeyStore(mode string) error {
+	switch mode {
+	case "", KeyStoreDisk, KeyStoreVault:
+		return nil
+	case KeyStorePKCS11:
+		if !pkcs11Supported {
+			return errors.New("pkcs11 authority key storage requires a build with CGO_ENABLED=1 and -tags pkcs11, e.g.) make install TAGS=pkcs11")
+		}
+
+		return nil
+	default:
+		return errors.Errorf("unknown authority key storage: %s", mode)
+	}
+}
+
+// KeyStore provides the signing keys of the certificate authority generations.
+type KeyStore interface {
+	// Signer for the generation's private key, created if necessary.
+	Signer(generation uint64) (crypto.Signer, error)
+	// Retire the generation's private key, it will no longer be used.
+	Retire(generation uint64) error
+}
+
+// KeySource provides the pem encoded private key of an authority generation.
+// every node must receive the same key for a generation, e.g.) from the quorum.
+type KeySource func(generation uint64) ([]byte, error)
+
+// KeySourceRandom generates a random private key, suitable for a single node.
+func KeySourceRandom(bits int) KeySource {
+	return func(uint64) ([]byte, error) {
+		return rsax.Generate(bits)
+	}
+}
+
+// NewDiskKeyStore stores the authority's private keys within the directory.
+// generation 0 uses the server's key, later generations are retrieved from the source
+// the first time they're used.
+func NewDiskKeyStore(dir string, source KeySource) KeyStore {
+	return diskKeyStore{
+		dir:    dir,
+		source: source,
+	}
+}
+
+type diskKeyStore struct {
+	dir    string
+	source KeySource
+}
+
+func (t diskKeyStore) Signer(g uint64) (_ crypto.Signer, err error) {
+	var (
+		encoded []byte
+	)
+
+	if g == 0 {
+		kpath := filepath.Join(t.dir, DefaultTLSKeyServer)
+		log.Println("loading ca key", kpath)
+		return rsax.DecodeFile(kpath)
+	}
+
+	kpath := generationKeyPath(t.dir, g)
+	if encoded, err = os.ReadFile(kpath); err == nil {
+		return rsax.Decode(encoded)
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "unable to read authority key")
+	}
+
+	if encoded, err = t.source(g); err != nil {
+		return nil, errors.Wrapf(err, "unable to retrieve authority key for generation %d", g)
+	}
+
+	if err = os.WriteFile(kpath, encoded, 0600); err != nil {
+		return nil, errors.Wrap(err, "unable to write authority key")
+	}
+
+	return rsax.Decode(encoded)
+}
+
+func (t diskKeyStore) Retire(g uint64) (err error) {
+	// generation 0 is the server's key, which remains in use by the server.
+	if g == 0 {
+		return nil
+	}
+
+	if err = os.Remove(generationKeyPath(t.dir, g)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "unable to remove retired authority key")
+	}
+
+	return nil
+}
+
+// generationSeed derives the seed for the randomness of the generation's certificate,
+// every node derives the same seed so every node produces an identical certificate.
+// the seed is never used to derive the generation's private key.
+func generationSeed(seed []byte, g uint64) []byte {
+	return rsax.DeterministcSeed(seed, []byte(fmt.Sprintf("tlsca.g%d", g)))
+}
+
+// generationKeyPath location of the private key of the authority generation.
+func generationKeyPath(dir string, g uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("tlsca.g%d.key", g))
+}
+
+// generationKeyLabel name of the generation's key within external key storage.
+func generationKeyLabel(prefix string, g uint64) string {
+	return fmt.Sprintf("%s-g%d", prefix, g)
+}
@@ -0,0 +1,13 @@
+//go:build !pkcs11
+
+package certificatecache
+
+import "github.com/pkg/errors"
+
+// pkcs11 support is only available when built with -tags pkcs11.
+const pkcs11Supported = false
+
+// NewPKCS11KeyStore unavailable, PKCS#11 support requires cgo and building with -tags pkcs11.
+func NewPKCS11KeyStore(module, token, pin, prefix string) (KeyStore, error) {
+	return nil, errors.New("pkcs11 key storage is unavailable, rebuild with CGO_ENABLED=1 and -tags pkcs11, e.g.) make install TAGS=pkcs11")
+}
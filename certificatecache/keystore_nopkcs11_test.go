@@ -0,0 +1,21 @@
+//go:build !pkcs11
+
+package certificatecache
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateKeyStore", func() {
+	It("should reject pkcs11 key storage when built without pkcs11 support", func() {
+		Expect(ValidateKeyStore(KeyStorePKCS11)).To(MatchError(ContainSubstring("-tags pkcs11")))
+	})
+
+	It("should accept the key storage supported by every build", func() {
+		Expect(ValidateKeyStore("")).To(Succeed())
+		Expect(ValidateKeyStore(KeyStoreDisk)).To(Succeed())
+		Expect(ValidateKeyStore(KeyStoreVault)).To(Succeed())
+		Expect(ValidateKeyStore("unknown")).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,81 @@
+//go:build pkcs11
+
+package certificatecache
+
+import (
+	"crypto"
+	"log"
+
+	"github.com/ThalesIgnite/crypto11"
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/internal/rsax"
+	"github.com/james-lawrence/bw/internal/stringsx"
+)
+
+// pkcs11 support is only available when built with -tags pkcs11.
+const pkcs11Supported = true
+
+// NewPKCS11KeyStore signs using keys held by the PKCS#11 token, the private keys never leave the token.
+// module is the path to the PKCS#11 library, e.g.) /usr/lib/softhsm/libsofthsm2.so.
+// each generation uses the key pair labeled <prefix>-g<generation>, generated within the token when missing.
+func NewPKCS11KeyStore(module, token, pin, prefix string) (_ KeyStore, err error) {
+	var (
+		ctx *crypto11.Context
+	)
+
+	if ctx, err = crypto11.Configure(&crypto11.Config{Path: module, TokenLabel: token, Pin: pin}); err != nil {
+		return nil, errors.Wrapf(err, "unable to open pkcs11 token %s using %s", token, module)
+	}
+
+	return pkcs11KeyStore{
+		ctx:    ctx,
+		prefix: stringsx.DefaultIfBlank(prefix, "bw-tlsca"),
+		bits:   rsax.AutoBits(),
+	}, nil
+}
+
+type pkcs11KeyStore struct {
+	ctx    *crypto11.Context
+	prefix string
+	bits   int
+}
+
+func (t pkcs11KeyStore) Signer(g uint64) (_ crypto.Signer, err error) {
+	var (
+		s     crypto11.Signer
+		label = generationKeyLabel(t.prefix, g)
+	)
+
+	if s, err = t.ctx.FindKeyPair(nil, []byte(label)); err != nil {
+		return nil, errors.Wrapf(err, "unable to find pkcs11 key pair: %s", label)
+	}
+
+	if s != nil {
+		return s, nil
+	}
+
+	log.Println("generating pkcs11 key pair", label)
+	if s, err = t.ctx.GenerateRSAKeyPairWithLabel([]byte(label), []byte(label), t.bits); err != nil {
+		return nil, errors.Wrapf(err, "unable to generate pkcs11 key pair: %s", label)
+	}
+
+	return s, nil
+}
+
+func (t pkcs11KeyStore) Retire(g uint64) (err error) {
+	var (
+		s     crypto11.Signer
+		label = generationKeyLabel(t.prefix, g)
+	)
+
+	if s, err = t.ctx.FindKeyPair(nil, []byte(label)); err != nil {
+		return errors.Wrapf(err, "unable to find pkcs11 key pair: %s", label)
+	}
+
+	if s == nil {
+		return nil
+	}
+
+	return errors.Wrapf(s.Delete(), "unable to delete pkcs11 key pair: %s", label)
+}
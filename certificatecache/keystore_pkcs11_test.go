@@ -0,0 +1,121 @@
+//go:build pkcs11
+
+package certificatecache
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/internal/envx"
+	"github.com/james-lawrence/bw/internal/rsax"
+	"github.com/james-lawrence/bw/internal/systemx"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/notary"
+)
+
+// softhsm initializes a SoftHSM token within a temporary directory, returning the module's path.
+// run with: CGO_ENABLED=1 go test -tags pkcs11 ./certificatecache
+func softhsm(label, pin string) string {
+	module := envx.String("", "SOFTHSM2_MODULE")
+	for _, candidate := range []string{
+		"/usr/lib/softhsm/libsofthsm2.so",
+		"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+		"/usr/lib64/pkcs11/libsofthsm2.so",
+		"/usr/local/lib/softhsm/libsofthsm2.so",
+	} {
+		if module != "" {
+			break
+		}
+
+		if systemx.FileExists(candidate) {
+			module = candidate
+		}
+	}
+
+	util, err := exec.LookPath("softhsm2-util")
+	if module == "" || err != nil {
+		Skip("softhsm is not installed, set SOFTHSM2_MODULE to the path of libsofthsm2.so")
+	}
+
+	dir := testingx.TempDir()
+	tokens := filepath.Join(dir, "tokens")
+	Expect(os.MkdirAll(tokens, 0700)).To(Succeed())
+
+	conf := filepath.Join(dir, "softhsm2.conf")
+	Expect(os.WriteFile(conf, []byte(fmt.Sprintf("directories.tokendir = %s\nobjectstore.backend = file\n", tokens)), 0600)).To(Succeed())
+	// the module reads its configuration from the environment when the token is opened.
+	GinkgoT().Setenv("SOFTHSM2_CONF", conf)
+
+	cmd := exec.Command(util, "--init-token", "--free", "--label", label, "--pin", pin, "--so-pin", pin)
+	out, err := cmd.CombinedOutput()
+	Expect(err).To(Succeed(), string(out))
+
+	return module
+}
+
+var _ = Describe("PKCS11KeyStore", func() {
+	const (
+		token = "bw"
+		pin   = "1234"
+	)
+
+	It("should sign with keys generated within the token", func() {
+		ks, err := NewPKCS11KeyStore(softhsm(token, pin), token, pin, "")
+		Expect(err).To(Succeed())
+
+		s, err := ks.Signer(1)
+		Expect(err).To(Succeed())
+
+		digest := sha256.Sum256([]byte("hello world"))
+		sig, err := s.Sign(rand.Reader, digest[:], crypto.SHA256)
+		Expect(err).To(Succeed())
+		Expect(rsa.VerifyPKCS1v15(s.Public().(*rsa.PublicKey), crypto.SHA256, digest[:], sig)).To(Succeed())
+
+		again, err := ks.Signer(1)
+		Expect(err).To(Succeed())
+		Expect(again.Public()).To(Equal(s.Public()))
+
+		other, err := ks.Signer(2)
+		Expect(err).To(Succeed())
+		Expect(other.Public()).ToNot(Equal(s.Public()))
+	})
+
+	It("should rotate the authority using keys held by the token", func() {
+		dir := testingx.TempDir()
+		ks, err := NewPKCS11KeyStore(softhsm(token, pin), token, pin, "rotation")
+		Expect(err).To(Succeed())
+
+		// generation 0 remains the server's key on disk.
+		pkey, err := rsax.Generate(1024)
+		Expect(err).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, DefaultTLSKeyServer), pkey, 0600)).To(Succeed())
+		c := NewAuthorityCache([]byte("pkcs11"), "example.com", dir, AuthorityCacheOptionKeyStore(ks))
+
+		issued := time.Now().Unix()
+		_, err = c.Rotate(&notary.RotateRequest{Phase: notary.Rotation_Distribute, Generation: 1, Issued: issued})
+		Expect(err).To(Succeed())
+		_, err = c.Rotate(&notary.RotateRequest{Phase: notary.Rotation_Reissue, Generation: 1})
+		Expect(err).To(Succeed())
+
+		_, _, _, err = c.Create(time.Hour, 1024)
+		Expect(err).To(Succeed())
+
+		signer, err := ks.Signer(1)
+		Expect(err).To(Succeed())
+		Expect(ks.Retire(1)).To(Succeed())
+
+		regenerated, err := ks.Signer(1)
+		Expect(err).To(Succeed())
+		Expect(regenerated.Public()).ToNot(Equal(signer.Public()))
+	})
+})
@@ -0,0 +1,197 @@
+package certificatecache
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/internal/stringsx"
+)
+
+// hash algorithm names understood by the vault transit engine.
+var vaultHashes = map[crypto.Hash]string{
+	crypto.SHA224: "sha2-224",
+	crypto.SHA256: "sha2-256",
+	crypto.SHA384: "sha2-384",
+	crypto.SHA512: "sha2-512",
+}
+
+// NewVaultKeyStore signs using keys held by a vault transit engine mounted at the path,
+// the private keys never leave vault. each generation uses the key named <prefix>-g<generation>,
+// created with the key type (e.g. rsa-4096, ecdsa-p256) when missing.
+func NewVaultKeyStore(c *api.Client, mount, prefix, keytype string) KeyStore {
+	return vaultKeyStore{
+		client:  c,
+		mount:   stringsx.DefaultIfBlank(mount, "transit"),
+		prefix:  stringsx.DefaultIfBlank(prefix, "bw-tlsca"),
+		keytype: stringsx.DefaultIfBlank(keytype, "rsa-4096"),
+	}
+}
+
+type vaultKeyStore struct {
+	client  *api.Client
+	mount   string
+	prefix  string
+	keytype string
+}
+
+func (t vaultKeyStore) Signer(g uint64) (_ crypto.Signer, err error) {
+	var (
+		secret *api.Secret
+		name   = generationKeyLabel(t.prefix, g)
+		keyp   = path.Join(t.mount, "keys", name)
+	)
+
+	if secret, err = t.client.Logical().Read(keyp); err != nil {
+		return nil, errors.Wrapf(err, "unable to read vault transit key: %s", keyp)
+	}
+
+	if secret == nil {
+		log.Println("creating vault transit key", keyp, t.keytype)
+		if _, err = t.client.Logical().Write(keyp, map[string]interface{}{"type": t.keytype}); err != nil {
+			return nil, errors.Wrapf(err, "unable to create vault transit key: %s", keyp)
+		}
+
+		if secret, err = t.client.Logical().Read(keyp); err != nil {
+			return nil, errors.Wrapf(err, "unable to read vault transit key: %s", keyp)
+		}
+	}
+
+	if secret == nil {
+		return nil, errors.Errorf("vault transit key not found: %s", keyp)
+	}
+
+	return newVaultSigner(t.client, t.mount, name, secret.Data)
+}
+
+// Retire leaves the key within vault, transit keys are only deleted by an operator once
+// deletion is explicitly allowed for the key.
+func (t vaultKeyStore) Retire(g uint64) error {
+	log.Println("retired authority key remains in vault", path.Join(t.mount, "keys", generationKeyLabel(t.prefix, g)))
+	return nil
+}
+
+func newVaultSigner(c *api.Client, mount, name string, data map[string]interface{}) (s vaultSigner, err error) {
+	var (
+		version int64
+		block   *pem.Block
+	)
+
+	if version, err = vaultInt(data["latest_version"]); err != nil {
+		return s, errors.Wrapf(err, "invalid vault transit key version: %s", name)
+	}
+
+	versions, _ := data["keys"].(map[string]interface{})
+	details, _ := versions[fmt.Sprint(version)].(map[string]interface{})
+	encoded, _ := details["public_key"].(string)
+
+	if block, _ = pem.Decode([]byte(encoded)); block == nil {
+		return s, errors.Errorf("vault transit key %s has no public key, only asymmetric keys can sign certificates", name)
+	}
+
+	s = vaultSigner{
+		client:  c,
+		mount:   mount,
+		name:    name,
+		version: version,
+	}
+
+	if s.pub, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+		return s, errors.Wrapf(err, "invalid vault transit public key: %s", name)
+	}
+
+	return s, nil
+}
+
+// vaultSigner crypto.Signer backed by a vault transit key.
+type vaultSigner struct {
+	client  *api.Client
+	mount   string
+	name    string
+	version int64
+	pub     crypto.PublicKey
+}
+
+// Public key of the transit key.
+func (t vaultSigner) Public() crypto.PublicKey {
+	return t.pub
+}
+
+// Sign the digest using the transit key.
+func (t vaultSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) (signature []byte, err error) {
+	var (
+		secret *api.Secret
+		hash   string
+		ok     bool
+	)
+
+	if hash, ok = vaultHashes[opts.HashFunc()]; !ok {
+		return nil, errors.Errorf("hash function is not supported by vault transit: %s", opts.HashFunc())
+	}
+
+	payload := map[string]interface{}{
+		"input":       base64.StdEncoding.EncodeToString(digest),
+		"prehashed":   true,
+		"key_version": t.version,
+	}
+
+	if _, ok = t.pub.(*rsa.PublicKey); ok {
+		payload["signature_algorithm"] = "pkcs1v15"
+		if pss, ok := opts.(*rsa.PSSOptions); ok {
+			payload["signature_algorithm"] = "pss"
+			if pss.SaltLength == rsa.PSSSaltLengthEqualsHash {
+				payload["salt_length"] = "hash"
+			}
+		}
+	} else {
+		payload["marshaling_algorithm"] = "asn1"
+	}
+
+	signp := path.Join(t.mount, "sign", t.name, hash)
+	if secret, err = t.client.Logical().Write(signp, payload); err != nil {
+		return nil, errors.Wrapf(err, "vault transit signing failed: %s", signp)
+	}
+
+	if secret == nil {
+		return nil, errors.Errorf("vault transit signing returned no signature: %s", signp)
+	}
+
+	encoded, _ := secret.Data["signature"].(string)
+	// signatures are formatted as vault:v<version>:<base64 signature>
+	if idx := strings.LastIndex(encoded, ":"); idx >= 0 {
+		encoded = encoded[idx+1:]
+	}
+
+	if signature, err = base64.StdEncoding.DecodeString(encoded); err != nil {
+		return nil, errors.Wrap(err, "invalid vault transit signature")
+	}
+
+	return signature, nil
+}
+
+// vault responses decode numbers as json.Number.
+func vaultInt(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case json.Number:
+		return n.Int64()
+	case float64:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case int:
+		return int64(n), nil
+	default:
+		return 0, errors.Errorf("expected a number, found %T", v)
+	}
+}
@@ -0,0 +1,130 @@
+package certificatecache
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/internal/rsax"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/notary"
+)
+
+// fakeTransit minimal vault transit engine holding rsa keys in memory.
+type fakeTransit struct {
+	sync.Mutex
+	keys map[string]*rsa.PrivateKey
+}
+
+func (t *fakeTransit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var (
+		body map[string]interface{}
+	)
+
+	t.Lock()
+	defer t.Unlock()
+
+	reply := func(data map[string]interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}
+
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/transit/"), "/")
+
+	switch {
+	case segments[0] == "keys" && r.Method == http.MethodGet:
+		key, ok := t.keys[segments[1]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		der, _ := x509.MarshalPKIXPublicKey(key.Public())
+		reply(map[string]interface{}{
+			"latest_version": 1,
+			"keys": map[string]interface{}{
+				"1": map[string]interface{}{
+					"public_key": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+				},
+			},
+		})
+	case segments[0] == "keys":
+		key, err := rsa.GenerateKey(rand.Reader, 1024)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		t.keys[segments[1]] = key
+		w.WriteHeader(http.StatusNoContent)
+	case segments[0] == "sign":
+		digest, _ := base64.StdEncoding.DecodeString(body["input"].(string))
+		sig, err := rsa.SignPKCS1v15(rand.Reader, t.keys[segments[1]], crypto.SHA256, digest)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		reply(map[string]interface{}{"signature": "vault:v1:" + base64.StdEncoding.EncodeToString(sig)})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+var _ = Describe("VaultKeyStore", func() {
+	const bits = 1024
+
+	It("should issue certificates signed by the transit key", func() {
+		transit := &fakeTransit{keys: map[string]*rsa.PrivateKey{}}
+		srv := httptest.NewServer(transit)
+		defer srv.Close()
+
+		client, err := api.NewClient(&api.Config{Address: srv.URL})
+		Expect(err).To(Succeed())
+		client.SetToken("example")
+
+		dir := testingx.TempDir()
+		pkey, err := rsax.Generate(bits)
+		Expect(err).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, DefaultTLSKeyServer), pkey, 0600)).To(Succeed())
+
+		c := NewAuthorityCache([]byte("vault"), "example.com", dir, AuthorityCacheOptionKeyStore(NewVaultKeyStore(client, "", "", "")))
+		_, err = c.Rotate(&notary.RotateRequest{Phase: notary.Rotation_Distribute, Generation: 1, Issued: time.Now().Unix()})
+		Expect(err).To(Succeed())
+		_, err = c.Rotate(&notary.RotateRequest{Phase: notary.Rotation_Reissue, Generation: 1})
+		Expect(err).To(Succeed())
+		Expect(transit.keys).To(HaveKey("bw-tlsca-g1"))
+		Expect(filepath.Join(dir, "tlsca.g1.key")).ToNot(BeAnExistingFile())
+
+		ca, _, cert, err := c.Create(time.Hour, bits)
+		Expect(err).To(Succeed())
+
+		b, _ := pem.Decode(ca)
+		authority, err := x509.ParseCertificate(b.Bytes)
+		Expect(err).To(Succeed())
+		Expect(authority.PublicKey).To(Equal(transit.keys["bw-tlsca-g1"].Public()))
+
+		b, _ = pem.Decode(cert)
+		leaf, err := x509.ParseCertificate(b.Bytes)
+		Expect(err).To(Succeed())
+
+		pool := x509.NewCertPool()
+		pool.AddCert(authority)
+		_, err = leaf.Verify(x509.VerifyOptions{Roots: pool})
+		Expect(err).To(Succeed())
+	})
+})
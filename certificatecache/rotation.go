@@ -0,0 +1,145 @@
+package certificatecache
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/notary"
+)
+
+// rotation phases, a rotation moves through distribute, reissue and retire in order.
+//
+//	distribute - the new generation is trusted, the previous generation still issues certificates.
+//	reissue    - the new generation issues certificates, the previous generation is still trusted.
+//	retire     - the previous generation is no longer trusted.
+//
+// each phase must complete on every node before the next phase begins, otherwise
+// nodes may be presented certificates from an authority they do not yet trust.
+
+// generationCertPath location of the certificate of the authority generation.
+// the file lives within the authorities directory so the agent trusts it.
+func generationCertPath(dir string, g uint64) string {
+	if g == 0 {
+		return filepath.Join(dir, DefaultDirTLSAuthority, DefaultTLSGeneratedCACert)
+	}
+
+	return filepath.Join(dir, DefaultDirTLSAuthority, fmt.Sprintf("tlsca.g%d.cert", g))
+}
+
+// ReadRotation the certificate authority rotation recorded in the directory.
+func ReadRotation(dir string) (r *notary.Rotation, err error) {
+	var (
+		encoded []byte
+	)
+
+	r = &notary.Rotation{}
+	if encoded, err = os.ReadFile(filepath.Join(dir, DefaultTLSRotation)); err != nil {
+		return r, errorsx.Ignore(errors.Wrap(err, "unable to read rotation"), os.ErrNotExist)
+	}
+
+	if err = proto.Unmarshal(encoded, r); err != nil {
+		return r, errors.Wrap(err, "invalid rotation")
+	}
+
+	return r, nil
+}
+
+func writeRotation(dir string, r *notary.Rotation) (err error) {
+	var (
+		encoded []byte
+	)
+
+	if encoded, err = proto.Marshal(&notary.Rotation{Generation: r.Generation, Phase: r.Phase, Issued: r.Issued}); err != nil {
+		return errors.Wrap(err, "unable to encode rotation")
+	}
+
+	return errors.Wrap(os.WriteFile(filepath.Join(dir, DefaultTLSRotation), encoded, 0600), "unable to write rotation")
+}
+
+// issuer generation currently issuing certificates.
+func issuer(r *notary.Rotation) uint64 {
+	if r.Phase == notary.Rotation_Distribute {
+		return r.Generation - 1
+	}
+
+	return r.Generation
+}
+
+// trusted generations, the issuing generation is always first.
+func trusted(r *notary.Rotation) []uint64 {
+	switch r.Phase {
+	case notary.Rotation_Distribute:
+		return []uint64{r.Generation - 1, r.Generation}
+	case notary.Rotation_Reissue:
+		return []uint64{r.Generation, r.Generation - 1}
+	default:
+		return []uint64{r.Generation}
+	}
+}
+
+// advance the rotation to the requested phase. repeating the current phase is allowed
+// so a rotation can be safely reapplied to every node after a partial failure.
+func advance(r *notary.Rotation, req *notary.RotateRequest) (_ *notary.Rotation, err error) {
+	current := func(phases ...notary.Rotation_Phase) error {
+		if r.Generation != req.Generation {
+			return errors.Errorf("generation %d is not being rotated, current generation %d (%s)", req.Generation, r.Generation, r.Phase)
+		}
+
+		for _, p := range phases {
+			if r.Phase == p {
+				return nil
+			}
+		}
+
+		return errors.Errorf("unable to %s generation %d while in the %s phase", req.Phase, req.Generation, r.Phase)
+	}
+
+	switch req.Phase {
+	case notary.Rotation_Idle:
+		return r, nil
+	case notary.Rotation_Distribute:
+		if r.Phase == notary.Rotation_Distribute && r.Generation == req.Generation {
+			return r, nil
+		}
+
+		if r.Phase != notary.Rotation_Idle && r.Phase != notary.Rotation_Retire {
+			return r, errors.Errorf("generation %d rotation must be retired before starting another, currently %s", r.Generation, r.Phase)
+		}
+
+		if req.Generation != r.Generation+1 {
+			return r, errors.Errorf("generation %d does not follow the current generation %d", req.Generation, r.Generation)
+		}
+
+		if req.Issued == 0 {
+			return r, errors.New("distributing a generation requires an issued timestamp")
+		}
+
+		return &notary.Rotation{Generation: req.Generation, Phase: req.Phase, Issued: req.Issued}, nil
+	case notary.Rotation_Reissue:
+		if err = current(notary.Rotation_Distribute, notary.Rotation_Reissue); err != nil {
+			return r, err
+		}
+	case notary.Rotation_Retire:
+		if err = current(notary.Rotation_Reissue, notary.Rotation_Retire); err != nil {
+			return r, err
+		}
+	default:
+		return r, errors.Errorf("unknown rotation phase: %d", req.Phase)
+	}
+
+	return &notary.Rotation{Generation: r.Generation, Phase: req.Phase, Issued: r.Issued}, nil
+}
+
+// fingerprint of the authority's public key.
+func fingerprint(c *x509.Certificate) string {
+	digest := sha256.Sum256(c.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(digest[:])
+}
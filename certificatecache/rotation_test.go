@@ -0,0 +1,175 @@
+package certificatecache
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/internal/rsax"
+	"github.com/james-lawrence/bw/internal/systemx"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/notary"
+)
+
+var _ = Describe("Rotation", func() {
+	const bits = 1024
+	seed := []byte("rotation")
+	issued := time.Now().Unix()
+
+	sourcedcache := func(source KeySource) *AuthorityCache {
+		dir := testingx.TempDir()
+		pkey, err := rsax.Generate(bits)
+		Expect(err).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, DefaultTLSKeyServer), pkey, 0600)).To(Succeed())
+		return NewAuthorityCache(seed, "example.com", dir, AuthorityCacheOptionKeyStore(NewDiskKeyStore(dir, source)))
+	}
+
+	quickcache := func() *AuthorityCache {
+		return sourcedcache(KeySourceRandom(bits))
+	}
+
+	// emulates the quorum, the first key generated for a generation is shared.
+	sharedsource := func() KeySource {
+		keys := map[uint64][]byte{}
+		return func(g uint64) (_ []byte, err error) {
+			if _, ok := keys[g]; !ok {
+				if keys[g], err = rsax.Generate(bits); err != nil {
+					return nil, err
+				}
+			}
+
+			return keys[g], nil
+		}
+	}
+
+	// decode the authority bundle of a newly issued certificate.
+	bundle := func(c *AuthorityCache) (authorities []*x509.Certificate, leaf *x509.Certificate) {
+		ca, _, cert, err := c.Create(time.Hour, bits)
+		Expect(err).To(Succeed())
+
+		for b, rest := pem.Decode(ca); b != nil; b, rest = pem.Decode(rest) {
+			decoded, err := x509.ParseCertificate(b.Bytes)
+			Expect(err).To(Succeed())
+			authorities = append(authorities, decoded)
+		}
+
+		b, _ := pem.Decode(cert)
+		leaf, err = x509.ParseCertificate(b.Bytes)
+		Expect(err).To(Succeed())
+
+		return authorities, leaf
+	}
+
+	rotate := func(c *AuthorityCache, phase notary.Rotation_Phase, g uint64) *notary.Rotation {
+		r, err := c.Rotate(&notary.RotateRequest{Phase: phase, Generation: g, Issued: issued})
+		Expect(err).To(Succeed())
+		return r
+	}
+
+	It("should trust, reissue from, and retire authorities in order", func() {
+		c := quickcache()
+
+		authorities, _ := bundle(c)
+		Expect(authorities).To(HaveLen(1))
+		gen0 := fingerprint(authorities[0])
+
+		r := rotate(c, notary.Rotation_Distribute, 1)
+		Expect(r.Trusted).To(HaveLen(2))
+		Expect(r.Trusted[0]).To(Equal(gen0))
+		Expect(systemx.FileExists(generationCertPath(c.dir, 1))).To(BeTrue())
+
+		authorities, leaf := bundle(c)
+		Expect(authorities).To(HaveLen(2))
+		Expect(fingerprint(authorities[0])).To(Equal(gen0))
+		Expect(authorities[0].CheckSignature(leaf.SignatureAlgorithm, leaf.RawTBSCertificate, leaf.Signature)).To(Succeed())
+
+		rotate(c, notary.Rotation_Reissue, 1)
+		authorities, leaf = bundle(c)
+		Expect(authorities).To(HaveLen(2))
+		Expect(fingerprint(authorities[1])).To(Equal(gen0))
+
+		pool := x509.NewCertPool()
+		pool.AddCert(authorities[0])
+		_, err := leaf.Verify(x509.VerifyOptions{Roots: pool})
+		Expect(err).To(Succeed())
+
+		r = rotate(c, notary.Rotation_Retire, 1)
+		Expect(r.Trusted).To(Equal([]string{fingerprint(authorities[0])}))
+		Expect(systemx.FileExists(generationCertPath(c.dir, 0))).To(BeFalse())
+
+		retired, _ := bundle(c)
+		Expect(retired).To(HaveLen(1))
+		Expect(retired[0].Raw).To(Equal(authorities[0].Raw))
+
+		persisted, err := ReadRotation(c.dir)
+		Expect(err).To(Succeed())
+		Expect(persisted.Generation).To(Equal(uint64(1)))
+		Expect(persisted.Phase).To(Equal(notary.Rotation_Retire))
+	})
+
+	It("should allow reapplying the current phase", func() {
+		c := quickcache()
+		first := rotate(c, notary.Rotation_Distribute, 1)
+		Expect(rotate(c, notary.Rotation_Distribute, 1).Trusted).To(Equal(first.Trusted))
+	})
+
+	It("should reject phases applied out of order", func() {
+		c := quickcache()
+		_, err := c.Rotate(&notary.RotateRequest{Phase: notary.Rotation_Reissue, Generation: 1})
+		Expect(err).ToNot(Succeed())
+		_, err = c.Rotate(&notary.RotateRequest{Phase: notary.Rotation_Distribute, Generation: 2, Issued: issued})
+		Expect(err).ToNot(Succeed())
+
+		rotate(c, notary.Rotation_Distribute, 1)
+		_, err = c.Rotate(&notary.RotateRequest{Phase: notary.Rotation_Retire, Generation: 1})
+		Expect(err).ToNot(Succeed())
+		_, err = c.Rotate(&notary.RotateRequest{Phase: notary.Rotation_Distribute, Generation: 2, Issued: issued})
+		Expect(err).ToNot(Succeed())
+	})
+
+	It("should generate the same authority on every node sharing the key source", func() {
+		source := sharedsource()
+		a, b := sourcedcache(source), sourcedcache(source)
+		Expect(rotate(a, notary.Rotation_Distribute, 1).Trusted[1]).To(Equal(rotate(b, notary.Rotation_Distribute, 1).Trusted[1]))
+
+		ca, err := os.ReadFile(generationCertPath(a.dir, 1))
+		Expect(err).To(Succeed())
+		Expect(os.ReadFile(generationCertPath(b.dir, 1))).To(Equal(ca))
+	})
+
+	It("should not derive the authority's key from the seed", func() {
+		a, b := quickcache(), quickcache()
+		Expect(rotate(a, notary.Rotation_Distribute, 1).Trusted[1]).ToNot(Equal(rotate(b, notary.Rotation_Distribute, 1).Trusted[1]))
+	})
+
+	It("should refresh credentials issued by the previous authority when reissuing", func() {
+		refreshed := make(chan struct{}, 1)
+		c := quickcache()
+		AuthorityCacheOptionReissue(func() error {
+			refreshed <- struct{}{}
+			return nil
+		})(c)
+
+		_, _, cert, err := c.Create(time.Hour, bits)
+		Expect(err).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(c.dir, DefaultTLSCertServer), cert, 0600)).To(Succeed())
+
+		rotate(c, notary.Rotation_Distribute, 1)
+		Consistently(refreshed).ShouldNot(Receive())
+
+		rotate(c, notary.Rotation_Reissue, 1)
+		Eventually(refreshed).Should(Receive())
+
+		// credentials issued by the current authority are left alone.
+		_, _, cert, err = c.Create(time.Hour, bits)
+		Expect(err).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(c.dir, DefaultTLSCertServer), cert, 0600)).To(Succeed())
+		rotate(c, notary.Rotation_Reissue, 1)
+		Consistently(refreshed).ShouldNot(Receive())
+	})
+})
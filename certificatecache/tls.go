@@ -39,9 +39,11 @@ func TLSGenServer(c agent.Config, options ...tlsx.Option) (creds *tls.Config, er
 		ClientAuth:           tls.RequireAndVerifyClientCert,
 		GetCertificate:       m.GetCertificate,
 		GetClientCertificate: m.GetClientCertificate,
-		ClientCAs:            pool,
-		RootCAs:              pool,
-		NextProtos:           []string{"bw.mux"},
+		// the pools only ever grow, retired authorities are rejected by the directory.
+		VerifyPeerCertificate: m.VerifyPeerCertificate,
+		ClientCAs:             pool,
+		RootCAs:               pool,
+		NextProtos:            []string{"bw.mux"},
 	}
 
 	return tlsx.Clone(creds, options...)
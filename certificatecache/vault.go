@@ -43,17 +43,11 @@ func (t Vault) Refresh() (err error) {
 	var (
 		client      *api.Client
 		credentials *api.Secret
-		config      *api.Config
 	)
 
-	if config = api.DefaultConfig(); config.Error != nil {
-		return errors.WithStack(config.Error)
-	}
-
-	if client, err = api.NewClient(config); err != nil {
-		return errors.WithStack(err)
+	if client, err = NewVaultClient(t.DefaultTokenFile); err != nil {
+		return err
 	}
-	client.SetToken(stringsx.DefaultIfBlank(client.Token(), t.readTokenFile()))
 
 	payload := map[string]interface{}{
 		"common_name": t.CommonName,
@@ -87,14 +81,34 @@ func (t Vault) Refresh() (err error) {
 	return nil
 }
 
-func (t Vault) readTokenFile() string {
+// NewVaultClient configured from the environment (VAULT_ADDR, VAULT_TOKEN, ...),
+// falling back to the token file when no token is provided.
+func NewVaultClient(tokenfile string) (client *api.Client, err error) {
+	var (
+		config *api.Config
+	)
+
+	if config = api.DefaultConfig(); config.Error != nil {
+		return nil, errors.WithStack(config.Error)
+	}
+
+	if client, err = api.NewClient(config); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	client.SetToken(stringsx.DefaultIfBlank(client.Token(), readVaultTokenFile(tokenfile)))
+
+	return client, nil
+}
+
+func readVaultTokenFile(path string) string {
 	var (
 		err error
 		raw []byte
 	)
 
-	if raw, err = os.ReadFile(t.DefaultTokenFile); err != nil {
-		log.Println("failed to read vault token from file", t.DefaultTokenFile, err)
+	if raw, err = os.ReadFile(path); err != nil {
+		log.Println("failed to read vault token from file", path, err)
 		return ""
 	}
 
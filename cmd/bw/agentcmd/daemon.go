@@ -85,6 +85,8 @@ func (t *daemon) bind(ctx *cmdopts.Global, config agent.Config, deployer daemons
 		localpriv []byte
 		localpub  []byte
 		tc        storage.TorrentConfig
+		upload    storage.UploadProtocol
+		downloads []storage.DownloadProtocol
 		tlscreds  *tls.Config
 		ns        notary.Composite
 		ss        notary.Signer
@@ -95,6 +97,10 @@ func (t *daemon) bind(ctx *cmdopts.Global, config agent.Config, deployer daemons
 		return err
 	}
 
+	if err = certificatecache.ValidateKeyStore(config.Authority.Keys); err != nil {
+		return errors.Wrap(err, "invalid authority configuration")
+	}
+
 	log.SetPrefix("[AGENT] ")
 	log.Println("configuration:", spew.Sdump(config.Sanitize()))
 
@@ -219,6 +225,11 @@ func (t *daemon) bind(ctx *cmdopts.Global, config agent.Config, deployer daemons
 		acme.NewALPNCertCache(acme.NewResolver(config.Peer(), dctx.Cluster, acmesvc, dialer)),
 	)
 
+	// browsers negotiate http/1.1, these connections are routed to the dashboard.
+	if config.Dashboard.Enabled {
+		alpn.NextProtos = append(alpn.NextProtos, "http/1.1")
+	}
+
 	for idx, b := range bound {
 		bound[idx] = tls.NewListener(
 			b,
@@ -238,6 +249,7 @@ func (t *daemon) bind(ctx *cmdopts.Global, config agent.Config, deployer daemons
 
 	// attempt notary synchronize before bootstrapping.
 	daemons.SyncAuthorizations(dctx)
+	daemons.CollectExpiredAuthorizations(dctx, time.Minute)
 
 	if dctx, err = daemons.Quorum(dctx, &t.Peering); err != nil {
 		return errors.Wrap(err, "failed to initialize quorum service")
@@ -256,32 +268,43 @@ func (t *daemon) bind(ctx *cmdopts.Global, config agent.Config, deployer daemons
 		return errors.Wrap(err, "failed to initialize deploy archive transfer service")
 	}
 
-	if err = daemons.Agent(dctx, tc.Uploader(), tc.Downloader()); err != nil {
+	if upload, downloads, err = daemons.ArchiveStorage(dctx, tc); err != nil {
+		return errors.Wrap(err, "failed to initialize deploy archive storage")
+	}
+
+	gc := daemons.ArchiveGC(dctx, tc)
+
+	if err = daemons.Agent(dctx, gc, upload, downloads...); err != nil {
 		return errors.Wrap(err, "failed to initialize agent service")
 	}
 
+	if err = daemons.Dashboard(dctx); err != nil {
+		return errors.Wrap(err, "failed to initialize dashboard service")
+	}
+
 	go deployment.ResultBus(
 		dctx.Results,
 		syncAuthorizationsPostDeploy(dctx),
-		clearTorrents(tc),
+		collectArchives(gc),
 	)
 
-	if err = daemons.Bootstrap(dctx, tc.Downloader()); err != nil {
+	if err = daemons.Bootstrap(dctx, downloads...); err != nil {
 		return errors.Wrap(err, "failed to bootstrap node shutting down")
 	}
 
 	return nil
 }
 
-func clearTorrents(c storage.TorrentConfig) chan *deployment.DeployResult {
+// collectArchives removes the archives no longer retained, once on startup and after every deploy.
+func collectArchives(gc storage.TorrentGC) chan *deployment.DeployResult {
 	var (
-		tcu storage.TorrentUtil
 		tdr = make(chan *deployment.DeployResult)
 	)
 
 	go func() {
+		gc.Collect()
 		for range tdr {
-			tcu.ClearTorrents(c)
+			gc.Collect()
 		}
 	}()
 
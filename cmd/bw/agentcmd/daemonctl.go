@@ -43,6 +43,8 @@ type CmdControl struct {
 	CPU        CmdControlProfileCPU    `cmd:"" help:"run a cpu profile against agents"`
 	Memory     CmdControlProfileMemory `cmd:"" help:"run a memory profile against agents"`
 	Heap       CmdControlProfileHeap   `cmd:"" help:"run a heap profile against agents"`
+	TLS        CmdControlTLS           `cmd:"" name:"tls" help:"manage the cluster's tls certificate authority"`
+	Storage    CmdControlStorage       `cmd:"" help:"manage the deploy archives held by each node"`
 }
 
 type controlConnection struct {
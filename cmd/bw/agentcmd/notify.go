@@ -17,6 +17,7 @@ import (
 	"github.com/james-lawrence/bw/deployment/notifications/native"
 	"github.com/james-lawrence/bw/deployment/notifications/sentryio"
 	"github.com/james-lawrence/bw/deployment/notifications/slack"
+	"github.com/james-lawrence/bw/deployment/notifications/smtp"
 	"github.com/james-lawrence/bw/internal/envx"
 	"github.com/james-lawrence/bw/internal/tlsx"
 	"github.com/james-lawrence/bw/notary"
@@ -63,6 +64,7 @@ func (t *Notify) Run(ctx *cmdopts.Global, aconfig *agent.Config) (err error) {
 		"desktop":  func() notifications.Notifier { return native.New() },
 		"slack":    func() notifications.Notifier { return slack.New() },
 		"sentryio": func() notifications.Notifier { return sentryio.New() },
+		"smtp":     func() notifications.Notifier { return smtp.New() },
 	})
 	if err != nil {
 		return err
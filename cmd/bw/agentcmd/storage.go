@@ -0,0 +1,55 @@
+package agentcmd
+
+import (
+	"context"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/agent/operations"
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/james-lawrence/bw/cmd/bw/cmdopts"
+	"github.com/james-lawrence/bw/uxterm"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+type CmdControlStorage struct {
+	GC CmdControlStorageGC `cmd:"" name:"gc" help:"remove the archives each node no longer retains, keeping the most recent, deployed, and pinned archives"`
+}
+
+type CmdControlStorageGC struct {
+	DryRun bool `name:"dry-run" help:"report the archives that would be removed without removing them"`
+	controlConnection
+}
+
+func (t CmdControlStorageGC) Run(gctx *cmdopts.Global) (err error) {
+	var (
+		d      dialers.Defaults
+		c      clustering.Rendezvous
+		failed int
+	)
+
+	if d, c, err = t.connect(gctx.Context); err != nil {
+		return err
+	}
+
+	err = operations.New(gctx.Context, operations.Fn(func(ctx context.Context, p *agent.Peer, conn grpc.ClientConnInterface) error {
+		s, err := agent.NewAgentClient(conn).StorageGC(ctx, &agent.StorageGCRequest{DryRun: t.DryRun})
+		if err != nil {
+			failed++
+			s = &agent.StorageGCResponse{Peer: p, DryRun: t.DryRun, Error: err.Error()}
+		}
+
+		return uxterm.PrintStorageGC(s)
+	}))(t.filtered(c), d)
+
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d nodes failed to collect their storage", failed)
+	}
+
+	return nil
+}
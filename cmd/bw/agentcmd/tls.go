@@ -0,0 +1,210 @@
+package agentcmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/agent/operations"
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/james-lawrence/bw/cmd/bw/cmdopts"
+	"github.com/james-lawrence/bw/notary"
+	"github.com/james-lawrence/bw/uxterm"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// rotation phases by their command line name.
+var rotationPhases = map[string]notary.Rotation_Phase{
+	"status":     notary.Rotation_Idle,
+	"distribute": notary.Rotation_Distribute,
+	"reissue":    notary.Rotation_Reissue,
+	"retire":     notary.Rotation_Retire,
+}
+
+type CmdControlTLS struct {
+	Status CmdControlTLSStatus `cmd:"" help:"report the tls certificates of each node, flagging certificates that expire soon"`
+	Rotate CmdControlTLSRotate `cmd:"" help:"rotate the cluster's certificate authority, each phase must complete on every node before starting the next"`
+}
+
+type CmdControlTLSStatus struct {
+	Threshold time.Duration `name:"threshold" help:"flag certificates expiring within the duration" default:"336h"`
+	controlConnection
+}
+
+func (t CmdControlTLSStatus) Run(gctx *cmdopts.Global) (err error) {
+	var (
+		d       dialers.Defaults
+		c       clustering.Rendezvous
+		flagged int
+		now     = time.Now()
+	)
+
+	if d, c, err = t.connect(gctx.Context); err != nil {
+		return err
+	}
+
+	err = operations.New(gctx.Context, operations.Fn(func(ctx context.Context, p *agent.Peer, conn grpc.ClientConnInterface) error {
+		s, err := agent.NewAgentClient(conn).TLSStatus(ctx, &agent.TLSStatusRequest{})
+		if err != nil {
+			s = &agent.TLSStatusResponse{Peer: p, Error: err.Error()}
+		}
+
+		expiring, err := uxterm.PrintTLSStatus(s, now, t.Threshold)
+		if expiring {
+			flagged++
+		}
+
+		return err
+	}))(t.filtered(c), d)
+
+	if err != nil {
+		return err
+	}
+
+	if flagged > 0 {
+		return errors.Errorf("%d nodes have certificates that are unavailable or expire within %s", flagged, t.Threshold)
+	}
+
+	return nil
+}
+
+type CmdControlTLSRotate struct {
+	Phase string `arg:"" name:"phase" enum:"status,distribute,reissue,retire" help:"status: report the rotation of each node. distribute: trust a new authority. reissue: issue certificates from the new authority. retire: stop trusting the previous authority."`
+	controlConnection
+}
+
+// rotation progress of a single node.
+type rotationResult struct {
+	peer     *agent.Peer
+	rotation *notary.Rotation
+	err      error
+}
+
+func (t CmdControlTLSRotate) Run(gctx *cmdopts.Global) (err error) {
+	var (
+		d       dialers.Defaults
+		c       clustering.Rendezvous
+		current []rotationResult
+		results []rotationResult
+		req     *notary.RotateRequest
+	)
+
+	if d, c, err = t.connect(gctx.Context); err != nil {
+		return err
+	}
+
+	c = t.filtered(c)
+
+	if current, err = t.rotate(gctx.Context, c, d, &notary.RotateRequest{}); err != nil {
+		return err
+	}
+
+	if t.Phase == "status" {
+		return printRotation(current)
+	}
+
+	if req, err = nextRotation(t.Phase, current, time.Now()); err != nil {
+		printRotation(current)
+		return err
+	}
+
+	log.Println("rotating certificate authority", req.Phase, "generation", req.Generation)
+
+	if results, err = t.rotate(gctx.Context, c, d, req); err != nil {
+		return err
+	}
+
+	if err = printRotation(results); err != nil {
+		return err
+	}
+
+	switch req.Phase {
+	case notary.Rotation_Reissue:
+		log.Println("agents with certificates from the previous authority refresh them, clients refresh on their next command")
+	case notary.Rotation_Retire:
+		log.Println("agents stop trusting the retired authority once they reload their authorities")
+	}
+
+	return nil
+}
+
+func (t CmdControlTLSRotate) rotate(ctx context.Context, c clustering.Rendezvous, d dialers.Defaults, req *notary.RotateRequest) (results []rotationResult, err error) {
+	err = operations.New(ctx, operations.Fn(func(ctx context.Context, p *agent.Peer, conn grpc.ClientConnInterface) error {
+		resp, err := notary.NewNotaryClient(conn).Rotate(ctx, req)
+		results = append(results, rotationResult{peer: p, rotation: resp.GetRotation(), err: err})
+		return nil
+	}))(c, d)
+
+	return results, err
+}
+
+// nextRotation determines the request for the phase based on the current rotation of every node.
+// nodes that already completed the phase are included so a partially applied phase can be reapplied.
+func nextRotation(phase string, current []rotationResult, now time.Time) (req *notary.RotateRequest, err error) {
+	req = &notary.RotateRequest{
+		Phase: rotationPhases[phase],
+	}
+
+	for _, r := range current {
+		if r.err != nil {
+			return req, errors.Wrapf(r.err, "unable to determine the rotation of %s (%s)", r.peer.Name, r.peer.Ip)
+		}
+
+		switch {
+		case req.Phase != notary.Rotation_Distribute:
+			req.Generation = max(req.Generation, r.rotation.Generation)
+		case r.rotation.Phase == notary.Rotation_Distribute:
+			// the generation was already distributed to the node, reuse it so every node agrees.
+			req.Generation = max(req.Generation, r.rotation.Generation)
+			req.Issued = r.rotation.Issued
+		default:
+			req.Generation = max(req.Generation, r.rotation.Generation+1)
+		}
+	}
+
+	if req.Phase == notary.Rotation_Distribute && req.Issued == 0 {
+		req.Issued = now.Unix()
+	}
+
+	return req, nil
+}
+
+func printRotation(results []rotationResult) (err error) {
+	var (
+		failed int
+	)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tIP\tGENERATION\tPHASE\tTRUSTED\tERROR")
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			fmt.Fprintf(tw, "%s\t%s\t-\t-\t-\t%s\n", r.peer.Name, r.peer.Ip, r.err)
+			continue
+		}
+
+		trusted := make([]string, 0, len(r.rotation.Trusted))
+		for _, fp := range r.rotation.Trusted {
+			trusted = append(trusted, fp[:min(len(fp), 12)])
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t-\n", r.peer.Name, r.peer.Ip, r.rotation.Generation, r.rotation.Phase, strings.Join(trusted, ","))
+	}
+
+	if err = tw.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d of %d nodes failed, resolve the failures and rerun the phase", failed, len(results))
+	}
+
+	return nil
+}
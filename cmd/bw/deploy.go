@@ -16,6 +16,9 @@ type cmdDeploy struct {
 	Locally  cmdDeployLocal       `cmd:"" name:"locally" aliases:"local" help:"deploy to the local system"`
 	Snapshot cmdDeploySnapshot    `cmd:"" name:"snapshot" help:"generate a deployment archive without uploading it anywhere"`
 	Redeploy cmdDeployRedeploy    `cmd:"" name:"archive" help:"redeploy an archive to nodes within the cluster of the specified environment"`
+	Stage    cmdDeployStage       `cmd:"" name:"stage" help:"distribute an archive to nodes within the cluster of the specified environment ahead of its deploy"`
+	Diff     cmdDeployDiff        `cmd:"" name:"diff" help:"show the changes between a deployed archive and a new snapshot of the specified environment"`
+	Inspect  cmdDeployInspect     `cmd:"" name:"inspect" help:"list the contents and bw.env of a deployment's archive"`
 	Cancel   cmdDeployCancel      `cmd:"" name:"cancel" help:"cancel any current deploy"`
 }
 
@@ -29,6 +32,8 @@ type DeployCluster struct {
 	Names       []*regexp.Regexp `name:"name" help:"regex to match names against"`
 	IPs         []net.IP         `name:"ip" help:"match against the provided IP addresses"`
 	Concurrency int64            `name:"concurrency" help:"number of nodes allowed to deploy simultaneously"`
+	Interactive bool             `name:"interactive" help:"display the deploy using a full screen interactive view"`
+	Prestage    bool             `name:"prestage" help:"distribute the archive to every node before any node begins deploying"`
 }
 
 type cmdDeployEnvironment struct {
@@ -64,6 +69,8 @@ func (t cmdDeployEnvironment) Run(ctx *cmdopts.Global) error {
 		Silent:      t.Silent,
 		Canary:      t.Canary,
 		Debug:       t.Debug,
+		Interactive: t.Interactive,
+		Prestage:    t.Prestage,
 		Filter:      deployment.Or(filters...),
 		AllowEmpty:  len(filters) == 0,
 	})
@@ -103,11 +110,53 @@ func (t cmdDeployRedeploy) Run(ctx *cmdopts.Global) error {
 		Silent:      t.Silent,
 		Canary:      t.Canary,
 		Debug:       t.Debug,
+		Interactive: t.Interactive,
+		Prestage:    t.Prestage,
 		Filter:      deployment.Or(filters...),
 		AllowEmpty:  len(filters) == 0,
 	}, t.DeploymentID)
 }
 
+type cmdDeployStage struct {
+	cmdopts.BeardedWookieEnv
+	Insecure  bool             `help:"skip tls verification"`
+	Debug     bool             `help:"leaves artifacts on the filesystem for debugging"`
+	Canary    bool             `name:"canary" help:"stage on the canary server" default:"false"`
+	Heartbeat time.Duration    `name:"heartbeat" help:"frequency at which heartbeats are expected" default:"10s"`
+	Names     []*regexp.Regexp `name:"name" help:"regex to match names against"`
+	IPs       []net.IP         `name:"ip" help:"match against the provided IP addresses"`
+}
+
+func (t cmdDeployStage) Run(ctx *cmdopts.Global) error {
+	filters := make([]deployment.Filter, 0, len(t.Names))
+	for _, n := range t.Names {
+		filters = append(filters, deployment.Named(n))
+	}
+
+	for _, n := range t.IPs {
+		filters = append(filters, deployment.IP(n))
+	}
+
+	// need a filter to be present for the canary to work.
+	if t.Canary {
+		filters = append(filters, deployment.AlwaysMatch)
+	}
+
+	return deploy.Stage(&deploy.Context{
+		Context:     ctx.Context,
+		CancelFunc:  ctx.Shutdown,
+		WaitGroup:   ctx.Cleanup,
+		Verbose:     ctx.Verbosity > 0,
+		Environment: t.Environment,
+		Insecure:    t.Insecure,
+		Heartbeat:   t.Heartbeat,
+		Canary:      t.Canary,
+		Debug:       t.Debug,
+		Filter:      deployment.Or(filters...),
+		AllowEmpty:  len(filters) == 0,
+	})
+}
+
 type cmdDeployLocal struct {
 	DeployCluster
 	cmdopts.BeardedWookieEnv
@@ -128,6 +177,7 @@ func (t cmdDeployLocal) Run(ctx *cmdopts.Global) error {
 type cmdDeploySnapshot struct {
 	cmdopts.BeardedWookieEnv
 	snapshotOutput *os.File `name:"output" help:"file to write to. by default archive is written to stdout" short:"o"`
+	List           bool     `name:"list" help:"print the files that would be included in the archive and their sizes instead of the archive"`
 }
 
 func (t cmdDeploySnapshot) Run(ctx *cmdopts.Global) error {
@@ -135,13 +185,59 @@ func (t cmdDeploySnapshot) Run(ctx *cmdopts.Global) error {
 		t.snapshotOutput = os.Stdout
 	}
 
-	return deploy.Snapshot(deploy.Context{
+	dctx := deploy.Context{
+		Context:     ctx.Context,
+		CancelFunc:  ctx.Shutdown,
+		WaitGroup:   ctx.Cleanup,
+		Verbose:     ctx.Verbosity > 0,
+		Environment: t.Environment,
+	}
+
+	if t.List {
+		return deploy.SnapshotList(dctx, t.snapshotOutput)
+	}
+
+	return deploy.Snapshot(dctx, t.snapshotOutput)
+}
+
+type cmdDeployDiff struct {
+	cmdopts.BeardedWookieEnvRequired
+	DeploymentID string `arg:"" name:"deployment-id" optional:"" help:"deployment to compare against, defaults to the currently deployed archive"`
+	Snapshot     string `name:"snapshot" type:"existingfile" help:"compare against a local archive generated by bw deploy snapshot instead of downloading it"`
+	NameOnly     bool   `name:"name-only" help:"only list the changed files"`
+	Insecure     bool   `help:"skip tls verification"`
+	Debug        bool   `help:"enable debug logging of the connection to the cluster"`
+}
+
+func (t cmdDeployDiff) Run(ctx *cmdopts.Global) error {
+	return deploy.Diff(&deploy.Context{
 		Context:     ctx.Context,
 		CancelFunc:  ctx.Shutdown,
 		WaitGroup:   ctx.Cleanup,
 		Verbose:     ctx.Verbosity > 0,
 		Environment: t.Environment,
-	}, t.snapshotOutput)
+		Insecure:    t.Insecure,
+		Debug:       t.Debug,
+	}, t.DeploymentID, t.Snapshot, t.NameOnly, os.Stdout)
+}
+
+type cmdDeployInspect struct {
+	DeploymentID string `arg:"" name:"deployment-id"`
+	cmdopts.BeardedWookieEnv
+	Insecure bool `help:"skip tls verification"`
+	Debug    bool `help:"enable debug logging of the connection to the cluster"`
+}
+
+func (t cmdDeployInspect) Run(ctx *cmdopts.Global) error {
+	return deploy.Inspect(&deploy.Context{
+		Context:     ctx.Context,
+		CancelFunc:  ctx.Shutdown,
+		WaitGroup:   ctx.Cleanup,
+		Verbose:     ctx.Verbosity > 0,
+		Environment: t.Environment,
+		Insecure:    t.Insecure,
+		Debug:       t.Debug,
+	}, t.DeploymentID, os.Stdout)
 }
 
 type cmdDeployCancel struct {
@@ -3,10 +3,15 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 	"io"
 	"log"
 	"math"
+	"net"
+	"net/url"
 	"os"
+	"regexp"
+	"sync"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
@@ -21,6 +26,7 @@ import (
 	"github.com/james-lawrence/bw/cmd/commandutils"
 	"github.com/james-lawrence/bw/cmd/termui"
 	"github.com/james-lawrence/bw/daemons"
+	"github.com/james-lawrence/bw/deployment"
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/grpcx"
 	"github.com/james-lawrence/bw/internal/iox"
@@ -36,10 +42,11 @@ import (
 )
 
 type cmdInfo struct {
-	Watch cmdInfoWatch `cmd:"" help:"watch cluster activity"`
-	Nodes cmdInfoNodes `cmd:"" help:"retrieve nodes within the cluster"`
-	Logs  cmdInfoLogs  `cmd:"" help:"log retrieval for the latest deployment"`
-	Check cmdInfoCheck `cmd:"" help:"check connectivity with the discovery service"`
+	Watch     cmdInfoWatch     `cmd:"" help:"watch cluster activity"`
+	Nodes     cmdInfoNodes     `cmd:"" help:"retrieve nodes within the cluster"`
+	Logs      cmdInfoLogs      `cmd:"" help:"deploy log retrieval"`
+	Check     cmdInfoCheck     `cmd:"" help:"check connectivity with the discovery service"`
+	Dashboard cmdInfoDashboard `cmd:"" help:"generate a login link for the agent web dashboard"`
 }
 
 type cmdInfoWatch struct {
@@ -172,11 +179,16 @@ func (t cmdInfoNodes) Run(gctx *cmdopts.Global) (err error) {
 }
 
 type cmdInfoLogs struct {
+	Latest cmdInfoLogsLatest `cmd:"" default:"withargs" help:"log retrieval for the latest deployment"`
+	Search cmdInfoLogsSearch `cmd:"" help:"search the deploy logs of the cluster"`
+}
+
+type cmdInfoLogsLatest struct {
 	cmdopts.BeardedWookieEnv
 	Insecure bool `help:"skip tls verification"`
 }
 
-func (t cmdInfoLogs) Run(gctx *cmdopts.Global) (err error) {
+func (t cmdInfoLogsLatest) Run(gctx *cmdopts.Global) (err error) {
 	var (
 		c      clustering.Rendezvous
 		d      dialers.Defaults
@@ -211,6 +223,146 @@ func (t cmdInfoLogs) Run(gctx *cmdopts.Global) (err error) {
 	return iox.Error(io.Copy(os.Stderr, logs))
 }
 
+type cmdInfoLogsSearch struct {
+	Pattern string `arg:"" name:"pattern" help:"regular expression to match log lines against"`
+	cmdopts.BeardedWookieEnv
+	Insecure   bool             `help:"skip tls verification"`
+	Deployment string           `name:"deployment" help:"restrict the search to the deployment ID"`
+	Since      time.Duration    `name:"since" help:"only search deployments initiated within the duration"`
+	Limit      int64            `name:"limit" help:"maximum number of matches per node, zero is unlimited"`
+	Names      []*regexp.Regexp `name:"name" help:"regex to match names against"`
+	IPs        []net.IP         `name:"ip" help:"match against the provided IP addresses"`
+}
+
+func (t cmdInfoLogsSearch) Run(gctx *cmdopts.Global) (err error) {
+	var (
+		c      clustering.Rendezvous
+		d      dialers.Defaults
+		config agent.ConfigClient
+		ss     notary.Signer
+		did    bw.RandomID
+		m      sync.Mutex
+		wg     sync.WaitGroup
+		failed error
+	)
+	defer gctx.Shutdown()
+
+	if _, err = regexp.Compile(t.Pattern); err != nil {
+		return errors.Wrap(err, "invalid pattern")
+	}
+
+	if t.Deployment != "" {
+		if did, err = bw.ParseRandomID(t.Deployment); err != nil {
+			return err
+		}
+	}
+
+	if config, err = commandutils.LoadConfiguration(gctx.Context, t.Environment, agent.CCOptionInsecure(t.Insecure)); err != nil {
+		return err
+	}
+
+	displayname := vcsinfo.CurrentUserDisplay(config.WorkDir())
+
+	if ss, err = notary.NewAutoSigner(displayname); err != nil {
+		return err
+	}
+
+	local := commandutils.NewClientPeer()
+
+	if d, c, err = daemons.Connect(gctx.Context, config, ss, grpc.WithPerRPCCredentials(ss)); err != nil {
+		return err
+	}
+
+	filters := make([]deployment.Filter, 0, len(t.Names)+len(t.IPs))
+	for _, n := range t.Names {
+		filters = append(filters, deployment.Named(n))
+	}
+
+	for _, n := range t.IPs {
+		filters = append(filters, deployment.IP(n))
+	}
+
+	peers := cluster.New(local, c).Peers()
+	if len(filters) > 0 {
+		peers = deployment.ApplyFilter(deployment.Or(filters...), peers...)
+	}
+
+	req := &agent.LogSearchRequest{
+		Pattern:      t.Pattern,
+		DeploymentID: did,
+		Limit:        t.Limit,
+	}
+
+	if t.Since > 0 {
+		req.Since = time.Now().Add(-t.Since).Unix()
+	}
+
+	search := agentutil.Operation(func(ctx context.Context, p *agent.Peer, c agent.Client) error {
+		return c.SearchLogs(ctx, req, func(match *agent.LogSearchMatch) error {
+			m.Lock()
+			defer m.Unlock()
+			_, err := fmt.Printf("%s %s:%d %s\n", p.Name, bw.RandomID(match.DeploymentID), match.Line, match.Content)
+			return err
+		})
+	})
+
+	for _, p := range peers {
+		wg.Add(1)
+		go func(p *agent.Peer) {
+			defer wg.Done()
+			if cause := agentutil.NewClusterOperation(gctx.Context, search)(agentutil.PeerSet{p}, d); cause != nil {
+				cause = errors.Wrapf(cause, "search failed %s", p.Name)
+				log.Println(cause)
+				m.Lock()
+				failed = errorsx.Compact(failed, cause)
+				m.Unlock()
+			}
+		}(p)
+	}
+
+	wg.Wait()
+
+	return failed
+}
+
+type cmdInfoDashboard struct {
+	cmdopts.BeardedWookieEnv
+	TTL time.Duration `name:"ttl" help:"duration the login link remains valid" default:"1m"`
+}
+
+func (t cmdInfoDashboard) Run(gctx *cmdopts.Global) (err error) {
+	var (
+		config  agent.ConfigClient
+		ss      notary.Signer
+		encoded string
+	)
+
+	if config, err = commandutils.LoadConfiguration(gctx.Context, t.Environment); err != nil {
+		return err
+	}
+
+	if ss, err = notary.NewAutoSigner(vcsinfo.CurrentUserDisplay(config.WorkDir())); err != nil {
+		return err
+	}
+
+	if encoded, err = ss.TokenTTL(t.TTL); err != nil {
+		return errors.Wrap(err, "unable to generate login token")
+	}
+
+	// the token is passed in the fragment which browsers never send to the server.
+	login := url.URL{
+		Scheme:   "https",
+		Host:     config.Address,
+		Path:     "/login",
+		Fragment: url.Values{"authorization": []string{encoded}}.Encode(),
+	}
+
+	log.Println("login link is valid for", t.TTL)
+	fmt.Println(login.String())
+
+	return nil
+}
+
 type cmdInfoCheck struct {
 	Insecure bool   `help:"skip tls verification"`
 	Address  string `help:"address to check" arg:""`
@@ -76,6 +76,7 @@ func main() {
 			"env_bw_agent_bootstrap_dns_enabled":               bw.EnvAgentClusterEnableDNS,
 			"env_bw_agent_bootstrap_aws_autoscaling_enabled":   bw.EnvAgentClusterEnableAWSAutoscaling,
 			"env_bw_agent_bootstrap_gcloud_taget_pool_enabled": bw.EnvAgentClusterEnableGoogleCloudPool,
+			"env_bw_oidc_token":                                bw.EnvOIDCToken,
 		},
 		kong.UsageOnError(),
 		kong.Bind(&shellCli.Global),
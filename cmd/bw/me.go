@@ -2,16 +2,23 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"path/filepath"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/certificatecache"
 	"github.com/james-lawrence/bw/cmd/bw/cmdopts"
 	"github.com/james-lawrence/bw/cmd/commandutils"
 	"github.com/james-lawrence/bw/internal/stringsx"
 	"github.com/james-lawrence/bw/notary"
+	"github.com/james-lawrence/bw/uxterm"
 	"github.com/james-lawrence/bw/vcsinfo"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	sshagent "golang.org/x/crypto/ssh/agent"
 )
 
 // used to configure the user's environment.
@@ -20,6 +27,7 @@ type cmdMe struct {
 	Pub   cmdMePub   `cmd:"" help:"print public key to stdout"`
 	Init  cmdMeInit  `cmd:"" help:"initialize the user's credentials for a workspace"`
 	Clear cmdMeClear `cmd:"" help:"remove the current credentials from disk"`
+	TLS   cmdMeTLS   `cmd:"" name:"tls" help:"inspect the tls credentials of the workspace"`
 }
 
 type cmdMeShow struct{}
@@ -59,8 +67,10 @@ func (t cmdMePub) Run(ctx *cmdopts.Global) (err error) {
 
 type cmdMeInit struct {
 	cmdopts.BeardedWookieEnv
-	Name string `help:"name to assign to the comment"`
-	Seed string `help:"deterministically initialize a user"`
+	Name        string `help:"name to assign to the comment"`
+	Seed        string `help:"deterministically initialize a user"`
+	SSHAgent    bool   `name:"ssh-agent" help:"use a key held by the ssh-agent (SSH_AUTH_SOCK) instead of generating one, the private key never leaves the agent"`
+	SSHAgentKey string `name:"ssh-agent-key" help:"comment or fingerprint (SHA256:...) of the ssh-agent key, required when the agent holds multiple keys"`
 }
 
 func (t cmdMeInit) Run(ctx *cmdopts.Global) (err error) {
@@ -77,7 +87,13 @@ func (t cmdMeInit) Run(ctx *cmdopts.Global) (err error) {
 
 	displayname := vcsinfo.CurrentUserDisplay(config.WorkDir())
 
-	if _, err = notary.NewDeterministicSigner(seed, stringsx.DefaultIfBlank(t.Name, displayname)); err != nil {
+	if t.SSHAgent {
+		if err = t.sshagent(); err != nil {
+			return err
+		}
+	} else if err = notary.ClearSSHAgentSigner(); err != nil {
+		return err
+	} else if _, err = notary.NewDeterministicSigner(seed, stringsx.DefaultIfBlank(t.Name, displayname)); err != nil {
 		return err
 	}
 
@@ -92,8 +108,78 @@ func (t cmdMeInit) Run(ctx *cmdopts.Global) (err error) {
 	)
 }
 
+// initialize the credentials using a key held by the ssh-agent.
+func (t cmdMeInit) sshagent() (err error) {
+	var (
+		a sshagent.ExtendedAgent
+		k *sshagent.Key
+	)
+
+	if a, err = notary.DialSSHAgent(); err != nil {
+		return err
+	}
+
+	if k, err = notary.SSHAgentKey(a, t.SSHAgentKey); err != nil {
+		return err
+	}
+
+	if t.Name != "" {
+		k.Comment = t.Name
+	}
+
+	if _, err = notary.InitSSHAgentSigner(a, k); err != nil {
+		return err
+	}
+
+	log.Println("using ssh-agent key", ssh.FingerprintSHA256(k), k.Comment)
+
+	return nil
+}
+
 type cmdMeClear struct{}
 
 func (t cmdMeClear) Run(ctx *cmdopts.Global) error {
 	return notary.ClearAutoSignerKey()
 }
+
+type cmdMeTLS struct {
+	Status cmdMeTLSStatus `cmd:"" help:"report the client tls certificates, flagging certificates that expire soon"`
+}
+
+type cmdMeTLSStatus struct {
+	cmdopts.BeardedWookieEnv
+	Threshold time.Duration `name:"threshold" help:"flag certificates expiring within the duration" default:"4h"`
+}
+
+func (t cmdMeTLSStatus) Run(ctx *cmdopts.Global) (err error) {
+	var (
+		config  agent.ConfigClient
+		s       *agent.TLSStatusResponse
+		flagged bool
+	)
+
+	if config, err = commandutils.ReadConfiguration(t.Environment); err != nil {
+		return err
+	}
+
+	inspector := certificatecache.NewInspector(
+		config.Credentials.Directory,
+		config.CA,
+		stringsx.DefaultIfBlank(config.Credentials.Mode, certificatecache.ModeNotary),
+		commandutils.ClientRefreshOffset,
+	)
+
+	if s, err = inspector.TLSStatus(); err != nil {
+		return err
+	}
+
+	if flagged, err = uxterm.PrintTLSStatus(s, time.Now(), t.Threshold); err != nil {
+		return err
+	}
+
+	if flagged {
+		return errors.Errorf("client certificates are unavailable or expire within %s, they are refreshed by the next command that connects to the cluster", t.Threshold)
+	}
+
+	return nil
+}
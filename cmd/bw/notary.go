@@ -1,7 +1,14 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/bits-and-blooms/bloom/v3"
 	"github.com/davecgh/go-spew/spew"
@@ -11,60 +18,257 @@ import (
 	"github.com/james-lawrence/bw/cmd/bw/cmdopts"
 	"github.com/james-lawrence/bw/cmd/commandutils"
 	"github.com/james-lawrence/bw/daemons"
+	"github.com/james-lawrence/bw/internal/stringsx"
 	"github.com/james-lawrence/bw/notary"
 	"github.com/james-lawrence/bw/vcsinfo"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
 	"google.golang.org/grpc"
 )
 
 // used to inspect permissions
 type cmdNotary struct {
-	Search cmdNotarySearch `cmd:"" help:"search users"`
-	Print  cmdNotaryPrint  `cmd:"" help:"list the fingerprints and their permissions in a file"`
+	Search   cmdNotarySearch   `cmd:"" help:"search users"`
+	Grant    cmdNotaryGrant    `cmd:"" help:"grant a public key access to the cluster"`
+	Revoke   cmdNotaryRevoke   `cmd:"" help:"revoke a fingerprint's access to the cluster"`
+	Print    cmdNotaryPrint    `cmd:"" help:"list the fingerprints and their permissions in a file"`
+	Audit    cmdNotaryAudit    `cmd:"" help:"display the audit log of privileged actions"`
+	Exchange cmdNotaryExchange `cmd:"" help:"exchange an oidc token (e.g. from a CI system) for a short lived deploy grant of the current credentials"`
+}
+
+// connect to the notary service of the environment.
+func notaryClient(gctx *cmdopts.Global, environment string, insecure bool) (client notary.Client, ss notary.Signer, err error) {
+	var (
+		d      dialers.Direct
+		config agent.ConfigClient
+		c      clustering.Rendezvous
+	)
+
+	if config, err = commandutils.LoadConfiguration(gctx.Context, environment, agent.CCOptionInsecure(insecure)); err != nil {
+		return client, ss, err
+	}
+
+	displayname := vcsinfo.CurrentUserDisplay(config.WorkDir())
+
+	if ss, err = notary.NewAutoSigner(displayname); err != nil {
+		return client, ss, err
+	}
+
+	if d, c, err = daemons.Connect(gctx.Context, config, ss, grpc.WithPerRPCCredentials(ss)); err != nil {
+		return client, ss, err
+	}
+
+	return notary.NewClient(dialers.NewQuorum(c, d.Defaults()...)), ss, nil
+}
+
+func printGrant(action string, g *notary.Grant) {
+	fmt.Printf("%s %s permissions(%s)%s%s\n", action, g.Fingerprint, strings.Join(notary.PermissionNames(g.Permission), ","), grantScope(g.Permission.GetScope()), grantWindow(g, time.Now()))
+}
+
+// describe the scope of the grant, empty when unscoped.
+func grantScope(s *notary.Scope) string {
+	if s == nil {
+		return ""
+	}
+
+	return fmt.Sprintf(" scope(labels(%s) filters(%s) actions(%s))", strings.Join(s.Labels, ","), strings.Join(s.Filters, ","), strings.Join(s.Actions, ","))
+}
+
+// describe the validity window of the grant, empty when unbounded.
+func grantWindow(g *notary.Grant, now time.Time) string {
+	switch {
+	case g.Expired(now):
+		return fmt.Sprintf(" expired(%s)", time.Unix(g.NotAfter, 0).UTC().Format(time.RFC3339))
+	case g.NotAfter > 0:
+		return fmt.Sprintf(" expires(%s)", time.Unix(g.NotAfter, 0).UTC().Format(time.RFC3339))
+	case g.NotBefore > now.Unix():
+		return fmt.Sprintf(" notbefore(%s)", time.Unix(g.NotBefore, 0).UTC().Format(time.RFC3339))
+	default:
+		return ""
+	}
 }
 
 type cmdNotarySearch struct {
+	Query string `arg:"" name:"query" optional:"" help:"whitespace separated terms: fingerprint:<prefix>, permission:<name>, or text matched against the comment"`
 	cmdopts.BeardedWookieEnv
-	Insecure bool `help:"skip tls verification"`
+	Insecure bool   `help:"skip tls verification"`
+	Limit    uint32 `name:"limit" default:"100" help:"maximum number of grants to display, 0 displays every match"`
+	Cursor   string `name:"cursor" help:"resume the search after the fingerprint, printed when more results are available"`
+	JSON     bool   `name:"json" help:"output the grants as json lines"`
+}
+
+// searchResult json representation of a grant returned by a search.
+type searchResult struct {
+	Fingerprint string        `json:"fingerprint"`
+	Comment     string        `json:"comment"`
+	Permissions []string      `json:"permissions"`
+	Source      string        `json:"source"`
+	NotBefore   int64         `json:"notBefore,omitempty"`
+	NotAfter    int64         `json:"notAfter,omitempty"`
+	Scope       *notary.Scope `json:"scope,omitempty"`
 }
 
 func (t cmdNotarySearch) Run(gctx *cmdopts.Global) (err error) {
 	var (
-		d      dialers.Direct
-		config agent.ConfigClient
-		ss     notary.Signer
-		c      clustering.Rendezvous
+		client notary.Client
 		s      notary.Notary_SearchClient
 		page   *notary.SearchResponse
+		next   string
 	)
 	defer gctx.Shutdown()
 
-	if config, err = commandutils.LoadConfiguration(gctx.Context, t.Environment, agent.CCOptionInsecure(t.Insecure)); err != nil {
+	if client, _, err = notaryClient(gctx, t.Environment, t.Insecure); err != nil {
 		return err
 	}
 
-	displayname := vcsinfo.CurrentUserDisplay(config.WorkDir())
+	if s, err = client.Search(gctx.Context, &notary.SearchRequest{Query: t.Query, Cursor: t.Cursor, Limit: t.Limit}); err != nil {
+		return err
+	}
 
-	if ss, err = notary.NewAutoSigner(displayname); err != nil {
+	enc := json.NewEncoder(os.Stdout)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	if !t.JSON {
+		fmt.Fprintln(tw, "FINGERPRINT\tCOMMENT\tPERMISSIONS\tSOURCE\tWINDOW")
+	}
+
+	for page, err = s.Recv(); err == nil; page, err = s.Recv() {
+		next = stringsx.DefaultIfBlank(page.Next, next)
+
+		for _, g := range page.Grants {
+			if t.JSON {
+				r := searchResult{
+					Fingerprint: g.Fingerprint,
+					Comment:     g.Comment,
+					Permissions: notary.PermissionNames(g.Permission),
+					Source:      page.Sources[g.Fingerprint],
+					NotBefore:   g.NotBefore,
+					NotAfter:    g.NotAfter,
+					Scope:       g.Permission.GetScope(),
+				}
+
+				if err = enc.Encode(r); err != nil {
+					return errors.Wrap(err, "unable to encode grant")
+				}
+				continue
+			}
+
+			fmt.Fprintf(
+				tw,
+				"%s\t%s\t%s\t%s\t%s\n",
+				g.Fingerprint,
+				stringsx.DefaultIfBlank(g.Comment, "-"),
+				strings.Join(notary.PermissionNames(g.Permission), ","),
+				page.Sources[g.Fingerprint],
+				stringsx.DefaultIfBlank(strings.TrimSpace(grantWindow(g, time.Now())), "-"),
+			)
+		}
+	}
+
+	if err != io.EOF {
 		return err
 	}
 
-	if d, c, err = daemons.Connect(gctx.Context, config, ss, grpc.WithPerRPCCredentials(ss)); err != nil {
+	if err = tw.Flush(); err != nil {
 		return err
 	}
 
-	client := notary.NewClient(dialers.NewQuorum(c, d.Defaults()...))
+	if next != "" {
+		log.Printf("more results available, continue with: --cursor %s\n", next)
+	}
 
-	if s, err = client.Search(gctx.Context, &notary.SearchRequest{}); err != nil {
+	return nil
+}
+
+type cmdNotaryGrant struct {
+	cmdopts.BeardedWookieEnv
+	Insecure    bool          `help:"skip tls verification"`
+	PublicKey   string        `name:"pubkey-file" required:"" type:"existingfile" help:"path to the ssh public key, in authorized_keys format"`
+	Permissions []string      `name:"permissions" default:"deployer" help:"comma separated permissions (refresh,search,grant,revoke,deploy,autocert,sync) or presets (admin,deployer,readonly)"`
+	TTL         time.Duration `name:"ttl" help:"duration the grant is valid for, e.g. 8h for break glass access. unbounded by default"`
+	Labels      []string      `name:"label" help:"restrict the grant to nodes with the label, e.g. environment=staging. all labels must match"`
+	Filters     []string      `name:"filter" sep:"none" help:"restrict the grant to nodes whose entire name or ip match the regex. any filter may match"`
+	Actions     []string      `name:"action" help:"additional actions (restart,profile) allowed by a scoped grant"`
+}
+
+func (t cmdNotaryGrant) Run(gctx *cmdopts.Global) (err error) {
+	var (
+		client  notary.Client
+		encoded []byte
+		key     ssh.PublicKey
+		comment string
+		perm    *notary.Permission
+		g       *notary.Grant
+	)
+	defer gctx.Shutdown()
+
+	if perm, err = notary.ParsePermissions(t.Permissions...); err != nil {
 		return err
 	}
 
-	for page, err = s.Recv(); err == nil; page, err = s.Recv() {
-		for _, g := range page.Grants {
-			log.Println(g.Fingerprint, spew.Sdump(g.Permission))
-		}
+	if perm.Scope, err = notary.ParseScope(t.Labels, t.Filters, t.Actions); err != nil {
+		return err
+	}
+
+	if encoded, err = os.ReadFile(t.PublicKey); err != nil {
+		return errors.Wrap(err, "unable to read public key")
+	}
+
+	if key, comment, _, _, err = ssh.ParseAuthorizedKey(encoded); err != nil {
+		return errors.Wrapf(err, "invalid public key %s", t.PublicKey)
+	}
+
+	if client, _, err = notaryClient(gctx, t.Environment, t.Insecure); err != nil {
+		return err
 	}
 
-	return err
+	options := []notary.GrantOption{notary.GrantOptionComment(comment)}
+	if t.TTL > 0 {
+		options = append(options, notary.GrantOptionTTL(t.TTL))
+	}
+
+	req := notary.NewGrant(perm, ssh.MarshalAuthorizedKey(key), options...)
+
+	if g, err = client.Grant(req); err != nil {
+		return errors.Wrapf(err, "unable to grant %s", req.Fingerprint)
+	}
+
+	printGrant("granted", g)
+
+	return nil
+}
+
+type cmdNotaryRevoke struct {
+	Fingerprint string `arg:"" name:"fingerprint" help:"fingerprint of the grant to revoke"`
+	cmdopts.BeardedWookieEnv
+	Insecure bool `help:"skip tls verification"`
+}
+
+func (t cmdNotaryRevoke) Run(gctx *cmdopts.Global) (err error) {
+	var (
+		client notary.Client
+		ss     notary.Signer
+		fp     string
+		g      *notary.Grant
+	)
+	defer gctx.Shutdown()
+
+	if client, ss, err = notaryClient(gctx, t.Environment, t.Insecure); err != nil {
+		return err
+	}
+
+	if fp, _, err = ss.AutoSignerInfo(); err != nil {
+		log.Println("unable to determine current fingerprint", err)
+	} else if fp == t.Fingerprint {
+		log.Println("WARNING: revoking your own fingerprint, you will lose access to the cluster")
+	}
+
+	if g, err = client.Revoke(t.Fingerprint); err != nil {
+		return errors.Wrapf(err, "unable to revoke %s", t.Fingerprint)
+	}
+
+	printGrant("revoked", g)
+
+	return nil
 }
 
 type cmdNotaryPrint struct {
@@ -100,3 +304,119 @@ func (t *cmdNotaryPrint) Run(ctx *cmdopts.Global) (err error) {
 		}
 	}
 }
+
+type cmdNotaryAudit struct {
+	cmdopts.BeardedWookieEnv
+	Insecure    bool          `help:"skip tls verification"`
+	Since       time.Duration `name:"since" help:"only display entries recorded within the duration, e.g. 24h. displays all entries by default"`
+	Fingerprint string        `name:"fingerprint" help:"only display entries for the fingerprint"`
+	Limit       uint32        `name:"limit" default:"100" help:"maximum number of entries to display, capped by the quorum"`
+	Cursor      uint64        `name:"cursor" help:"resume the audit log after the sequence, printed when more entries are available"`
+}
+
+func (t cmdNotaryAudit) Run(gctx *cmdopts.Global) (err error) {
+	var (
+		d      dialers.Direct
+		config agent.ConfigClient
+		ss     notary.Signer
+		c      clustering.Rendezvous
+		conn   *grpc.ClientConn
+		resp   *agent.AuditResponse
+		since  int64
+	)
+	defer gctx.Shutdown()
+
+	if config, err = commandutils.LoadConfiguration(gctx.Context, t.Environment, agent.CCOptionInsecure(t.Insecure)); err != nil {
+		return err
+	}
+
+	if ss, err = notary.NewAutoSigner(vcsinfo.CurrentUserDisplay(config.WorkDir())); err != nil {
+		return err
+	}
+
+	if d, c, err = daemons.Connect(gctx.Context, config, ss, grpc.WithPerRPCCredentials(ss)); err != nil {
+		return err
+	}
+
+	if conn, err = dialers.NewQuorum(c, d.Defaults()...).DialContext(gctx.Context); err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if t.Since > 0 {
+		since = time.Now().Add(-t.Since).Unix()
+	}
+
+	if resp, err = agent.NewQuorumClient(conn).Audit(gctx.Context, &agent.AuditRequest{Since: since, Fingerprint: t.Fingerprint, Cursor: t.Cursor, Limit: t.Limit}); err != nil {
+		return errors.Wrap(err, "unable to retrieve audit log")
+	}
+
+	if err = agent.VerifyAudit(resp.Entries...); err != nil {
+		log.Println("WARNING: audit log failed verification", err)
+	}
+
+	for _, e := range resp.Entries {
+		result := "ok"
+		if e.Result != "" {
+			result = e.Result
+		}
+
+		fmt.Printf("%d %s %s %s %s %s\n", e.Sequence, time.Unix(e.Ts, 0).UTC().Format(time.RFC3339), stringsx.DefaultIfBlank(e.Fingerprint, "unauthenticated"), e.Rpc, e.Arguments, result)
+	}
+
+	if resp.Next != 0 {
+		log.Printf("more entries available, continue with: --cursor %d\n", resp.Next)
+	}
+
+	return nil
+}
+
+type cmdNotaryExchange struct {
+	cmdopts.BeardedWookieEnv
+	Insecure  bool   `help:"skip tls verification"`
+	Token     string `name:"token" env:"${env_bw_oidc_token}" help:"oidc token issued by an issuer trusted by the cluster"`
+	TokenFile string `name:"token-file" type:"existingfile" help:"path to a file containing the oidc token"`
+}
+
+func (t cmdNotaryExchange) Run(gctx *cmdopts.Global) (err error) {
+	var (
+		config  agent.ConfigClient
+		d       dialers.Direct
+		ss      notary.Signer
+		encoded []byte
+		g       *notary.Grant
+	)
+	defer gctx.Shutdown()
+
+	token := t.Token
+	if t.TokenFile != "" {
+		if encoded, err = os.ReadFile(t.TokenFile); err != nil {
+			return errors.Wrap(err, "unable to read oidc token")
+		}
+		token = string(encoded)
+	}
+
+	if token = strings.TrimSpace(token); token == "" {
+		return errors.New("an oidc token is required, provide --token or --token-file")
+	}
+
+	if config, err = commandutils.LoadConfiguration(gctx.Context, t.Environment, agent.CCOptionInsecure(t.Insecure)); err != nil {
+		return err
+	}
+
+	if ss, err = notary.NewAutoSigner(vcsinfo.CurrentUserDisplay(config.WorkDir())); err != nil {
+		return err
+	}
+
+	if d, err = daemons.ConnectExchange(config); err != nil {
+		return err
+	}
+
+	if g, err = notary.NewClient(d).Exchange(gctx.Context, token, ss); err != nil {
+		return errors.Wrap(err, "unable to exchange oidc token")
+	}
+
+	printGrant("granted", g)
+
+	return nil
+}
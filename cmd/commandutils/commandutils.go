@@ -28,6 +28,9 @@ import (
 	"github.com/james-lawrence/bw/internal/tlsx"
 )
 
+// ClientRefreshOffset how far in advance of expiration client credentials are refreshed.
+const ClientRefreshOffset = 4 * time.Hour
+
 // NewClientPeer create a client peer.
 func NewClientPeer(options ...agent.PeerOption) (p *agent.Peer) {
 	return agent.NewPeerFromTemplate(
@@ -132,7 +135,7 @@ func LoadConfiguration(ctx context.Context, environment string, options ...agent
 		config.Credentials.Directory,
 		config.Credentials.Mode,
 		path,
-		4*time.Hour,
+		ClientRefreshOffset,
 		cc.NewRefreshClient(
 			config.Credentials.Directory,
 			config.Credentials.Insecure,
@@ -1,7 +1,9 @@
 package deploy
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"log"
@@ -10,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gofrs/uuid"
 	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agent/dialers"
@@ -32,6 +35,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 type Context struct {
@@ -46,6 +50,8 @@ type Context struct {
 	AllowEmpty  bool
 	Canary      bool
 	Debug       bool
+	Interactive bool
+	Prestage    bool
 	context.Context
 	context.CancelFunc
 	*sync.WaitGroup
@@ -55,11 +61,9 @@ type Context struct {
 func Into(gctx *Context) error {
 	var (
 		err       error
-		dst       *os.File
-		dstinfo   os.FileInfo
 		conn      *grpc.ClientConn
 		d         dialers.Defaults
-		client    agent.DeployClient
+		client    agent.DeployConn
 		config    agent.ConfigClient
 		c         clustering.Rendezvous
 		ss        notary.Signer
@@ -124,12 +128,24 @@ func Into(gctx *Context) error {
 
 	qd := dialers.NewQuorum(c, d.Defaults()...)
 
+	// only consider the canary node.
+	if gctx.Canary {
+		peers = agent.NodesToPeers(c.Get(rendezvous.Auto()))
+	} else {
+		peers = agent.NodesToPeers(c.Members()...)
+	}
+
+	peers = deployment.ApplyFilter(gctx.Filter, peers...)
+
 	dctx, failurefn := context.WithCancelCause(gctx.Context)
 	defer failurefn(nil)
 	termui.NewFromClientConfig(
 		dctx, failurefn, config, qd, local, events,
 		ux.OptionHeartbeat(gctx.Heartbeat),
 		ux.OptionDebug(gctx.Verbose),
+		ux.OptionInteractive(gctx.Interactive),
+		ux.OptionPeers(peers...),
+		ux.OptionInitiator(displayname),
 	)
 
 	conn = grpcx.UntilSuccess(gctx.Context, func(ictx context.Context) (*grpc.ClientConn, error) {
@@ -145,105 +161,232 @@ func Into(gctx *Context) error {
 
 	events <- agent.LogEvent(local, "connected to cluster")
 
+	if darchive, err = uploadArchive(gctx, config, ss, conn, client, events, local, displayname, commitish); err != nil {
+		events <- agent.LogError(local, err)
+		events <- agent.LogEvent(local, "deployment failed")
+		return err
+	}
+
+	max := gctx.Concurrency
+	if gctx.Concurrency == 0 {
+		max = int64(config.Partitioner().Partition(len(c.Members())))
+	}
+
+	dopts := agent.DeployOptions{
+		Concurrency:       max,
+		Timeout:           int64(config.Deployment.Timeout),
+		Heartbeat:         int64(gctx.Heartbeat),
+		IgnoreFailures:    gctx.Lenient,
+		SilenceDeployLogs: gctx.Silent,
+		Prestage:          gctx.Prestage || config.Deployment.Prestage,
+		PrestageTimeout:   int64(config.Deployment.PrestageTimeout),
+	}
+
+	if len(peers) == 0 && !gctx.AllowEmpty {
+		cause := errorsx.String("deployment failed, filter did not match any servers")
+		events <- agent.LogError(local, cause)
+		return cause
+	}
+
+	events <- agent.LogEvent(local, fmt.Sprintf("deploy initiated: by(%s) concurrency(%d), deployID(%s)", displayname, max, bw.RandomID(darchive.DeploymentID)))
+	if cause := client.RemoteDeploy(gctx.Context, displayname, &dopts, darchive, peers...); cause != nil {
+		events <- agent.LogError(local, errors.Wrap(cause, "deploy failed"))
+		events <- agent.DeployEventFailed(local, displayname, &dopts, darchive, cause)
+		events <- agent.NewDeployCommand(local, agent.DeployCommandFailed(displayname, darchive.DeployOption, dopts.DeployOption, agent.DeployCommandOptionError(cause)))
+		return cause
+	}
+
+	<-dctx.Done()
+	if cause := context.Cause(dctx); errorsx.Ignore(cause, context.Canceled) != nil {
+		return cause
+	}
+
+	return nil
+}
+
+// uploadArchive packs the deployspace into a signed archive and uploads it to the cluster.
+// unchanged reproducible archives reuse the deployed archive instead of being uploaded.
+func uploadArchive(gctx *Context, config agent.ConfigClient, ss notary.Signer, conn *grpc.ClientConn, client agent.DeployConn, events chan *agent.Message, local *agent.Peer, displayname, commitish string) (darchive *agent.Archive, err error) {
+	var (
+		dst       *os.File
+		dstinfo   os.FileInfo
+		size      int64
+		digest    []byte
+		signature *agent.ArchiveSignature
+		chunks    []*agent.ArchiveChunk
+		held      *agent.Archive
+	)
+
 	deployspace := config.Deployspace()
 	if err = os.WriteFile(filepath.Join(deployspace, bw.EnvFile), []byte(config.Environment), 0600); err != nil {
-		return err
+		return nil, errors.Wrap(err, "unable to write environment file")
 	}
 
 	if _, err := os.Stat(filepath.Join(config.Dir(), bw.AuthKeysFile)); !os.IsNotExist(err) {
 		if err = iox.Copy(filepath.Join(config.Dir(), bw.AuthKeysFile), filepath.Join(deployspace, bw.AuthKeysFile)); err != nil {
-			return err
+			return nil, errors.Wrap(err, "unable to copy authorized keys")
 		}
 	}
 
 	if dst, err = os.CreateTemp("", "bwarchive"); err != nil {
-		events <- agent.LogError(local, errors.Wrap(err, "archive creation failed"))
-		events <- agent.LogEvent(local, "deployment failed")
-		return nil
+		return nil, errors.Wrap(err, "archive creation failed")
 	}
 	defer os.Remove(dst.Name())
 	defer dst.Close()
 
-	if err = archive.Pack(dst, deployspace); err != nil {
-		return err
+	if size, err = archive.Size(deployspace); err != nil {
+		return nil, errors.Wrap(err, "archive creation failed")
+	}
+
+	packing := newTransfer(events, local, "packing archive", size)
+	err = packer(config, archive.PackOptionProgress(packing.Add)).Pack(dst, deployspace)
+	packing.Stop()
+	if err != nil {
+		return nil, errors.Wrap(err, "archive creation failed")
 	}
 
 	if dstinfo, err = dst.Stat(); err != nil {
-		events <- agent.LogError(local, errors.Wrap(err, "archive creation failed"))
-		events <- agent.LogEvent(local, "deployment failed")
-		return nil
+		return nil, errors.Wrap(err, "archive creation failed")
+	}
+
+	if digest, err = archiveDigest(dst); err != nil {
+		return nil, errors.Wrap(err, "archive signing failed")
+	}
+
+	if signature, err = signArchive(ss, digest); err != nil {
+		return nil, err
+	}
+
+	if chunks, err = archiveChunks(dst); err != nil {
+		return nil, err
+	}
+
+	// chunks of the currently deployed archive are held by the quorum and do not need to be uploaded.
+	if info, cause := agent.NewQuorumClient(conn).Info(gctx.Context, &agent.InfoRequest{}); cause == nil {
+		held = info.GetDeployed().GetArchive()
+	} else {
+		log.Println("unable to retrieve the deployed archive, uploading the entire archive", cause)
+	}
+
+	// reproducible archives of unchanged contents match the deployed archive, reuse it instead of uploading.
+	if held != nil && bytes.Equal(held.Checksum, digest) {
+		darchive = proto.Clone(held).(*agent.Archive)
+		darchive.Commit = commitish
+		darchive.Signature = signature
+		darchive.Ts = time.Now().UTC().Unix()
+		events <- agent.LogEvent(local, fmt.Sprintf("archive unchanged, reusing deployed archive: location(%s)", darchive.Location))
+		return darchive, nil
 	}
 
+	// the session allows interrupted uploads to resume from the last acknowledged chunk.
+	session := uuid.Must(uuid.NewV4()).String()
+	uploading := newTransfer(events, local, "uploading archive", dstinfo.Size())
+
+	attempts := 0
 	events <- agent.LogEvent(local, "archive upload initiated")
 	err = grpcx.Retry(gctx.Context, func() error {
+		// sessions are held in memory by a single quorum node, retries that reach another node start over.
+		if attempts++; attempts > 1 {
+			events <- agent.LogEvent(local, "retrying archive upload, it restarts from the beginning unless it reaches the quorum node holding the session")
+		}
+
 		if _, err = dst.Seek(0, io.SeekStart); err != nil {
-			events <- agent.LogError(local, errors.Wrap(err, "archive creation failed"))
-			events <- agent.LogEvent(local, "deployment failed")
-			return nil
+			return errors.Wrap(err, "archive creation failed")
 		}
 
 		meta := agent.UploadMetadata{
 			Bytes:     uint64(dstinfo.Size()),
 			Vcscommit: commitish,
+			Signature: signature,
+			Chunks:    chunks,
+			Session:   session,
 		}
 
-		if darchive, err = client.Upload(gctx.Context, &meta, dst); errors.Is(err, io.EOF) {
+		if darchive, err = client.UploadDelta(gctx.Context, &meta, transferReader{ReadSeeker: dst, transfer: uploading}, held); errors.Is(err, io.EOF) {
 			// occassionally we'll run into an EOF, due to server restarts, or random network failures. ceorce
 			// the io.EOF error into a grpc codes.Unavailable error so the retry takes hold.
 			return status.Error(codes.Unavailable, err.Error())
 		} else if err != nil {
-			events <- agent.LogError(local, errors.Wrap(err, "archive upload failed"))
-			events <- agent.LogEvent(local, "deployment failed")
-			return err
+			return errors.Wrap(err, "archive upload failed")
 		}
 		return nil
-	}, codes.Unavailable)
+	}, codes.Unavailable, codes.Aborted, codes.OutOfRange)
+	uploading.Stop()
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	events <- agent.LogEvent(local, fmt.Sprintf("archive upload completed: who(%s) location(%s)", displayname, darchive.Location))
 
-	max := gctx.Concurrency
-	if gctx.Concurrency == 0 {
-		max = int64(config.Partitioner().Partition(len(c.Members())))
+	return darchive, nil
+}
+
+// archiveChunks content defined chunks of the archive.
+func archiveChunks(src io.ReadSeeker) (chunks []*agent.ArchiveChunk, err error) {
+	var (
+		cc []archive.Chunk
+	)
+
+	if _, err = src.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.Wrap(err, "archive chunking failed")
 	}
 
-	// only consider the canary node.
-	if gctx.Canary {
-		peers = agent.NodesToPeers(c.Get(rendezvous.Auto()))
-	} else {
-		peers = agent.NodesToPeers(c.Members()...)
+	if cc, err = archive.Chunks(src); err != nil {
+		return nil, errors.Wrap(err, "archive chunking failed")
 	}
 
-	peers = deployment.ApplyFilter(gctx.Filter, peers...)
-	dopts := agent.DeployOptions{
-		Concurrency:       max,
-		Timeout:           int64(config.Deployment.Timeout),
-		Heartbeat:         int64(gctx.Heartbeat),
-		IgnoreFailures:    gctx.Lenient,
-		SilenceDeployLogs: gctx.Silent,
+	for _, c := range cc {
+		chunks = append(chunks, &agent.ArchiveChunk{Digest: c.Digest, Length: uint64(c.Length)})
 	}
 
-	if len(peers) == 0 && !gctx.AllowEmpty {
-		cause := errorsx.String("deployment failed, filter did not match any servers")
-		events <- agent.LogError(local, cause)
-		return cause
+	return chunks, nil
+}
+
+// packer for the archives of the configuration.
+func packer(config agent.ConfigClient, options ...archive.PackOption) archive.Packer {
+	return archive.NewPacker(
+		append(
+			[]archive.PackOption{
+				archive.PackOptionCompression(config.Deployment.Archive.Compression, config.Deployment.Archive.Level),
+				archive.PackOptionReproducible(config.Deployment.Archive.Reproducible),
+			},
+			options...,
+		)...,
+	)
+}
+
+// archiveDigest sha256 digest of the archive, matches the checksum computed by the quorum.
+func archiveDigest(src io.ReadSeeker) (_ []byte, err error) {
+	digest := sha256.New()
+
+	if _, err = src.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.WithStack(err)
 	}
 
-	events <- agent.LogEvent(local, fmt.Sprintf("deploy initiated: by(%s) concurrency(%d), deployID(%s)", displayname, max, bw.RandomID(darchive.DeploymentID)))
-	if cause := client.RemoteDeploy(gctx.Context, displayname, &dopts, darchive, peers...); cause != nil {
-		events <- agent.LogError(local, errors.Wrap(cause, "deploy failed"))
-		events <- agent.DeployEventFailed(local, displayname, &dopts, darchive, cause)
-		events <- agent.NewDeployCommand(local, agent.DeployCommandFailed(displayname, darchive.DeployOption, dopts.DeployOption))
-		return cause
+	if _, err = io.Copy(digest, src); err != nil {
+		return nil, errors.WithStack(err)
 	}
 
-	<-dctx.Done()
-	if cause := context.Cause(dctx); errorsx.Ignore(cause, context.Canceled) != nil {
-		return cause
+	return digest.Sum(nil), nil
+}
+
+// signArchive signs the archive's digest so agents can verify who produced it and when.
+func signArchive(ss notary.Signer, digest []byte) (_ *agent.ArchiveSignature, err error) {
+	var (
+		fingerprint string
+		sig         *notary.Signature
+		signed      = time.Now()
+	)
+
+	if fingerprint, sig, err = ss.SignArchive(digest, signed); err != nil {
+		return nil, errors.Wrap(err, "archive signing failed")
 	}
 
-	return nil
+	return &agent.ArchiveSignature{
+		Fingerprint: fingerprint,
+		Format:      sig.Format,
+		Data:        sig.Data,
+		Ts:          signed.Unix(),
+	}, nil
 }
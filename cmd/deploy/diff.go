@@ -0,0 +1,70 @@
+package deploy
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/archive"
+	"github.com/pkg/errors"
+)
+
+// Diff writes the changes between a deployment's archive and a new snapshot of the environment.
+// the archive is read from the local snapshot file when provided, otherwise it is downloaded
+// from the cluster; the currently deployed archive is used when the deployment id is blank.
+func Diff(gctx *Context, deploymentID string, snapshotFile string, nameOnly bool, out io.Writer) (err error) {
+	var (
+		dir     string
+		config  agent.ConfigClient
+		changes []archive.Change
+	)
+
+	if dir, err = os.MkdirTemp("", "bw-diff-*"); err != nil {
+		return errors.Wrap(err, "failed to create diff directory")
+	}
+	defer os.RemoveAll(dir)
+
+	if snapshotFile != "" {
+		err = unpackFile(snapshotFile, dir)
+	} else {
+		_, err = retrieve(gctx, deploymentID, dir)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if config, err = snapshot(*gctx); err != nil {
+		return err
+	}
+
+	if changes, err = archive.Diff(dir, config.Deployment.DataDir); err != nil {
+		return errors.Wrap(err, "failed to diff archive")
+	}
+
+	for _, c := range changes {
+		fmt.Fprintf(out, "%-8s %s\n", c.Kind, c.Path)
+	}
+
+	if nameOnly || len(changes) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out)
+
+	return archive.Patch(out, dir, config.Deployment.DataDir, changes...)
+}
+
+func unpackFile(path string, dir string) (err error) {
+	var (
+		src *os.File
+	)
+
+	if src, err = os.Open(path); err != nil {
+		return errors.Wrap(err, "failed to open snapshot")
+	}
+	defer src.Close()
+
+	return errors.Wrapf(archive.Unpack(dir, src), "failed to unpack snapshot: %s", path)
+}
@@ -0,0 +1,203 @@
+package deploy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/agentutil"
+	"github.com/james-lawrence/bw/archive"
+	"github.com/james-lawrence/bw/cluster"
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/james-lawrence/bw/cmd/commandutils"
+	"github.com/james-lawrence/bw/daemons"
+	"github.com/james-lawrence/bw/internal/envx"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/grpcx"
+	"github.com/james-lawrence/bw/internal/iox"
+	"github.com/james-lawrence/bw/notary"
+	"github.com/james-lawrence/bw/vcsinfo"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// Inspect writes the contents of the deployment's archive and its bw.env.
+func Inspect(gctx *Context, deploymentID string, out io.Writer) (err error) {
+	var (
+		dir     string
+		a       *agent.Archive
+		entries []archive.Entry
+		env     []byte
+	)
+
+	if dir, err = os.MkdirTemp("", "bw-inspect-*"); err != nil {
+		return errors.Wrap(err, "failed to create inspect directory")
+	}
+	defer os.RemoveAll(dir)
+
+	if a, err = retrieve(gctx, deploymentID, dir); err != nil {
+		return err
+	}
+
+	if entries, err = archive.List(dir); err != nil {
+		return errors.Wrap(err, "failed to list archive")
+	}
+
+	fmt.Fprintf(out, "deployment: %s\n", bw.RandomID(a.DeploymentID))
+	fmt.Fprintf(out, "location: %s\n", a.Location)
+	fmt.Fprintf(out, "commit: %s\n", a.Commit)
+	fmt.Fprintln(out)
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', tabwriter.AlignRight)
+	for _, e := range entries {
+		if !e.Mode.IsRegular() {
+			continue
+		}
+
+		fmt.Fprintf(tw, "%s\t%d\t%s\t\n", e.Mode, e.Size, e.Path)
+	}
+
+	if err = tw.Flush(); err != nil {
+		return errors.Wrap(err, "failed to write listing")
+	}
+
+	if env, err = os.ReadFile(filepath.Join(dir, bw.EnvFile)); os.IsNotExist(err) {
+		fmt.Fprintf(out, "\n%s: missing\n", bw.EnvFile)
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to read %s", bw.EnvFile)
+	}
+
+	fmt.Fprintf(out, "\n%s:\n%s", bw.EnvFile, env)
+	if len(env) > 0 && env[len(env)-1] != '\n' {
+		fmt.Fprintln(out)
+	}
+
+	return nil
+}
+
+// retrieve downloads and unpacks the deployment's archive into the directory.
+// the currently deployed archive is retrieved when the deployment id is blank.
+func retrieve(gctx *Context, deploymentID string, dir string) (a *agent.Archive, err error) {
+	var (
+		conn   *grpc.ClientConn
+		d      dialers.Defaults
+		config agent.ConfigClient
+		c      clustering.Rendezvous
+		ss     notary.Signer
+	)
+
+	if config, err = commandutils.LoadConfiguration(gctx.Context, gctx.Environment, agent.CCOptionInsecure(gctx.Insecure)); err != nil {
+		return nil, errors.Wrap(err, "unable to load configuration")
+	}
+
+	if ss, err = notary.NewAutoSigner(vcsinfo.CurrentUserDisplay(config.WorkDir())); err != nil {
+		return nil, errors.Wrap(err, "unable to setup authorization")
+	}
+
+	var (
+		debugopt1 grpc.DialOption = grpc.EmptyDialOption{}
+		debugopt2 grpc.DialOption = grpc.EmptyDialOption{}
+	)
+
+	if gctx.Debug || envx.Boolean(false, bw.EnvLogsGRPC, bw.EnvLogsVerbose) {
+		debugopt1 = grpc.WithUnaryInterceptor(grpcx.DebugClientIntercepter)
+		debugopt2 = grpc.WithStreamInterceptor(grpcx.DebugClientStreamIntercepter)
+	}
+
+	if d, c, err = daemons.ConnectClientUntilSuccess(gctx.Context, config, ss, debugopt1, debugopt2, grpc.WithPerRPCCredentials(ss)); err != nil {
+		return nil, errors.Wrap(err, "unable to connect to cluster")
+	}
+
+	qd := dialers.NewQuorum(c, d.Defaults()...)
+
+	if conn, err = qd.DialContext(gctx.Context); err != nil {
+		return nil, errors.Wrap(err, "unable to connect to quorum")
+	}
+	defer func() {
+		errorsx.Log(errors.Wrap(conn.Close(), "failed to close connection"))
+	}()
+
+	if a, err = locateArchive(gctx, conn, cluster.New(commandutils.NewClientPeer(), c), qd, deploymentID); err != nil {
+		return nil, err
+	}
+
+	log.Println("downloading archive", bw.RandomID(a.DeploymentID), a.Location)
+
+	src := agent.NewDeployConn(conn).Download(gctx.Context, a)
+	defer src.Close()
+
+	return a, unpackVerified(a, src, dir)
+}
+
+// locateArchive resolves the archive of the deployment, the deployed archive when the deployment id is blank.
+func locateArchive(gctx *Context, conn grpc.ClientConnInterface, c cluster.Cluster, qd dialers.Defaults, deploymentID string) (_ *agent.Archive, err error) {
+	var (
+		info     *agent.InfoResponse
+		located  *agent.Deploy
+		deployed *agent.Archive
+	)
+
+	if deploymentID == "" {
+		if info, err = agent.NewQuorumClient(conn).Info(gctx.Context, &agent.InfoRequest{}); err != nil {
+			return nil, errors.Wrap(err, "unable to retrieve the deployed archive")
+		}
+
+		if deployed = info.GetDeployed().GetArchive(); deployed == nil {
+			return nil, errorsx.String("no archive has been deployed to the environment")
+		}
+
+		return deployed, nil
+	}
+
+	if staged := stagedArchive(gctx.Context, conn, deploymentID); staged != nil {
+		return staged, nil
+	}
+
+	if located, err = agentutil.LocateDeployment(c, qd, agentutil.FilterDeployID(deploymentID)); err != nil {
+		return nil, errors.Wrap(err, "archive retrieval failed")
+	}
+
+	if located.Archive == nil {
+		return nil, errors.New("archive retrieval failed, deployment found but archive is nil")
+	}
+
+	return located.Archive, nil
+}
+
+// unpackVerified unpacks the archive read from src into the directory once its checksum is verified.
+func unpackVerified(a *agent.Archive, src io.Reader, dir string) (err error) {
+	var (
+		dst    *os.File
+		digest = sha256.New()
+	)
+
+	if dst, err = os.CreateTemp("", "bw-archive-*"); err != nil {
+		return errors.Wrap(err, "unable to open archive file")
+	}
+	defer os.Remove(dst.Name())
+	defer dst.Close()
+
+	if _, err = io.Copy(io.MultiWriter(dst, digest), src); err != nil {
+		return errors.Wrap(err, "retrieve archive")
+	}
+
+	if sum := digest.Sum(nil); !bytes.Equal(a.Checksum, sum) {
+		return errors.Errorf("checksums mismatch: archive(%s), downloaded(%s)", hex.EncodeToString(a.Checksum), hex.EncodeToString(sum))
+	}
+
+	if err = iox.Rewind(dst); err != nil {
+		return errors.Wrap(err, "unable to rewind archive")
+	}
+
+	return errors.Wrap(archive.Unpack(dir, dst), "unpack archive")
+}
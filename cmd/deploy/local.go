@@ -82,7 +82,7 @@ func Locally(ctx *Context, debug bool) (err error) {
 	defer os.Remove(dst.Name())
 	defer dst.Close()
 
-	if err = archive.Pack(dst, filepath.Join(config.WorkDir(), config.Deployment.DataDir)); err != nil {
+	if err = packer(config).Pack(dst, filepath.Join(config.WorkDir(), config.Deployment.DataDir)); err != nil {
 		return errors.Wrap(err, "failed to pack archive")
 	}
 
@@ -0,0 +1,92 @@
+package deploy
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/james-lawrence/bw/agent"
+)
+
+// newTransfer periodically reports the progress of the transfer until stopped.
+func newTransfer(events chan *agent.Message, local *agent.Peer, description string, total int64) *transfer {
+	t := &transfer{
+		events:      events,
+		local:       local,
+		description: description,
+		total:       uint64(max(total, 0)),
+		started:     time.Now(),
+		done:        make(chan struct{}),
+		stopped:     &sync.WaitGroup{},
+	}
+
+	t.stopped.Add(1)
+	go t.report(time.Second)
+
+	return t
+}
+
+type transfer struct {
+	events      chan *agent.Message
+	local       *agent.Peer
+	description string
+	total       uint64
+	completed   atomic.Uint64
+	started     time.Time
+	done        chan struct{}
+	stopped     *sync.WaitGroup
+}
+
+// Add to the number of bytes transferred.
+func (t *transfer) Add(n int64) {
+	t.completed.Add(uint64(n))
+}
+
+// Stop reporting, the final progress is always reported.
+func (t *transfer) Stop() {
+	close(t.done)
+	t.stopped.Wait()
+	t.emit()
+}
+
+func (t *transfer) emit() {
+	t.events <- agent.TransferEvent(t.local, t.description, t.completed.Load(), t.total, time.Since(t.started))
+}
+
+func (t *transfer) report(d time.Duration) {
+	defer t.stopped.Done()
+
+	tick := time.NewTicker(d)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-tick.C:
+			t.emit()
+		}
+	}
+}
+
+// transferReader tracks the position of the reader as the progress of the transfer.
+type transferReader struct {
+	io.ReadSeeker
+	*transfer
+}
+
+func (t transferReader) Read(b []byte) (n int, err error) {
+	n, err = t.ReadSeeker.Read(b)
+	t.transfer.Add(int64(n))
+	return n, err
+}
+
+func (t transferReader) Seek(offset int64, whence int) (n int64, err error) {
+	if n, err = t.ReadSeeker.Seek(offset, whence); err != nil {
+		return n, err
+	}
+
+	t.transfer.completed.Store(uint64(n))
+	return n, nil
+}
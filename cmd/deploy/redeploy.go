@@ -96,6 +96,17 @@ func Redeploy(gctx *Context, deploymentID string) error {
 
 	client = agent.NewDeployConn(conn)
 
+	cx := cluster.New(local, c)
+
+	// only consider the canary node.
+	if gctx.Canary {
+		peers = agent.NodesToPeers(cx.Get(rendezvous.Auto()))
+	} else {
+		peers = cx.Peers()
+	}
+
+	peers = deployment.ApplyFilter(gctx.Filter, peers...)
+
 	dctx, failurefn := context.WithCancelCause(gctx.Context)
 	defer failurefn(nil)
 
@@ -103,6 +114,9 @@ func Redeploy(gctx *Context, deploymentID string) error {
 		dctx, failurefn, config, qd, local, events,
 		ux.OptionHeartbeat(gctx.Heartbeat),
 		ux.OptionDebug(gctx.Verbose),
+		ux.OptionInteractive(gctx.Interactive),
+		ux.OptionPeers(peers...),
+		ux.OptionInitiator(displayname),
 	)
 
 	events <- agent.LogEvent(local, "connected to cluster")
@@ -113,40 +127,38 @@ func Redeploy(gctx *Context, deploymentID string) error {
 		}
 	}()
 
-	cx := cluster.New(local, c)
-	if located, err = agentutil.LocateDeployment(cx, qd, agentutil.FilterDeployID(deploymentID)); err != nil {
-		events <- agent.LogError(local, errors.Wrap(err, "archive retrieval failed"))
-		events <- agent.LogEvent(local, "deployment failed")
-		return err
-	}
+	// archives staged ahead of their deploy are recorded by the quorum, the nodes already hold them.
+	if archive = stagedArchive(gctx.Context, conn, deploymentID); archive != nil {
+		events <- agent.LogEvent(local, fmt.Sprintf("located staged archive: who(%s) location(%s)", displayname, archive.Location))
+	} else {
+		if located, err = agentutil.LocateDeployment(cx, qd, agentutil.FilterDeployID(deploymentID)); err != nil {
+			events <- agent.LogError(local, errors.Wrap(err, "archive retrieval failed"))
+			events <- agent.LogEvent(local, "deployment failed")
+			return err
+		}
 
-	if located.Archive == nil {
-		err = errors.New("archive retrieval failed, deployment found but archive is nil")
-		events <- agent.LogError(local, err)
-		events <- agent.LogEvent(local, "deployment failed")
-		return err
-	}
+		if located.Archive == nil {
+			err = errors.New("archive retrieval failed, deployment found but archive is nil")
+			events <- agent.LogError(local, err)
+			events <- agent.LogEvent(local, "deployment failed")
+			return err
+		}
 
-	archive = located.Archive
+		archive = located.Archive
 
-	events <- agent.LogEvent(local, fmt.Sprintf("located: who(%s) location(%s)", displayname, archive.Location))
+		events <- agent.LogEvent(local, fmt.Sprintf("located: who(%s) location(%s)", displayname, archive.Location))
+	}
 
 	max := int64(config.Partitioner().Partition(len(cx.Members())))
 
-	// only consider the canary node.
-	if gctx.Canary {
-		peers = agent.NodesToPeers(cx.Get(rendezvous.Auto()))
-	} else {
-		peers = cx.Peers()
-	}
-
-	peers = deployment.ApplyFilter(gctx.Filter, peers...)
 	dopts := agent.DeployOptions{
 		Concurrency:       max,
 		Timeout:           int64(config.Deployment.Timeout),
 		Heartbeat:         int64(gctx.Heartbeat),
 		IgnoreFailures:    gctx.Lenient,
 		SilenceDeployLogs: gctx.Silent,
+		Prestage:          gctx.Prestage || config.Deployment.Prestage,
+		PrestageTimeout:   int64(config.Deployment.PrestageTimeout),
 	}
 
 	if len(peers) == 0 && !gctx.AllowEmpty {
@@ -167,3 +179,18 @@ func Redeploy(gctx *Context, deploymentID string) error {
 
 	return nil
 }
+
+// stagedArchive returns the archive staged for the deployment, nil when the deployment isn't staged.
+func stagedArchive(ctx context.Context, conn grpc.ClientConnInterface, deploymentID string) *agent.Archive {
+	info, err := agent.NewQuorumClient(conn).Info(ctx, &agent.InfoRequest{})
+	if err != nil {
+		log.Println("unable to retrieve the staged archive", err)
+		return nil
+	}
+
+	if staged := info.GetStaged().GetArchive(); staged != nil && bw.RandomID(staged.DeploymentID).String() == deploymentID {
+		return staged
+	}
+
+	return nil
+}
@@ -1,10 +1,12 @@
 package deploy
 
 import (
+	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"text/tabwriter"
 
 	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/agent"
@@ -19,31 +21,70 @@ func Snapshot(ctx Context, out io.WriteCloser) (err error) {
 		config agent.ConfigClient
 	)
 
+	if config, err = snapshot(ctx); err != nil {
+		return err
+	}
+
+	defer out.Close()
+
+	if err = packer(config).Pack(out, config.Deployment.DataDir); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SnapshotList writes the files that would be included in the snapshot and their sizes.
+func SnapshotList(ctx Context, out io.Writer) (err error) {
+	var (
+		config  agent.ConfigClient
+		entries []archive.Entry
+		total   int64
+	)
+
+	if config, err = snapshot(ctx); err != nil {
+		return err
+	}
+
+	if entries, err = archive.List(config.Deployment.DataDir); err != nil {
+		return errors.Wrap(err, "failed to list archive")
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', tabwriter.AlignRight)
+	for _, e := range entries {
+		if !e.Mode.IsRegular() {
+			continue
+		}
+
+		total += e.Size
+		fmt.Fprintf(tw, "%d\t%s\t\n", e.Size, e.Path)
+	}
+	fmt.Fprintf(tw, "%d\t%s\t\n", total, "total")
+
+	return errors.Wrap(tw.Flush(), "failed to write listing")
+}
+
+// snapshot prepares the deployspace of the environment for packing.
+func snapshot(ctx Context) (config agent.ConfigClient, err error) {
 	if config, err = commandutils.ReadConfiguration(ctx.Environment); err != nil {
-		return errors.Wrap(err, "failed to load configuration")
+		return config, errors.Wrap(err, "failed to load configuration")
 	}
 
 	log.Println("pid", os.Getpid())
 
 	if err = os.WriteFile(filepath.Join(config.Deployment.DataDir, bw.EnvFile), []byte(config.Environment), 0600); err != nil {
-		return errors.Wrap(err, "failed to crreate bw.env")
+		return config, errors.Wrap(err, "failed to crreate bw.env")
 	}
 
 	if _, err = commandutils.RunLocalDirectives(ctx.Context, config); err != nil {
-		return errors.Wrap(err, "failed to run local directives")
+		return config, errors.Wrap(err, "failed to run local directives")
 	}
 
 	if _, err := os.Stat(filepath.Join(config.Dir(), bw.AuthKeysFile)); !os.IsNotExist(err) {
 		if err = iox.Copy(filepath.Join(config.Dir(), bw.AuthKeysFile), filepath.Join(config.Deployment.DataDir, bw.AuthKeysFile)); err != nil {
-			return err
+			return config, err
 		}
 	}
 
-	defer out.Close()
-
-	if err = archive.Pack(out, config.Deployment.DataDir); err != nil {
-		return err
-	}
-
-	return nil
+	return config, nil
 }
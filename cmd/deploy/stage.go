@@ -0,0 +1,146 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/clustering"
+	"github.com/james-lawrence/bw/clustering/rendezvous"
+	"github.com/james-lawrence/bw/cmd/commandutils"
+	"github.com/james-lawrence/bw/cmd/termui"
+	"github.com/james-lawrence/bw/daemons"
+	"github.com/james-lawrence/bw/deployment"
+	"github.com/james-lawrence/bw/internal/envx"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/grpcx"
+	"github.com/james-lawrence/bw/notary"
+	"github.com/james-lawrence/bw/ux"
+	"github.com/james-lawrence/bw/vcsinfo"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+// Stage uploads the archive and distributes it to the nodes of the environment ahead
+// of its deploy. deploying the staged archive only runs its directives.
+func Stage(gctx *Context) (err error) {
+	var (
+		conn      *grpc.ClientConn
+		d         dialers.Defaults
+		client    agent.DeployConn
+		config    agent.ConfigClient
+		c         clustering.Rendezvous
+		ss        notary.Signer
+		darchive  *agent.Archive
+		staged    *agent.Staged
+		peers     []*agent.Peer
+		commitish string
+	)
+
+	if config, err = commandutils.LoadConfiguration(gctx.Context, gctx.Environment, agent.CCOptionInsecure(gctx.Insecure)); err != nil {
+		return errors.Wrap(err, "unable to load configuration")
+	}
+
+	displayname := vcsinfo.CurrentUserDisplay(config.WorkDir())
+
+	if ss, err = notary.NewAutoSigner(displayname); err != nil {
+		return errors.Wrap(err, "unable to setup authorization")
+	}
+
+	log.Println("pid", os.Getpid())
+
+	if commitish, err = commandutils.RunLocalDirectives(gctx.Context, config); err != nil {
+		return errors.Wrap(err, "failed to run local directives")
+	}
+
+	if !commandutils.RemoteTasksAvailable(config) {
+		log.Println("no directives to run by the cluster")
+		return nil
+	}
+
+	events := make(chan *agent.Message, 100)
+
+	local := commandutils.NewClientPeer(
+		agent.PeerOptionName("local"),
+	)
+
+	events <- agent.LogEvent(local, "connecting to cluster")
+	var (
+		debugopt1 grpc.DialOption = grpc.EmptyDialOption{}
+		debugopt2 grpc.DialOption = grpc.EmptyDialOption{}
+	)
+
+	if gctx.Debug || gctx.Verbose || envx.Boolean(false, bw.EnvLogsGRPC, bw.EnvLogsVerbose) {
+		debugopt1 = grpc.WithUnaryInterceptor(grpcx.DebugClientIntercepter)
+		debugopt2 = grpc.WithStreamInterceptor(grpcx.DebugClientStreamIntercepter)
+	}
+
+	if d, c, err = daemons.ConnectClientUntilSuccess(gctx.Context, config, ss, debugopt1, debugopt2, grpc.WithPerRPCCredentials(ss)); err != nil {
+		return errors.Wrap(err, "unable to connect to cluster")
+	}
+
+	qd := dialers.NewQuorum(c, d.Defaults()...)
+
+	// only consider the canary node.
+	if gctx.Canary {
+		peers = agent.NodesToPeers(c.Get(rendezvous.Auto()))
+	} else {
+		peers = agent.NodesToPeers(c.Members()...)
+	}
+
+	peers = deployment.ApplyFilter(gctx.Filter, peers...)
+
+	if len(peers) == 0 && !gctx.AllowEmpty {
+		return errorsx.String("staging failed, filter did not match any servers")
+	}
+
+	dctx, failurefn := context.WithCancelCause(gctx.Context)
+	defer failurefn(nil)
+	termui.NewLogging(
+		dctx, failurefn, qd, local, events,
+		ux.OptionHeartbeat(gctx.Heartbeat),
+		ux.OptionDebug(gctx.Verbose),
+	)
+
+	conn = grpcx.UntilSuccess(gctx.Context, func(ictx context.Context) (*grpc.ClientConn, error) {
+		return qd.DialContext(ictx)
+	})
+
+	go func() {
+		<-gctx.Context.Done()
+		errorsx.Log(errors.Wrap(conn.Close(), "failed to close connection"))
+	}()
+
+	client = agent.NewDeployConn(conn)
+
+	events <- agent.LogEvent(local, "connected to cluster")
+
+	if darchive, err = uploadArchive(gctx, config, ss, conn, client, events, local, displayname, commitish); err != nil {
+		events <- agent.LogError(local, err)
+		events <- agent.LogEvent(local, "staging failed")
+		return err
+	}
+
+	events <- agent.LogEvent(local, fmt.Sprintf("staging initiated: by(%s) deployID(%s)", displayname, bw.RandomID(darchive.DeploymentID)))
+
+	sctx, done := context.WithTimeout(gctx.Context, config.Deployment.Timeout)
+	defer done()
+
+	if staged, err = client.Stage(sctx, displayname, darchive, peers...); err != nil {
+		events <- agent.LogError(local, errors.Wrap(err, "staging failed"))
+		return err
+	}
+
+	log.Printf(
+		"archive staged on %d node(s), deploy it with: bw deploy archive %s %s\n",
+		len(staged.Peers),
+		gctx.Environment,
+		bw.RandomID(staged.Archive.DeploymentID),
+	)
+
+	return nil
+}
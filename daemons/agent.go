@@ -2,6 +2,7 @@ package daemons
 
 import (
 	"net"
+	"time"
 
 	"github.com/hashicorp/raft"
 	"github.com/pkg/errors"
@@ -18,26 +19,48 @@ import (
 	"github.com/james-lawrence/bw/agentutil"
 	"github.com/james-lawrence/bw/certificatecache"
 	"github.com/james-lawrence/bw/deployment"
+	"github.com/james-lawrence/bw/internal/stringsx"
 	"github.com/james-lawrence/bw/notary"
 	"github.com/james-lawrence/bw/storage"
 )
 
 // Agent daemon - rpc endpoint for the system.
-func Agent(dctx Context, upload storage.UploadProtocol, download storage.DownloadProtocol) (err error) {
+func Agent(dctx Context, gc storage.TorrentGC, upload storage.UploadProtocol, downloads ...storage.DownloadProtocol) (err error) {
 	var (
 		bind         net.Listener
 		observersmem observers.Memory
-		dlreg        = storage.New(storage.OptionProtocols(download))
+		chunks       = deployment.NewChunkIndex(bw.DeployDir(dctx.Config.Root))
+		dlreg        = storage.New(storage.OptionProtocols(downloads...), storage.OptionChunks(chunks))
+		auditing     []quorum.Option
+		issuers      []notary.OIDC
+		keys         certificatecache.KeyStore
+		sealing      [][]byte
 	)
 
 	if observersmem, err = observers.NewMemory(); err != nil {
 		return err
 	}
 
+	if auditing, err = auditSink(dctx); err != nil {
+		return err
+	}
+
+	if issuers, err = oidcIssuers(dctx); err != nil {
+		return err
+	}
+
 	qdialer := dialers.NewQuorum(
 		dctx.Cluster,
 		dctx.Dialer.Defaults()...,
 	)
+
+	if keys, err = authorityKeys(dctx, qdialer); err != nil {
+		return err
+	}
+
+	if sealing, err = authoritySecrets(dctx.Config); err != nil {
+		return err
+	}
 	dispatcher := agentutil.NewDispatcher(qdialer)
 
 	coordinator := deployment.New(
@@ -46,22 +69,35 @@ func Agent(dctx Context, upload storage.UploadProtocol, download storage.Downloa
 		deployment.CoordinatorOptionDispatcher(dispatcher),
 		deployment.CoordinatorOptionRoot(dctx.Config.Root),
 		deployment.CoordinatorOptionKeepN(dctx.Config.KeepN),
+		deployment.CoordinatorOptionLogRetention(logRetention(dctx)),
+		deployment.CoordinatorOptionLogShipper(logShipper(dctx)),
 		deployment.CoordinatorOptionDeployResults(dctx.Results),
 		deployment.CoordinatorOptionStorage(dlreg),
+		deployment.CoordinatorOptionVerifier(archiveVerifier(dctx)),
 	)
 
+	go pruneLogs(dctx, &coordinator, time.Hour)
+
 	server := grpc.NewServer(
 		// grpc.UnaryInterceptor(grpcx.DebugIntercepter),
 		// grpc.StreamInterceptor(grpcx.DebugStreamIntercepter),
 		grpc.KeepaliveParams(dctx.RPCKeepalive),
 		grpc.KeepaliveEnforcementPolicy(dctx.RPCKeepalivePolicy),
+		auditInterceptor(dctx, dispatcher),
 	)
 
 	agent.NewServer(
 		dctx.Cluster,
-		agent.ServerOptionAuth(notary.NewAgentAuth(dctx.NotaryAuth)),
+		agent.ServerOptionAuth(notary.NewAgentAuth(dctx.NotaryAuth, notary.AgentAuthOptionLocal(dctx.Config.Peer()))),
 		agent.ServerOptionDeployer(&coordinator),
 		agent.ServerOptionShutdown(dctx.Shutdown),
+		agent.ServerOptionTLS(certificatecache.NewInspector(
+			dctx.Config.Credentials.Directory,
+			dctx.Config.CA,
+			stringsx.DefaultIfBlank(dctx.Config.Credentials.Mode, certificatecache.ModeACME),
+			agentRefreshOffset,
+		)),
+		agent.ServerOptionStorage(gc),
 	).Bind(server)
 
 	q := quorum.New(
@@ -72,31 +108,47 @@ func Agent(dctx Context, upload storage.UploadProtocol, download storage.Downloa
 		),
 		upload,
 		dctx.Raft,
-		quorum.OptionDialer(qdialer),
+		append(auditing, quorum.OptionDialer(qdialer), quorum.OptionChunks(chunks), quorum.OptionAuthoritySecret(sealing[0]))...,
 	)
 	go (&q).Observe(make(chan raft.Observation, 200))
 
 	agent.NewQuorum(
 		&q,
+		dctx.Cluster,
 		notary.NewAgentAuth(dctx.NotaryAuth),
 	).Bind(server)
 
-	notary.New(
+	authority := certificatecache.NewAuthorityCache(
+		dctx.Ring.GetPrimaryKey(),
 		dctx.Config.ServerName,
-		certificatecache.NewAuthorityCache(dctx.Ring.GetPrimaryKey(), dctx.Config.ServerName, dctx.Config.Credentials.Directory),
+		dctx.Config.Credentials.Directory,
+		certificatecache.AuthorityCacheOptionKeyStore(keys),
+		certificatecache.AuthorityCacheOptionReissue(agentReissue(dctx)),
+	)
+	nsvc := notary.New(
+		dctx.Config.ServerName,
+		authority,
 		dctx.NotaryStorage,
-	).Bind(server)
+		notary.OptionOIDC(issuers...),
+		notary.OptionRotation(authority),
+	)
+	nsvc.Bind(server)
+
+	if err = oidcExchange(dctx, nsvc, dispatcher); err != nil {
+		return err
+	}
 
 	notary.NewSyncService(
 		dctx.NotaryAuth,
 		dctx.NotaryStorage,
+		notary.SyncServiceOptionRetention(dctx.Config.Signatures.Retention),
 	).Bind(server)
 
 	debug.NewService(
-		notary.NewAgentAuth(dctx.NotaryAuth),
+		notary.NewAgentAuth(dctx.NotaryAuth, notary.AgentAuthOptionLocal(dctx.Config.Peer())),
 	).Bind(server)
 
-	proxy.NewDeployment(dctx.NotaryAuth, qdialer).Bind(server)
+	proxy.NewDeployment(dctx.NotaryAuth, dctx.Cluster, qdialer, dlreg).Bind(server)
 	acme.NewService(dctx.ACMECache, dctx.NotaryAuth).Bind(server)
 
 	if bind, err = dctx.Muxer.Bind(bw.ProtocolAgent, dctx.Listener.Addr()); err != nil {
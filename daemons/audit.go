@@ -0,0 +1,66 @@
+package daemons
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/debug"
+	"github.com/james-lawrence/bw/agent/quorum"
+	"github.com/james-lawrence/bw/notary"
+)
+
+// privileged rpcs recorded in the audit log.
+var auditedRPCs = []string{
+	notary.Notary_Grant_FullMethodName,
+	notary.Notary_Revoke_FullMethodName,
+	notary.Notary_Rotate_FullMethodName,
+	notary.Notary_Exchange_FullMethodName,
+	agent.Quorum_Deploy_FullMethodName,
+	agent.Quorum_Stage_FullMethodName,
+	agent.Quorum_Cancel_FullMethodName,
+	agent.Deployments_Deploy_FullMethodName,
+	agent.Deployments_Stage_FullMethodName,
+	agent.Deployments_Cancel_FullMethodName,
+	agent.Agent_Shutdown_FullMethodName,
+	agent.Agent_StorageGC_FullMethodName,
+	debug.Debug_CPU_FullMethodName,
+	debug.Debug_Memory_FullMethodName,
+	debug.Debug_Heap_FullMethodName,
+}
+
+// auditInterceptor records privileged rpcs into the audit log.
+func auditInterceptor(dctx Context, r agent.AuditRecorder) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(
+		agent.AuditInterceptor(dctx.NotaryAuth, r, auditedRPCs...),
+	)
+}
+
+// auditSink opens the configured audit log export.
+func auditSink(dctx Context) (options []quorum.Option, err error) {
+	var (
+		dst *os.File
+	)
+
+	if dctx.Config.Audit.Path == "" {
+		return options, nil
+	}
+
+	if err = os.MkdirAll(filepath.Dir(dctx.Config.Audit.Path), 0700); err != nil {
+		return options, errors.Wrap(err, "unable to create audit log directory")
+	}
+
+	if dst, err = os.OpenFile(dctx.Config.Audit.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600); err != nil {
+		return options, errors.Wrap(err, "unable to open audit log")
+	}
+
+	go func() {
+		<-dctx.Context.Done()
+		dst.Close()
+	}()
+
+	return append(options, quorum.OptionAuditSink(dst)), nil
+}
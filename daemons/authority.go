@@ -0,0 +1,83 @@
+package daemons
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/certificatecache"
+	"github.com/james-lawrence/bw/internal/cryptox"
+	"github.com/james-lawrence/bw/internal/stringsx"
+)
+
+// authorityKeys loads the key storage of the cluster's certificate authority.
+// external key storage must be shared by every node, e.g.) a network HSM or a single vault cluster,
+// otherwise each node would issue from a different authority.
+func authorityKeys(dctx Context, d dialers.ContextDialer) (_ certificatecache.KeyStore, err error) {
+	var (
+		client  *api.Client
+		secrets [][]byte
+		c       = dctx.Config.Authority
+	)
+
+	switch mode := stringsx.DefaultIfBlank(c.Keys, certificatecache.KeyStoreDisk); mode {
+	case certificatecache.KeyStoreDisk:
+		if secrets, err = authoritySecrets(dctx.Config); err != nil {
+			return nil, err
+		}
+
+		return certificatecache.NewDiskKeyStore(dctx.Config.Credentials.Directory, quorumKeySource(d, secrets...)), nil
+	case certificatecache.KeyStorePKCS11:
+		return certificatecache.NewPKCS11KeyStore(c.PKCS11.Module, c.PKCS11.Token, c.PKCS11.Pin, c.PKCS11.Label)
+	case certificatecache.KeyStoreVault:
+		if client, err = certificatecache.NewVaultClient(certificatecache.VaultDefaultTokenPath()); err != nil {
+			return nil, err
+		}
+
+		return certificatecache.NewVaultKeyStore(client, c.Vault.Mount, c.Vault.Key, c.Vault.Type), nil
+	default:
+		return nil, errors.Errorf("unknown authority key storage: %s", mode)
+	}
+}
+
+// authoritySecrets derives the keys sealing the authority keys retained by the quorum from the cluster tokens.
+// the primary token's key seals new keys, the secondary tokens open keys sealed before the tokens were rotated.
+func authoritySecrets(c agent.Config) ([][]byte, error) {
+	return clusterSecrets(c, "bw.authority")
+}
+
+// quorumKeySource retrieves the keys of the authority generations from the quorum.
+// the quorum generates a random key the first time a generation is requested,
+// seals it with the cluster's secret, and shares it with every agent of the cluster.
+func quorumKeySource(d dialers.ContextDialer, secrets ...[]byte) certificatecache.KeySource {
+	return func(g uint64) (_ []byte, err error) {
+		var (
+			conn *grpc.ClientConn
+			resp *agent.AuthorityResponse
+			key  []byte
+		)
+
+		ctx, done := context.WithTimeout(context.Background(), time.Minute)
+		defer done()
+
+		if conn, err = d.DialContext(ctx); err != nil {
+			return nil, errors.Wrap(err, "unable to connect to quorum")
+		}
+		defer conn.Close()
+
+		if resp, err = agent.NewQuorumClient(conn).Authority(ctx, &agent.AuthorityRequest{Generation: g}); err != nil {
+			return nil, errors.Wrap(err, "unable to retrieve authority key")
+		}
+
+		if key, err = cryptox.Open(resp.GetAuthority().GetKey(), secrets...); err != nil {
+			return nil, errors.Wrap(err, "unable to open authority key")
+		}
+
+		return key, nil
+	}
+}
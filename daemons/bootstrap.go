@@ -1,16 +1,19 @@
 package daemons
 
 import (
+	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agent/dialers"
 	"github.com/james-lawrence/bw/bootstrap"
+	"github.com/james-lawrence/bw/deployment"
+	"github.com/james-lawrence/bw/notary"
 	"github.com/james-lawrence/bw/storage"
 
 	"github.com/pkg/errors"
 )
 
 // Bootstrap daemon - pulls latest deploy from the cluster and ensures its running locally.
-func Bootstrap(ctx Context, download storage.DownloadProtocol) (err error) {
+func Bootstrap(ctx Context, downloads ...storage.DownloadProtocol) (err error) {
 	cx := ctx.Cluster
 	dialer := dialers.NewDirect(
 		agent.RPCAddress(cx.Local()),
@@ -37,12 +40,25 @@ func Bootstrap(ctx Context, download storage.DownloadProtocol) (err error) {
 
 	bus := bootstrap.NewUntilSuccess(
 		bootstrap.OptionMaxAttempts(ctx.Config.Bootstrap.Attempts),
+		bootstrap.OptionCoordinator(
+			deployment.CoordinatorOptionVerifier(archiveVerifier(ctx)),
+		),
 	)
 
-	if err = bus.Run(ctx.Context, ctx.Config, ctx.Deploys, download, ctx.Results); err != nil {
+	dlreg := storage.New(
+		storage.OptionProtocols(downloads...),
+		storage.OptionChunks(deployment.NewChunkIndex(bw.DeployDir(ctx.Config.Root))),
+	)
+
+	if err = bus.Run(ctx.Context, ctx.Config, ctx.Deploys, dlreg, ctx.Results); err != nil {
 		// if bootstrapping fails shutdown the process.
 		return errors.Wrap(err, "failed to bootstrap node shutting down")
 	}
 
 	return nil
 }
+
+// archiveVerifier verifies the archives deployed by the agent and its bootstrap services.
+func archiveVerifier(dctx Context) notary.ArchiveVerifier {
+	return notary.NewArchiveVerifier(dctx.NotaryStorage, notary.ArchiveVerifierOptionUnsigned(dctx.Config.Signatures.Unsigned))
+}
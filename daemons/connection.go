@@ -54,6 +54,25 @@ func Connect(ctx context.Context, config agent.ConfigClient, ss notary.Signer, o
 	return connect(ctx, config, ss, options...)
 }
 
+// ConnectExchange connects to the oidc exchange of the cluster, the exchange
+// is served without the proxy since callers don't have a grant yet.
+func ConnectExchange(config agent.ConfigClient, options ...grpc.DialOption) (d dialers.Direct, err error) {
+	var (
+		dd        dialers.Defaults
+		tlsconfig *tls.Config
+	)
+
+	if tlsconfig, err = certificatecache.TLSGenClient(config); err != nil {
+		return d, err
+	}
+
+	if dd, err = dialers.DefaultDialer(config.Address, tlsx.NewDialer(tlsconfig), options...); err != nil {
+		return d, err
+	}
+
+	return dialers.NewDirect(agent.URIExchange(config.Address), dd.Defaults()...), nil
+}
+
 // ConnectClientUntilSuccess continuously tries to make a connection until successful.
 func ConnectClientUntilSuccess(
 	ctx context.Context,
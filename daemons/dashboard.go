@@ -0,0 +1,95 @@
+package daemons
+
+import (
+	"crypto/sha256"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dashboard"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/notary"
+)
+
+// Dashboard serves the read only web dashboard for tls connections
+// that did not negotiate the muxer protocol. requires the search permission.
+func Dashboard(dctx Context) (err error) {
+	var (
+		l net.Listener
+	)
+
+	if !dctx.Config.Dashboard.Enabled {
+		return nil
+	}
+
+	if l, err = dctx.Muxer.Default(bw.ProtocolDashboard, dctx.Listener.Addr()); err != nil {
+		return errors.Wrap(err, "failed to bind dashboard protocol")
+	}
+
+	secrets, err := dashboardSecrets(dctx.Config)
+	if err != nil {
+		return err
+	}
+
+	d := dashboard.New(
+		secrets[0],
+		dctx.Cluster,
+		dctx.Dialer,
+		notary.NewAuthChecker(dctx.NotaryStorage, func(perm *notary.Permission) (err error) {
+			if perm.Search {
+				return nil
+			}
+
+			return errors.New("unauthorized")
+		}),
+		dctx.NotaryStorage,
+		dashboard.OptionSessionTTL(dctx.Config.Dashboard.Session),
+		dashboard.OptionPreviousSecrets(secrets[1:]...),
+	)
+
+	server := &http.Server{
+		Handler:           d.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	dctx.shutdown("dashboard", l)
+	log.Println("listening dashboard")
+	go func() {
+		errorsx.Log(errors.Wrap(errorsx.Ignore(server.Serve(l), io.EOF, http.ErrServerClosed), "dashboard failed"))
+	}()
+
+	return nil
+}
+
+// dashboardSecrets derives the keys signing dashboard sessions from the cluster tokens.
+// the keys are independent of the gossip keys, which are shared with every member of the
+// cluster, and the secondary tokens keep sessions valid while the tokens are rotated.
+func dashboardSecrets(c agent.Config) (secrets [][]byte, err error) {
+	return clusterSecrets(c, "bw.dashboard")
+}
+
+// clusterSecrets derives a key per cluster token for the given purpose, the primary token's key first.
+func clusterSecrets(c agent.Config, purpose string) (secrets [][]byte, err error) {
+	tokens := c.ClusterTokens
+	if len(tokens) == 0 {
+		tokens = []string{c.ServerName}
+	}
+
+	for _, token := range tokens {
+		secret := make([]byte, 32)
+		if _, err = io.ReadFull(hkdf.New(sha256.New, []byte(token), nil, []byte(purpose)), secret); err != nil {
+			return nil, errors.Wrapf(err, "unable to derive %s secret", purpose)
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, nil
+}
@@ -0,0 +1,86 @@
+package daemons
+
+import (
+	"crypto/tls"
+	"log"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/deployment"
+	"github.com/james-lawrence/bw/deployment/logship"
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+// logRetention the retention policy for deploy logs.
+func logRetention(dctx Context) deployment.LogRetention {
+	return deployment.LogRetention{
+		KeepN:         dctx.Config.Logs.KeepN,
+		MaxAge:        dctx.Config.Logs.MaxAge,
+		Compress:      dctx.Config.Logs.Compress,
+		CompressAfter: dctx.Config.Logs.CompressAfter,
+	}
+}
+
+// pruneLogs periodically applies the log retention policy, logs age out even when no deploys occur.
+func pruneLogs(dctx Context, c *deployment.Coordinator, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+
+	for {
+		errorsx.Log(errors.Wrap(c.PruneLogs(), "failed to prune deploy logs"))
+
+		select {
+		case <-dctx.Context.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// logShipper builds the shipper for the configured log destinations.
+// returns nil when no destinations are configured.
+func logShipper(dctx Context) logship.Shipper {
+	var (
+		shippers logship.Multi
+		c        = dctx.Config.Logs
+	)
+
+	if c.Syslog.Address != "" {
+		options := []logship.SyslogOption{
+			logship.SyslogOptionHostname(dctx.Config.Name),
+		}
+
+		if c.Syslog.TLS {
+			host, _, err := net.SplitHostPort(c.Syslog.Address)
+			if err != nil {
+				host = c.Syslog.Address
+			}
+
+			options = append(options, logship.SyslogOptionTLS(&tls.Config{
+				ServerName:         host,
+				InsecureSkipVerify: c.Syslog.Insecure,
+			}))
+		}
+
+		log.Println("shipping deploy logs to syslog", c.Syslog.Address)
+		shippers = append(shippers, logship.NewSyslog(dctx.Context, c.Syslog.Address, options...))
+	}
+
+	if c.Loki.Endpoint != "" {
+		log.Println("shipping deploy logs to loki", c.Loki.Endpoint)
+		shippers = append(shippers, logship.NewLoki(
+			dctx.Context,
+			c.Loki.Endpoint,
+			logship.LokiOptionTenant(c.Loki.Tenant),
+			logship.LokiOptionLabels(c.Loki.Labels),
+		))
+	}
+
+	if len(shippers) == 0 {
+		return nil
+	}
+
+	return shippers
+}
@@ -50,7 +50,7 @@ func SyncAuthorizations(dctx Context) {
 		}
 
 		log.Println("syncing credentials initiated", agent.RPCAddress(p))
-		if err = notary.Sync(stream, b, dctx.NotaryStorage); err != nil {
+		if err = notary.Sync(stream, b, dctx.NotaryStorage, dctx.Config.Signatures.Retention); err != nil {
 			return errors.Wrap(err, "syncing credentials failed")
 		}
 		log.Println("syncing credentials completed", agent.RPCAddress(p))
@@ -64,3 +64,33 @@ func SyncAuthorizations(dctx Context) {
 		}
 	}
 }
+
+// CollectExpiredAuthorizations periodically removes expired grants from the notary storage.
+// grants are retained after they expire so the archives they signed remain deployable.
+func CollectExpiredAuthorizations(dctx Context, interval time.Duration) {
+	gc := func() {
+		removed, err := dctx.NotaryStorage.GC(notary.RetentionCutoff(time.Now(), dctx.Config.Signatures.Retention))
+		if err != nil {
+			log.Println("authorization garbage collection failed", err)
+		}
+
+		for _, g := range removed {
+			log.Println("removed expired authorization", g.Fingerprint)
+		}
+	}
+
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+
+		for {
+			gc()
+
+			select {
+			case <-dctx.Context.Done():
+				return
+			case <-t.C:
+			}
+		}
+	}()
+}
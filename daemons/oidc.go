@@ -0,0 +1,58 @@
+package daemons
+
+import (
+	"net"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/notary"
+)
+
+// oidcIssuers loads the oidc issuers trusted by the notary service.
+func oidcIssuers(dctx Context) (options []notary.OIDC, err error) {
+	for _, c := range dctx.Config.OIDC {
+		var (
+			keys jose.JSONWebKeySet
+			o    notary.OIDC
+		)
+
+		if keys, err = notary.LoadJWKS(c.JWKS); err != nil {
+			return options, err
+		}
+
+		if o, err = notary.NewOIDC(c.Issuer, c.Audience, keys, c.Subjects, notary.OIDCOptionTTL(c.TTL), notary.OIDCOptionJWKSFile(c.JWKS)); err != nil {
+			return options, errors.Wrapf(err, "invalid oidc issuer %s", c.Issuer)
+		}
+
+		options = append(options, o)
+	}
+
+	return options, nil
+}
+
+// oidcExchange serves the oidc exchange directly instead of through the proxy,
+// the proxy only admits authorized callers and the exchange is how callers become authorized.
+func oidcExchange(dctx Context, svc notary.Service, r agent.AuditRecorder) (err error) {
+	var (
+		bind net.Listener
+	)
+
+	server := grpc.NewServer(
+		grpc.KeepaliveParams(dctx.RPCKeepalive),
+		grpc.KeepaliveEnforcementPolicy(dctx.RPCKeepalivePolicy),
+		auditInterceptor(dctx, r),
+	)
+	svc.BindExchange(server)
+
+	if bind, err = dctx.Muxer.Bind(bw.ProtocolExchange, dctx.Listener.Addr()); err != nil {
+		return errors.Wrap(err, "failed to bind oidc exchange")
+	}
+
+	dctx.grpc("exchange", server, bind)
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package daemons
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/internal/stringsx"
+	"github.com/james-lawrence/bw/storage"
+)
+
+const (
+	storageTorrent    = "torrent"
+	storageS3         = "s3"
+	storageFilesystem = "filesystem"
+)
+
+// ArchiveStorage the protocols used to transfer deploy archives. archives are uploaded using
+// the configured backend while torrents remain downloadable so previously uploaded archives
+// are available after switching backends.
+func ArchiveStorage(dctx Context, tc storage.TorrentConfig) (upload storage.UploadProtocol, downloads []storage.DownloadProtocol, err error) {
+	var (
+		s3s storage.S3
+		c   = dctx.Config.Storage
+		fs  = storage.NewFilesystem(c.Filesystem.Directory)
+	)
+
+	downloads = append(downloads, tc.Downloader())
+
+	if c.Filesystem.Directory != "" {
+		downloads = append(downloads, fs)
+	}
+
+	if c.S3.Bucket != "" {
+		if s3s, err = s3Storage(dctx); err != nil {
+			return upload, downloads, err
+		}
+		downloads = append(downloads, s3s)
+	}
+
+	switch backend := stringsx.DefaultIfBlank(c.Backend, storageTorrent); backend {
+	case storageTorrent:
+		return tc.Uploader(), downloads, nil
+	case storageFilesystem:
+		if c.Filesystem.Directory == "" {
+			return upload, downloads, errors.New("filesystem storage requires a directory")
+		}
+
+		return fs, downloads, nil
+	case storageS3:
+		if c.S3.Bucket == "" {
+			return upload, downloads, errors.New("s3 storage requires a bucket")
+		}
+
+		return s3s, downloads, nil
+	default:
+		return upload, downloads, errors.Errorf("unknown archive storage backend: %s", backend)
+	}
+}
+
+func s3Storage(dctx Context) (_ storage.S3, err error) {
+	var (
+		cfg aws.Config
+		c   = dctx.Config.Storage.S3
+	)
+
+	options := []func(*config.LoadOptions) error{}
+	if c.Region != "" {
+		options = append(options, config.WithRegion(c.Region))
+	}
+
+	if cfg, err = config.LoadDefaultConfig(dctx.Context, options...); err != nil {
+		return storage.S3{}, errors.Wrap(err, "unable to load aws configuration")
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if c.Endpoint != "" {
+			o.BaseEndpoint = aws.String(c.Endpoint)
+		}
+		o.UsePathStyle = c.PathStyle
+	})
+
+	return storage.NewS3(client, c.Bucket, storage.S3OptionPrefix(c.Prefix)), nil
+}
@@ -7,21 +7,39 @@ import (
 	"github.com/james-lawrence/bw/certificatecache"
 )
 
+// offset the time into the future to refresh the certificate well in advance
+// of the actual expiration. lets encrypt wants 30 days.
+const agentRefreshOffset = 31 * 24 * time.Hour
+
 // AgentCertificateCache initializes the certificate cache manager.
 func AgentCertificateCache(ctx Context) (err error) {
-	// offset the time into the future to refresh the certificate well in advance
-	// of the actual expiration. lets encrypt wants 30 days.
-	const futureoffset = 31 * 24 * time.Hour
-
 	config := ctx.Config
-	client := acme.NewChallenger(ctx.Cluster.Local(), ctx.Cluster, ctx.ACMECache, ctx.Dialer)
-	fallback := certificatecache.NewRefreshAgent(config.Credentials.Directory, client)
 
 	return certificatecache.FromConfig(
 		config.Credentials.Directory,
 		config.Credentials.Mode,
 		ctx.ConfigurationFile,
-		futureoffset,
-		fallback,
+		agentRefreshOffset,
+		agentRefreshFallback(ctx),
 	)
 }
+
+// agentReissue immediately refreshes the agent's credentials, used when the
+// cluster's certificate authority reissues certificates.
+func agentReissue(ctx Context) func() error {
+	return func() error {
+		config := ctx.Config
+
+		return certificatecache.RefreshFromConfig(
+			config.Credentials.Directory,
+			config.Credentials.Mode,
+			ctx.ConfigurationFile,
+			agentRefreshFallback(ctx),
+		)
+	}
+}
+
+func agentRefreshFallback(ctx Context) *certificatecache.ACME {
+	client := acme.NewChallenger(ctx.Cluster.Local(), ctx.Cluster, ctx.ACMECache, ctx.Dialer)
+	return certificatecache.NewRefreshAgent(ctx.Config.Credentials.Directory, client)
+}
@@ -1,10 +1,16 @@
 package daemons
 
 import (
+	"context"
 	"net"
 	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc"
 
 	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
 	"github.com/james-lawrence/bw/internal/tlsx"
 	"github.com/james-lawrence/bw/internal/torrentx"
 	"github.com/james-lawrence/bw/muxer"
@@ -37,7 +43,60 @@ func Torrent(ctx Context) (tc storage.TorrentConfig, err error) {
 		storage.TorrentOptionBind(b),
 		storage.TorrentOptionDHTPeers(ctx.Cluster),
 		storage.TorrentOptionDataDir(filepath.Join(ctx.Config.Root, bw.DirTorrents)),
+		storage.TorrentOptionRateLimits(ctx.Config.Storage.Torrent.Upload, ctx.Config.Storage.Torrent.Download),
+	}
+
+	if ctx.Config.Storage.Torrent.ZoneFallback > 0 {
+		opts = append(opts, storage.TorrentOptionZoneFallback(ctx.Config.Storage.Torrent.ZoneFallback))
 	}
 
 	return storage.NewTorrent(ctx.Cluster, opts...)
 }
+
+// ArchiveGC garbage collection of the archives held by the torrent storage.
+func ArchiveGC(ctx Context, tc storage.TorrentConfig) storage.TorrentGC {
+	r := storage.Retention{
+		KeepN:  ctx.Config.Storage.Retention.KeepN,
+		Pinned: ctx.Config.Storage.Retention.Pinned,
+	}
+
+	if r.KeepN == 0 {
+		r.KeepN = ctx.Config.KeepN
+	}
+
+	return storage.NewTorrentGC(
+		tc,
+		r,
+		storage.TorrentGCOptionCurrent(quorumDeploys(dialers.NewQuorum(ctx.Cluster, ctx.Dialer.Defaults()...))),
+	)
+}
+
+// quorumDeploys retrieves the archives of the quorum's latest and in progress deploys.
+func quorumDeploys(d dialers.ContextDialer) storage.CurrentDeploys {
+	return func() (archives []*agent.Archive, err error) {
+		var (
+			conn *grpc.ClientConn
+			info *agent.InfoResponse
+		)
+
+		ctx, done := context.WithTimeout(context.Background(), time.Minute)
+		defer done()
+
+		if conn, err = d.DialContext(ctx); err != nil {
+			return nil, errors.Wrap(err, "unable to connect to quorum")
+		}
+		defer conn.Close()
+
+		if info, err = agent.NewQuorumClient(conn).Info(ctx, &agent.InfoRequest{}); err != nil {
+			return nil, errors.Wrap(err, "unable to retrieve quorum info")
+		}
+
+		for _, a := range []*agent.Archive{info.GetDeployed().GetArchive(), info.GetDeploying().GetArchive()} {
+			if a != nil {
+				archives = append(archives, a)
+			}
+		}
+
+		return archives, nil
+	}
+}
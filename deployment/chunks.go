@@ -0,0 +1,52 @@
+package deployment
+
+import (
+	"encoding/hex"
+	"path/filepath"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/storage"
+)
+
+// NewChunkIndex index the chunks of the archives held by previous deploys.
+func NewChunkIndex(root string) ChunkIndex {
+	return ChunkIndex{root: root}
+}
+
+// ChunkIndex locates chunks within the archives of previous deploys.
+type ChunkIndex struct {
+	root string
+}
+
+// Index the chunks, deploys in progress are ignored since their archive
+// may be incomplete.
+func (t ChunkIndex) Index() (_ map[string]storage.ChunkLocation, err error) {
+	var (
+		deploys []*agent.Deploy
+	)
+
+	if deploys, err = readAllDeployMetadata(t.root); err != nil {
+		return nil, err
+	}
+
+	idx := make(map[string]storage.ChunkLocation)
+	for _, d := range deploys {
+		if d.Archive == nil || d.Stage == agent.Deploy_Deploying {
+			continue
+		}
+
+		_, droot, _ := deployDirs(t.root, d.Archive)
+		offset := int64(0)
+		for _, c := range d.Archive.Chunks {
+			idx[hex.EncodeToString(c.Digest)] = storage.ChunkLocation{
+				Path:   filepath.Join(droot, bw.ArchiveFile),
+				Offset: offset,
+				Length: int64(c.Length),
+			}
+			offset += int64(c.Length)
+		}
+	}
+
+	return idx, nil
+}
@@ -0,0 +1,58 @@
+package deployment
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/storage"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	g "github.com/onsi/gomega"
+)
+
+var _ = Describe("ChunkIndex", func() {
+	deploy := func(root string, stage agent.Deploy_Stage, chunks ...*agent.ArchiveChunk) *agent.Deploy {
+		id := bw.MustGenerateID()
+		d := &agent.Deploy{
+			Stage:   stage,
+			Archive: &agent.Archive{DeploymentID: id[:], Chunks: chunks},
+		}
+		_, droot, _ := deployDirs(root, d.Archive)
+		g.Expect(os.MkdirAll(droot, 0755)).To(g.Succeed())
+		g.Expect(writeDeployMetadata(droot, d)).To(g.Succeed())
+		return d
+	}
+
+	It("should locate chunks within completed deploys", func() {
+		root := testingx.TempDir()
+		d := deploy(
+			root,
+			agent.Deploy_Completed,
+			&agent.ArchiveChunk{Digest: []byte{0x1}, Length: 10},
+			&agent.ArchiveChunk{Digest: []byte{0x2}, Length: 20},
+		)
+		deploy(root, agent.Deploy_Deploying, &agent.ArchiveChunk{Digest: []byte{0x3}, Length: 30})
+
+		idx, err := NewChunkIndex(root).Index()
+		g.Expect(err).To(g.Succeed())
+		g.Expect(idx).To(g.HaveLen(2))
+
+		_, droot, _ := deployDirs(root, d.Archive)
+		g.Expect(idx[hex.EncodeToString([]byte{0x2})]).To(g.Equal(storage.ChunkLocation{
+			Path:   filepath.Join(droot, bw.ArchiveFile),
+			Offset: 10,
+			Length: 20,
+		}))
+	})
+
+	It("should return an empty index when no deploys exist", func() {
+		idx, err := NewChunkIndex(filepath.Join(testingx.TempDir(), "missing")).Index()
+		g.Expect(err).To(g.Succeed())
+		g.Expect(idx).To(g.BeEmpty())
+	})
+})
@@ -1,7 +1,10 @@
 package deployment
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"log"
 	"os"
@@ -18,9 +21,11 @@ import (
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agentutil"
 	"github.com/james-lawrence/bw/archive"
+	"github.com/james-lawrence/bw/deployment/logship"
 	"github.com/james-lawrence/bw/internal/envx"
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/iox"
+	"github.com/james-lawrence/bw/notary"
 	"github.com/james-lawrence/bw/storage"
 )
 
@@ -39,6 +44,8 @@ func New(local *agent.Peer, d deployer, options ...CoordinatorOption) Coordinato
 		local:    local,
 		deployer: d,
 		m:        &sync.Mutex{},
+		staging:  &sync.Mutex{},
+		logs:     &sync.Mutex{},
 		dlreg:    storage.New(),
 		ds:       newDeployState(),
 	}
@@ -69,6 +76,8 @@ func CoordinatorOptionRoot(root string) CoordinatorOption {
 	return func(d *Coordinator) {
 		d.root = root
 		d.deploysRoot = bw.DeployDir(root)
+		d.logsRoot = bw.LogsDir(root)
+		d.stagedRoot = filepath.Join(root, bw.DirStaged)
 	}
 }
 
@@ -80,6 +89,21 @@ func CoordinatorOptionKeepN(n int) CoordinatorOption {
 	}
 }
 
+// CoordinatorOptionLogRetention controls how deploy logs are retained
+// independently of the deploy directories removed by KeepN.
+func CoordinatorOptionLogRetention(r LogRetention) CoordinatorOption {
+	return func(d *Coordinator) {
+		d.retention = r
+	}
+}
+
+// CoordinatorOptionLogShipper forward deploy logs to a remote destination.
+func CoordinatorOptionLogShipper(s logship.Shipper) CoordinatorOption {
+	return func(d *Coordinator) {
+		d.shipper = s
+	}
+}
+
 // CoordinatorOptionDispatcher sets the dispatcher for the coordinator.
 func CoordinatorOptionDispatcher(di dispatcher) CoordinatorOption {
 	return func(d *Coordinator) {
@@ -94,6 +118,13 @@ func CoordinatorOptionDeployResults(dst chan *DeployResult) CoordinatorOption {
 	}
 }
 
+// CoordinatorOptionVerifier verify the signature of archives before they are unpacked.
+func CoordinatorOptionVerifier(v archiveVerifier) CoordinatorOption {
+	return func(d *Coordinator) {
+		d.verifier = v
+	}
+}
+
 // CoordinatorOptionStorage set the storage registry.
 func CoordinatorOptionStorage(reg storage.DownloadFactory) CoordinatorOption {
 	return func(d *Coordinator) {
@@ -106,14 +137,21 @@ type Coordinator struct {
 	keepN             int // never set manually. always set by CoordinatorOptionKeepN
 	root              string
 	deploysRoot       string // never set manually. always set by CoordinatorOptionRoot
+	logsRoot          string // never set manually. always set by CoordinatorOptionRoot
+	stagedRoot        string // never set manually. always set by CoordinatorOptionRoot
+	retention         LogRetention
+	shipper           logship.Shipper
 	local             *agent.Peer
 	deployer          deployer
 	dispatcher        dispatcher
 	dlreg             storage.DownloadFactory
+	verifier          archiveVerifier
 	cleanup           agentutil.Cleaner // never set manually. always set by CoordinatorOptionKeepN
 	completedObserver chan *DeployResult
 	ds                *DeployState
 	m                 *sync.Mutex
+	staging           *sync.Mutex // serializes staging, independent of m since downloads are slow.
+	logs              *sync.Mutex // serializes the retention of deploy logs.
 }
 
 func (t *Coordinator) background(dctx *DeployContext) {
@@ -129,6 +167,10 @@ func (t *Coordinator) background(dctx *DeployContext) {
 		log.Println("failed to write deploy metadata", err)
 	}
 
+	if err := t.retainLog(dctx.ID, dctx.LogFile); err != nil {
+		log.Println("failed to retain deploy log", err)
+	}
+
 	// by default keep the oldest deploys. if we have a successful deploy then keep the newest.
 	switch d.Stage {
 	case agent.Deploy_Completed:
@@ -216,6 +258,10 @@ func (t *Coordinator) Deploy(ctx context.Context, by string, opts *agent.DeployO
 		DeployContextOptionDispatcher(t.dispatcher),
 	}
 
+	if t.shipper != nil {
+		dcopts = append(dcopts, DeployContextOptionLogShipper(t.shipper))
+	}
+
 	if dctx, err = NewRemoteDeployContext(ctx, t.deploysRoot, t.local, by, opts, archive, dcopts...); err != nil {
 		errorsx.Log(agentutil.Dispatch(ctx, t.dispatcher, agent.LogError(t.local, err)))
 		return t.ds.current, err
@@ -251,7 +297,7 @@ func (t *Coordinator) Deploy(ctx context.Context, by string, opts *agent.DeployO
 
 	errorsx.Log(dctx.Dispatch(agent.DeployEvent(dctx.Local, d)))
 
-	if err = downloadArchive(t.dlreg, dctx); err != nil {
+	if err = downloadArchive(t.dlreg, t.verifier, t.staged(archive), dctx); err != nil {
 		return d, dctx.Done(err)
 	}
 
@@ -260,14 +306,71 @@ func (t *Coordinator) Deploy(ctx context.Context, by string, opts *agent.DeployO
 	return d, nil
 }
 
+// Stage downloads and verifies the archive ahead of its deploy, the deploy
+// uses the staged archive instead of retrieving it again. only the most
+// recently staged archive is kept.
+func (t *Coordinator) Stage(ctx context.Context, a *agent.Archive) (err error) {
+	var (
+		dst    *os.File
+		digest = sha256.New()
+		path   = t.staged(a)
+	)
+
+	t.staging.Lock()
+	defer t.staging.Unlock()
+
+	if _, err = os.Stat(path); err == nil {
+		return nil
+	}
+
+	if err = os.RemoveAll(t.stagedRoot); err != nil {
+		return errors.Wrap(err, "unable to clear staging directory")
+	}
+
+	if err = os.MkdirAll(t.stagedRoot, 0700); err != nil {
+		return errors.Wrap(err, "unable to create staging directory")
+	}
+
+	if dst, err = os.CreateTemp(t.stagedRoot, "staging-*"); err != nil {
+		return errors.Wrap(err, "unable to open archive file")
+	}
+	defer os.Remove(dst.Name())
+	defer dst.Close()
+
+	if _, err = io.Copy(io.MultiWriter(dst, digest), t.dlreg.New(a.Location).Download(ctx, a)); err != nil {
+		return errors.Wrap(err, "retrieve archive")
+	}
+
+	if err = verifyArchive(t.verifier, a, digest.Sum(nil)); err != nil {
+		return err
+	}
+
+	if err = dst.Sync(); err != nil {
+		return errors.Wrap(err, "unable to sync staged archive")
+	}
+
+	return errors.Wrap(os.Rename(dst.Name(), path), "unable to stage archive")
+}
+
+func (t *Coordinator) staged(a *agent.Archive) string {
+	return filepath.Join(t.stagedRoot, bw.RandomID(a.DeploymentID).String())
+}
+
 // Logs return the logs for the given deployment ID.
+// falls back to the retained logs once the deploy directory has been removed.
 func (t *Coordinator) Logs(did []byte) (logs io.ReadCloser) {
 	var (
 		err error
 	)
 
 	p := filepath.Join(t.deploysRoot, bw.RandomID(did).String(), bw.DeployLog)
-	if logs, err = os.Open(p); err != nil {
+	if logs, err = os.Open(p); err == nil {
+		return logs
+	} else if !os.IsNotExist(err) {
+		return io.NopCloser(iox.ErrReader(errors.Wrapf(err, "unable to open logfile %s", p)))
+	}
+
+	if logs, err = openRetainedLog(t.logsRoot, did); err != nil {
 		return io.NopCloser(iox.ErrReader(errors.Wrapf(err, "unable to open logfile %s", p)))
 	}
 
@@ -320,12 +423,28 @@ func (t *Coordinator) correctLatestDeploy(deploys ...*agent.Deploy) error {
 	return writeDeployMetadata(root, d)
 }
 
-func downloadArchive(dlreg storage.DownloadFactory, dctx *DeployContext) (err error) {
+type archiveVerifier interface {
+	VerifyArchive(digest []byte, signed time.Time, fingerprint string, s *notary.Signature) error
+}
+
+// downloadArchive retrieves the archive, using the staged archive when present.
+func downloadArchive(dlreg storage.DownloadFactory, v archiveVerifier, staged string, dctx *DeployContext) (err error) {
 	var (
-		dst *os.File
+		dst    *os.File
+		src    io.Reader
+		digest = sha256.New()
 	)
 
-	dctx.Log.Println("download initiated", dctx.Archive.Location)
+	if s, cause := os.Open(staged); cause == nil {
+		dctx.Log.Println("using staged archive", dctx.Archive.Location)
+		defer os.Remove(staged)
+		defer s.Close()
+		src = s
+	} else {
+		dctx.Log.Println("download initiated", dctx.Archive.Location)
+		src = dlreg.New(dctx.Archive.Location).Download(dctx.deadline, dctx.Archive)
+	}
+
 	if dst, err = os.OpenFile(dctx.ArchiveFile, os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0600); err != nil {
 		return errors.Wrap(err, "unable to open archive file")
 	}
@@ -334,10 +453,14 @@ func downloadArchive(dlreg storage.DownloadFactory, dctx *DeployContext) (err er
 		errorsx.Log(errors.Wrap(errorsx.Compact(dst.Sync(), dst.Close()), "archive cleanup failed"))
 	}()
 
-	if _, err = io.Copy(dst, dlreg.New(dctx.Archive.Location).Download(dctx.deadline, dctx.Archive)); err != nil {
+	if _, err = io.Copy(io.MultiWriter(dst, digest), src); err != nil {
 		return errors.Wrapf(err, "retrieve archive")
 	}
 
+	if err = verifyArchive(v, dctx.Archive, digest.Sum(nil)); err != nil {
+		return err
+	}
+
 	if err = iox.Rewind(dst); err != nil {
 		return errors.Wrap(err, "unable to rewind archive")
 	}
@@ -350,6 +473,37 @@ func downloadArchive(dlreg storage.DownloadFactory, dctx *DeployContext) (err er
 	return nil
 }
 
+// verifyArchive ensures the downloaded archive is the archive signed by its uploader.
+func verifyArchive(v archiveVerifier, a *agent.Archive, digest []byte) error {
+	if v == nil {
+		return nil
+	}
+
+	if !bytes.Equal(a.Checksum, digest) {
+		return errors.Errorf("checksums mismatch: archive(%s), downloaded(%s)", hex.EncodeToString(a.Checksum), hex.EncodeToString(digest))
+	}
+
+	var signed time.Time
+	sig := a.GetSignature()
+	if sig.GetTs() > 0 {
+		signed = time.Unix(sig.GetTs(), 0)
+	}
+
+	if err := v.VerifyArchive(digest, signed, sig.GetFingerprint(), signature(sig)); err != nil {
+		return errors.Wrap(err, "archive verification failed")
+	}
+
+	return nil
+}
+
+func signature(s *agent.ArchiveSignature) *notary.Signature {
+	if s == nil {
+		return nil
+	}
+
+	return &notary.Signature{Format: s.Format, Data: s.Data}
+}
+
 // ResultBus bus for deploy results.
 func ResultBus(in chan *DeployResult, out ...chan *DeployResult) {
 	for result := range in {
@@ -16,6 +16,7 @@ import (
 	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/agent"
 	"github.com/james-lawrence/bw/agentutil"
+	"github.com/james-lawrence/bw/deployment/logship"
 	"github.com/james-lawrence/bw/internal/envx"
 	"github.com/james-lawrence/bw/internal/errorsx"
 )
@@ -41,6 +42,16 @@ func DeployContextOptionLog(l logger) DeployContextOption {
 	}
 }
 
+// DeployContextOptionLogShipper forward the deploy log to the shipper.
+// has no effect on deploys that silence their logs.
+func DeployContextOptionLogShipper(s logship.Shipper) DeployContextOption {
+	return func(dctx *DeployContext) {
+		if l, ok := dctx.Log.(dlog); ok && l.dst != nil {
+			dctx.Log = l.ship(logship.NewWriter(s, dctx.ID.String(), dctx.Local.Name, dctx.Local.Labels))
+		}
+	}
+}
+
 // DeployContextOptionArchiveRoot set the root directory of the archive.
 func DeployContextOptionArchiveRoot(ar string) DeployContextOption {
 	return func(dctx *DeployContext) {
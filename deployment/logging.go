@@ -13,20 +13,35 @@ import (
 
 type dlog struct {
 	*log.Logger
-	uid string
-	dst *os.File
+	uid     string
+	dst     *os.File
+	shipped io.Closer
 }
 
 func (t dlog) Write(b []byte) (n int, err error) {
 	return t.Logger.Writer().Write(b)
 }
 
-func (t dlog) Close() error {
+func (t dlog) Close() (err error) {
+	if t.shipped != nil {
+		err = t.shipped.Close()
+	}
+
 	if t.dst != nil {
-		return errorsx.Compact(t.dst.Sync(), t.dst.Close())
+		return errorsx.Compact(err, t.dst.Sync(), t.dst.Close())
 	}
 
-	return nil
+	return err
+}
+
+// ship duplicates the log output to the provided writer.
+func (t dlog) ship(w io.WriteCloser) dlog {
+	return dlog{
+		Logger:  log.New(io.MultiWriter(t.Logger.Writer(), w), t.Logger.Prefix(), t.Logger.Flags()),
+		uid:     t.uid,
+		dst:     t.dst,
+		shipped: w,
+	}
 }
 
 func newLogger(uid bw.RandomID, root, prefix string) (_dlog dlog, err error) {
@@ -0,0 +1,184 @@
+package deployment
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+// maximum length of a single log line considered by the search.
+const maxSearchLine = 1024 * 1024
+
+// errSearchLimit signals the search limit has been reached.
+const errSearchLimit = errorsx.String("search limit reached")
+
+type searchable struct {
+	id bw.RandomID
+	ts time.Time
+}
+
+// SearchLogs search the deploy logs, including retained logs, for lines matching the pattern.
+// deployments are searched from oldest to newest.
+func (t *Coordinator) SearchLogs(ctx context.Context, req *agent.LogSearchRequest, match func(*agent.LogSearchMatch) error) (err error) {
+	var (
+		pattern    *regexp.Regexp
+		candidates []searchable
+		matched    int64
+	)
+
+	if pattern, err = regexp.Compile(req.Pattern); err != nil {
+		return errors.Wrap(err, "invalid search pattern")
+	}
+
+	if len(req.DeploymentID) > 0 {
+		candidates = []searchable{{id: req.DeploymentID}}
+	} else if candidates, err = t.searchable(time.Unix(req.Since, 0)); err != nil {
+		return err
+	}
+
+	for _, c := range candidates {
+		// peers that never ran the deploy, or no longer retain its log, have nothing to match.
+		if !t.logged(c.id) {
+			continue
+		}
+
+		err = searchLog(ctx, t.Logs(c.id), pattern, func(line int64, content string) error {
+			if req.Limit > 0 && matched >= req.Limit {
+				return errSearchLimit
+			}
+			matched++
+
+			return match(&agent.LogSearchMatch{
+				DeploymentID: c.id,
+				Line:         line,
+				Content:      content,
+			})
+		})
+
+		if errors.Is(err, errSearchLimit) {
+			return nil
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "search failed %s", c.id)
+		}
+	}
+
+	return nil
+}
+
+// logged reports if the deploy log or the retained log of the deployment exists.
+func (t *Coordinator) logged(id bw.RandomID) bool {
+	for _, path := range []string{
+		filepath.Join(t.deploysRoot, id.String(), bw.DeployLog),
+		filepath.Join(t.logsRoot, id.String()+retainedLogExt),
+		filepath.Join(t.logsRoot, id.String()+retainedLogCompressed),
+	} {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// searchable returns the deployments with logs initiated after the provided time.
+func (t *Coordinator) searchable(since time.Time) (candidates []searchable, err error) {
+	var (
+		deploys []*agent.Deploy
+		entries []os.DirEntry
+		seen    = make(map[string]bool)
+	)
+
+	t.m.Lock()
+	deploys, err = readAllDeployMetadata(t.deploysRoot)
+	t.m.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range deploys {
+		if d.Archive == nil {
+			continue
+		}
+
+		id := bw.RandomID(d.Archive.DeploymentID)
+		seen[id.String()] = true
+		candidates = append(candidates, searchable{id: id, ts: time.Unix(d.Archive.Dts, 0)})
+	}
+
+	if entries, err = os.ReadDir(t.logsRoot); err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "unable to read logs directory")
+	}
+
+	for _, e := range entries {
+		name := strings.TrimSuffix(strings.TrimSuffix(e.Name(), retainedLogCompressed), retainedLogExt)
+		if e.IsDir() || name == e.Name() || seen[name] {
+			continue
+		}
+
+		id, err := bw.ParseRandomID(name)
+		if err != nil {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		seen[name] = true
+		candidates = append(candidates, searchable{id: id, ts: info.ModTime()})
+	}
+
+	filtered := candidates[:0]
+	for _, c := range candidates {
+		if c.ts.Before(since) {
+			continue
+		}
+
+		filtered = append(filtered, c)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return filtered[i].ts.Before(filtered[j].ts)
+	})
+
+	return filtered, nil
+}
+
+func searchLog(ctx context.Context, logs io.ReadCloser, pattern *regexp.Regexp, match func(line int64, content string) error) (err error) {
+	defer logs.Close()
+
+	scanner := bufio.NewScanner(logs)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSearchLine)
+
+	for line := int64(1); scanner.Scan(); line++ {
+		if err = ctx.Err(); err != nil {
+			return errors.WithStack(err)
+		}
+
+		if !pattern.MatchString(scanner.Text()) {
+			continue
+		}
+
+		if err = match(line, scanner.Text()); err != nil {
+			return err
+		}
+	}
+
+	return errors.WithStack(scanner.Err())
+}
@@ -0,0 +1,90 @@
+package deployment
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/testingx"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	g "github.com/onsi/gomega"
+)
+
+var _ = Describe("SearchLogs", func() {
+	var (
+		workdir  string
+		c        Coordinator
+		live     bw.RandomID
+		retained bw.RandomID
+	)
+
+	search := func(req *agent.LogSearchRequest) (matches []*agent.LogSearchMatch) {
+		g.Expect(c.SearchLogs(context.Background(), req, func(m *agent.LogSearchMatch) error {
+			matches = append(matches, m)
+			return nil
+		})).To(g.Succeed())
+		return matches
+	}
+
+	BeforeEach(func() {
+		workdir = testingx.TempDir()
+		c = New(nil, NewDirective(), CoordinatorOptionRoot(workdir), CoordinatorOptionLogRetention(LogRetention{KeepN: 5, Compress: true}))
+		live = bw.MustGenerateID()
+		retained = bw.MustGenerateID()
+
+		// a deploy directory with a live log.
+		deploydir := filepath.Join(c.deploysRoot, live.String())
+		g.Expect(os.MkdirAll(deploydir, 0755)).To(g.Succeed())
+		g.Expect(os.WriteFile(filepath.Join(deploydir, bw.DeployLog), []byte("starting\nerror: disk full\ndone\n"), 0600)).To(g.Succeed())
+		g.Expect(writeDeployMetadata(deploydir, &agent.Deploy{
+			Archive: &agent.Archive{DeploymentID: live, Dts: time.Now().Unix()},
+			Stage:   agent.Deploy_Completed,
+		})).To(g.Succeed())
+
+		// a deploy that only has a retained log.
+		src := filepath.Join(workdir, bw.DeployLog)
+		g.Expect(os.WriteFile(src, []byte("error: connection refused\nok\n"), 0600)).To(g.Succeed())
+		g.Expect(retainLog(c.logsRoot, c.retention, retained, src)).To(g.Succeed())
+		old := time.Now().Add(-48 * time.Hour)
+		g.Expect(os.Chtimes(filepath.Join(c.logsRoot, retained.String()+retainedLogCompressed), old, old)).To(g.Succeed())
+	})
+
+	It("should search live and retained logs oldest first", func() {
+		matches := search(&agent.LogSearchRequest{Pattern: "^error"})
+		g.Expect(matches).To(g.HaveLen(2))
+		g.Expect(matches[0].DeploymentID).To(g.Equal([]byte(retained)))
+		g.Expect(matches[0].Line).To(g.Equal(int64(1)))
+		g.Expect(matches[0].Content).To(g.Equal("error: connection refused"))
+		g.Expect(matches[1].DeploymentID).To(g.Equal([]byte(live)))
+		g.Expect(matches[1].Line).To(g.Equal(int64(2)))
+	})
+
+	It("should restrict the search to a deployment", func() {
+		matches := search(&agent.LogSearchRequest{Pattern: "error", DeploymentID: retained})
+		g.Expect(matches).To(g.HaveLen(1))
+		g.Expect(matches[0].Content).To(g.Equal("error: connection refused"))
+	})
+
+	It("should not match deployments the peer never ran", func() {
+		g.Expect(search(&agent.LogSearchRequest{Pattern: "error", DeploymentID: bw.MustGenerateID()})).To(g.BeEmpty())
+	})
+
+	It("should ignore deployments before since", func() {
+		matches := search(&agent.LogSearchRequest{Pattern: "error", Since: time.Now().Add(-time.Hour).Unix()})
+		g.Expect(matches).To(g.HaveLen(1))
+		g.Expect(matches[0].DeploymentID).To(g.Equal([]byte(live)))
+	})
+
+	It("should respect the limit", func() {
+		g.Expect(search(&agent.LogSearchRequest{Pattern: ".", Limit: 3})).To(g.HaveLen(3))
+	})
+
+	It("should reject invalid patterns", func() {
+		g.Expect(c.SearchLogs(context.Background(), &agent.LogSearchRequest{Pattern: "("}, func(*agent.LogSearchMatch) error { return nil })).ToNot(g.Succeed())
+	})
+})
@@ -0,0 +1,183 @@
+// Package logship forwards deploy log lines to remote log aggregation systems.
+package logship
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/james-lawrence/bw/backoff"
+)
+
+// Entry a single line of a deploy log.
+type Entry struct {
+	Timestamp  time.Time
+	Deployment string            // deployment id the line belongs to.
+	Peer       string            // name of the peer that generated the line.
+	Labels     map[string]string // labels of the peer that generated the line.
+	Line       string
+}
+
+// Shipper forwards log entries to a remote destination.
+// implementations must not block the caller.
+type Shipper interface {
+	Ship(Entry)
+}
+
+// Multi ships entries to every shipper.
+type Multi []Shipper
+
+// Ship implements Shipper.
+func (t Multi) Ship(e Entry) {
+	for _, s := range t {
+		s.Ship(e)
+	}
+}
+
+// Discard ignores all entries.
+type Discard struct{}
+
+// Ship implements Shipper.
+func (Discard) Ship(Entry) {}
+
+// NewWriter splits the written content into lines and ships them.
+func NewWriter(s Shipper, deployment, peer string, labels map[string]string) *Writer {
+	return &Writer{
+		s:          s,
+		deployment: deployment,
+		peer:       peer,
+		labels:     labels,
+	}
+}
+
+// Writer ships each line written to it.
+type Writer struct {
+	s          Shipper
+	deployment string
+	peer       string
+	labels     map[string]string
+	m          sync.Mutex
+	partial    []byte
+}
+
+func (t *Writer) Write(b []byte) (int, error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.partial = append(t.partial, b...)
+	for {
+		idx := bytes.IndexByte(t.partial, '\n')
+		if idx < 0 {
+			break
+		}
+
+		t.ship(t.partial[:idx])
+		t.partial = t.partial[idx+1:]
+	}
+
+	return len(b), nil
+}
+
+// Close ships any remaining partial line.
+func (t *Writer) Close() error {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if len(t.partial) > 0 {
+		t.ship(t.partial)
+		t.partial = nil
+	}
+
+	return nil
+}
+
+func (t *Writer) ship(line []byte) {
+	t.s.Ship(Entry{
+		Timestamp:  time.Now(),
+		Deployment: t.deployment,
+		Peer:       t.peer,
+		Labels:     t.labels,
+		Line:       string(bytes.TrimRight(line, "\r")),
+	})
+}
+
+type sender func(context.Context, []Entry) error
+
+// queue buffers entries and delivers them in batches from a background goroutine.
+// entries are dropped when the buffer is full to prevent remote failures from
+// blocking deploys.
+type queue struct {
+	entries chan Entry
+	dropped *uint64
+}
+
+func newQueue(size int) queue {
+	return queue{
+		entries: make(chan Entry, size),
+		dropped: new(uint64),
+	}
+}
+
+// Ship implements Shipper.
+func (t queue) Ship(e Entry) {
+	select {
+	case t.entries <- e:
+	default:
+		atomic.AddUint64(t.dropped, 1)
+	}
+}
+
+func (t queue) run(ctx context.Context, name string, batch int, flush time.Duration, attempts int, send sender) {
+	var (
+		pending = make([]Entry, 0, batch)
+		tick    = time.NewTicker(flush)
+		retry   = backoff.New(backoff.Exponential(250*time.Millisecond), backoff.Maximum(10*time.Second))
+	)
+	defer tick.Stop()
+
+	deliver := func() {
+		if len(pending) == 0 {
+			return
+		}
+
+		if dropped := atomic.SwapUint64(t.dropped, 0); dropped > 0 {
+			log.Println(name, "dropped", dropped, "log entries, buffer full")
+		}
+
+		for i := 0; i < attempts; i++ {
+			err := send(ctx, pending)
+			if err == nil {
+				break
+			}
+
+			if i == attempts-1 {
+				log.Println(name, "failed to ship", len(pending), "log entries", err)
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retry.Backoff(i)):
+			}
+		}
+
+		pending = pending[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-t.entries:
+			if pending = append(pending, e); len(pending) >= batch {
+				deliver()
+			}
+		case <-tick.C:
+			deliver()
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package logship_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/james-lawrence/bw/deployment/logship"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type recorder struct {
+	entries []logship.Entry
+}
+
+func (t *recorder) Ship(e logship.Entry) {
+	t.entries = append(t.entries, e)
+}
+
+// frames reads octet counted syslog messages.
+func frames(conn net.Conn, out chan string) {
+	defer close(out)
+	r := bufio.NewReader(conn)
+	for {
+		size, err := r.ReadString(' ')
+		if err != nil {
+			return
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(size))
+		if err != nil {
+			return
+		}
+
+		msg := make([]byte, n)
+		if _, err = io.ReadFull(r, msg); err != nil {
+			return
+		}
+
+		out <- string(msg)
+	}
+}
+
+var _ = Describe("Writer", func() {
+	It("should ship each line", func() {
+		r := &recorder{}
+		w := logship.NewWriter(r, "deploy1", "node1", map[string]string{"zone": "us-east-1a"})
+
+		_, err := fmt.Fprint(w, "hello\nwor")
+		Expect(err).To(Succeed())
+		_, err = fmt.Fprint(w, "ld\r\npartial")
+		Expect(err).To(Succeed())
+		Expect(r.entries).To(HaveLen(2))
+		Expect(w.Close()).To(Succeed())
+
+		Expect(r.entries).To(HaveLen(3))
+		Expect(r.entries[0].Line).To(Equal("hello"))
+		Expect(r.entries[1].Line).To(Equal("world"))
+		Expect(r.entries[2].Line).To(Equal("partial"))
+		Expect(r.entries[2].Deployment).To(Equal("deploy1"))
+		Expect(r.entries[2].Peer).To(Equal("node1"))
+		Expect(r.entries[2].Labels).To(Equal(map[string]string{"zone": "us-east-1a"}))
+	})
+})
+
+var _ = Describe("Syslog", func() {
+	It("should ship RFC5424 messages", func(ctx SpecContext) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(Succeed())
+		defer l.Close()
+
+		messages := make(chan string, 10)
+		go func() {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			frames(conn, messages)
+		}()
+
+		sctx, done := context.WithCancel(ctx)
+		defer done()
+
+		s := logship.NewSyslog(sctx, l.Addr().String(), logship.SyslogOptionHostname("node 1"))
+		ts := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+		s.Ship(logship.Entry{Timestamp: ts, Deployment: "deploy1", Peer: `no"de]1`, Line: "hello world"})
+		s.Ship(logship.Entry{Timestamp: ts, Deployment: "deploy1", Peer: "node1", Labels: map[string]string{"zone": "a", "role": "web"}, Line: "second"})
+
+		Eventually(messages).WithTimeout(5 * time.Second).Should(Receive(Equal(`<134>1 2024-01-02T03:04:05.000006Z node_1 bw - deploy [bw@32473 deployment="deploy1" peer="no\"de\]1"] hello world`)))
+		Eventually(messages).WithTimeout(5 * time.Second).Should(Receive(HaveSuffix(`peer="node1" label.role="web" label.zone="a"] second`)))
+	}, SpecTimeout(10*time.Second))
+})
+
+var _ = Describe("Loki", func() {
+	It("should push streams labelled by deployment, peer, and the peer's labels", func(ctx SpecContext) {
+		type push struct {
+			Streams []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"streams"`
+		}
+
+		pushes := make(chan push, 10)
+		tenants := make(chan string, 10)
+		srv := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			var p push
+			Expect(json.NewDecoder(req.Body).Decode(&p)).To(Succeed())
+			tenants <- req.Header.Get("X-Scope-OrgID")
+			pushes <- p
+			resp.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		sctx, done := context.WithCancel(ctx)
+		defer done()
+
+		l := logship.NewLoki(
+			sctx,
+			srv.URL,
+			logship.LokiOptionTenant("tenant1"),
+			logship.LokiOptionLabels(map[string]string{"environment": "production"}),
+		)
+		ts := time.Unix(0, 1000)
+		labels := map[string]string{"zone": "us-east-1a", "app.kubernetes.io/name": "web", "environment": "staging"}
+		l.Ship(logship.Entry{Timestamp: ts, Deployment: "deploy1", Peer: "node1", Labels: labels, Line: "hello"})
+		l.Ship(logship.Entry{Timestamp: ts, Deployment: "deploy1", Peer: "node1", Labels: labels, Line: "world"})
+
+		var p push
+		Eventually(pushes).WithTimeout(5 * time.Second).Should(Receive(&p))
+		Expect(<-tenants).To(Equal("tenant1"))
+		Expect(p.Streams).To(HaveLen(1))
+		Expect(p.Streams[0].Stream).To(Equal(map[string]string{
+			"environment":            "production",
+			"deployment":             "deploy1",
+			"peer":                   "node1",
+			"zone":                   "us-east-1a",
+			"app_kubernetes_io_name": "web",
+		}))
+		Expect(p.Streams[0].Values).To(Equal([][2]string{{"1000", "hello"}, {"1000", "world"}}))
+	}, SpecTimeout(10*time.Second))
+})
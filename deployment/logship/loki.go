@@ -0,0 +1,166 @@
+package logship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+// LokiOption options for the loki shipper.
+type LokiOption func(*Loki)
+
+// LokiOptionLabels static labels added to every stream.
+func LokiOptionLabels(labels map[string]string) LokiOption {
+	return func(l *Loki) {
+		l.labels = labels
+	}
+}
+
+// LokiOptionTenant set the tenant for multi-tenant loki deployments.
+func LokiOptionTenant(tenant string) LokiOption {
+	return func(l *Loki) {
+		l.tenant = tenant
+	}
+}
+
+// LokiOptionClient set the http client used to push entries.
+func LokiOptionClient(c *http.Client) LokiOption {
+	return func(l *Loki) {
+		l.client = c
+	}
+}
+
+// LokiOptionBuffer number of entries to buffer before dropping.
+func LokiOptionBuffer(n int) LokiOption {
+	return func(l *Loki) {
+		l.queue = newQueue(n)
+	}
+}
+
+// NewLoki ships entries to a loki compatible push endpoint.
+// e.g.) http://localhost:3100/loki/api/v1/push
+func NewLoki(ctx context.Context, endpoint string, options ...LokiOption) *Loki {
+	l := &Loki{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		queue:    newQueue(4096),
+	}
+
+	for _, opt := range options {
+		opt(l)
+	}
+
+	go l.queue.run(ctx, "loki", 512, time.Second, 5, l.send)
+
+	return l
+}
+
+// Loki ships deploy logs to a loki compatible http endpoint.
+type Loki struct {
+	queue
+	endpoint string
+	tenant   string
+	labels   map[string]string
+	client   *http.Client
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+type lokiPush struct {
+	Streams []*lokiStream `json:"streams"`
+}
+
+// streams groups the entries by deployment and peer. the labels of the peer are added
+// to the stream, the static labels and the deployment and peer labels take precedence.
+func (t *Loki) streams(entries []Entry) lokiPush {
+	var (
+		push    lokiPush
+		indexed = make(map[[2]string]*lokiStream)
+	)
+
+	for _, e := range entries {
+		key := [2]string{e.Deployment, e.Peer}
+		s, ok := indexed[key]
+		if !ok {
+			labels := make(map[string]string, len(t.labels)+len(e.Labels)+2)
+			for k, v := range e.Labels {
+				labels[lokiLabel(k)] = v
+			}
+			for k, v := range t.labels {
+				labels[k] = v
+			}
+			labels["deployment"] = e.Deployment
+			labels["peer"] = e.Peer
+
+			s = &lokiStream{Stream: labels}
+			indexed[key] = s
+			push.Streams = append(push.Streams, s)
+		}
+
+		s.Values = append(s.Values, [2]string{strconv.FormatInt(e.Timestamp.UnixNano(), 10), e.Line})
+	}
+
+	return push
+}
+
+// lokiLabel converts the peer label into a valid loki label name, [a-zA-Z_][a-zA-Z0-9_]*.
+func lokiLabel(k string) string {
+	name := []rune(k)
+	for idx, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && idx > 0:
+		default:
+			name[idx] = '_'
+		}
+	}
+
+	return string(name)
+}
+
+func (t *Loki) send(ctx context.Context, entries []Entry) (err error) {
+	var (
+		encoded []byte
+		req     *http.Request
+		resp    *http.Response
+	)
+
+	if encoded, err = json.Marshal(t.streams(entries)); err != nil {
+		return errors.Wrap(err, "unable to encode entries")
+	}
+
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(encoded)); err != nil {
+		return errors.WithStack(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if t.tenant != "" {
+		req.Header.Set("X-Scope-OrgID", t.tenant)
+	}
+
+	if resp, err = t.client.Do(req); err != nil {
+		return errors.Wrapf(err, "unable to push to %s", t.endpoint)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		errorsx.Log(resp.Body.Close())
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return errors.Errorf("unable to push to %s: %s %s", t.endpoint, resp.Status, bytes.TrimSpace(body))
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package logship
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/internal/systemx"
+)
+
+const (
+	// structured data id, uses the documentation enterprise number from RFC5612.
+	syslogSDID = "bw@32473"
+	// facility local0, severity informational.
+	syslogPriority = 16*8 + 6
+	// RFC5424 timestamps allow at most microsecond precision.
+	syslogTimestamp = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// SyslogOption options for the syslog shipper.
+type SyslogOption func(*Syslog)
+
+// SyslogOptionTLS enables tls using the provided configuration.
+func SyslogOptionTLS(c *tls.Config) SyslogOption {
+	return func(s *Syslog) {
+		s.tls = c
+	}
+}
+
+// SyslogOptionHostname set the hostname reported in each message.
+func SyslogOptionHostname(h string) SyslogOption {
+	return func(s *Syslog) {
+		s.hostname = h
+	}
+}
+
+// SyslogOptionBuffer number of entries to buffer before dropping.
+func SyslogOptionBuffer(n int) SyslogOption {
+	return func(s *Syslog) {
+		s.queue = newQueue(n)
+	}
+}
+
+// NewSyslog ships entries to a syslog server using RFC5424 messages
+// over tcp with octet counting framing (RFC6587).
+func NewSyslog(ctx context.Context, address string, options ...SyslogOption) *Syslog {
+	s := &Syslog{
+		address:  address,
+		hostname: systemx.HostnameOrLocalhost(),
+		appname:  "bw",
+		timeout:  10 * time.Second,
+		queue:    newQueue(4096),
+		m:        &sync.Mutex{},
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	go s.queue.run(ctx, "syslog", 128, time.Second, 5, s.send)
+
+	return s
+}
+
+// Syslog ships deploy logs to a syslog server.
+type Syslog struct {
+	queue
+	address  string
+	hostname string
+	appname  string
+	tls      *tls.Config
+	timeout  time.Duration
+	m        *sync.Mutex
+	conn     net.Conn
+}
+
+func (t *Syslog) dial(ctx context.Context) (conn net.Conn, err error) {
+	d := &net.Dialer{Timeout: t.timeout}
+	if t.tls == nil {
+		return d.DialContext(ctx, "tcp", t.address)
+	}
+
+	return (&tls.Dialer{NetDialer: d, Config: t.tls}).DialContext(ctx, "tcp", t.address)
+}
+
+func (t *Syslog) send(ctx context.Context, entries []Entry) (err error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.conn == nil {
+		if t.conn, err = t.dial(ctx); err != nil {
+			return errors.Wrapf(err, "unable to connect to %s", t.address)
+		}
+	}
+
+	var buf strings.Builder
+	for _, e := range entries {
+		msg := t.format(e)
+		fmt.Fprintf(&buf, "%d %s", len(msg), msg)
+	}
+
+	if err = t.conn.SetWriteDeadline(time.Now().Add(t.timeout)); err == nil {
+		_, err = t.conn.Write([]byte(buf.String()))
+	}
+
+	if err != nil {
+		// drop the connection, it'll be reestablished on the next attempt.
+		_ = t.conn.Close()
+		t.conn = nil
+		return errors.Wrapf(err, "unable to write to %s", t.address)
+	}
+
+	return nil
+}
+
+// format an entry as an RFC5424 message. the labels of the peer are
+// included as structured data parameters prefixed with label.
+func (t *Syslog) format(e Entry) string {
+	labels := make([]string, 0, len(e.Labels))
+	for k, v := range e.Labels {
+		labels = append(labels, fmt.Sprintf(" %s=\"%s\"", sdname("label."+k), sdescape(v)))
+	}
+	sort.Strings(labels)
+
+	return fmt.Sprintf(
+		"<%d>1 %s %s %s - deploy [%s deployment=\"%s\" peer=\"%s\"%s] %s",
+		syslogPriority,
+		e.Timestamp.UTC().Format(syslogTimestamp),
+		header(t.hostname),
+		header(t.appname),
+		syslogSDID,
+		sdescape(e.Deployment),
+		sdescape(e.Peer),
+		strings.Join(labels, ""),
+		e.Line,
+	)
+}
+
+// header fields must be printable ascii without spaces, nil value is '-'.
+func header(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+
+		return r
+	}, s)
+
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+// structured data parameter names are at most 32 printable ascii characters excluding '=', ' ', ']', and '"'.
+func sdname(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+
+		return r
+	}, s)
+
+	if len(s) > 32 {
+		return s[:32]
+	}
+
+	return s
+}
+
+// escape structured data parameter values.
+func sdescape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(s)
+}
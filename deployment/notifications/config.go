@@ -31,7 +31,7 @@ func decode(path string) *ast.Table {
 func deferredExpand(s string) string {
 	return os.Expand(s, func(key string) string {
 		switch key {
-		case EnvDeployInitiator, EnvDeployID, EnvDeployResult:
+		case EnvDeployInitiator, EnvDeployID, EnvDeployResult, EnvDeployCommit, EnvDeployError:
 			return fmt.Sprintf("${%s}", key)
 		default:
 			return os.Getenv(key)
@@ -17,6 +17,7 @@ const (
 	EnvDeployResult    = "BEARDED_WOOKIE_NOTIFICATIONS_DEPLOY_RESULT"
 	EnvDeployInitiator = "BEARDED_WOOKIE_NOTIFICATIONS_DEPLOY_INITIATOR"
 	EnvDeployCommit    = "BEARDED_WOOKIE_NOTIFICATIONS_DEPLOY_COMMIT"
+	EnvDeployError     = "BEARDED_WOOKIE_NOTIFICATIONS_DEPLOY_ERROR"
 )
 
 // Creator ...
@@ -79,7 +80,9 @@ func ExpandEnv(s string, dc *agent.DeployCommand) string {
 		case EnvDeployInitiator:
 			return dc.GetInitiator()
 		case EnvDeployCommit:
-			return dc.Archive.Commit
+			return dc.GetArchive().GetCommit()
+		case EnvDeployError:
+			return dc.GetError()
 		default:
 			return os.Getenv(key)
 		}
@@ -0,0 +1,252 @@
+// Package smtp delivers deployment notifications via email.
+package smtp
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/deployment/notifications"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/pkg/errors"
+)
+
+// supported transport security modes.
+const (
+	ModeNone     = "none"
+	ModeSTARTTLS = "starttls"
+	ModeTLS      = "tls"
+)
+
+// Duration decodes a duration from its string representation. e.g.) 30s
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *Duration) UnmarshalText(b []byte) (err error) {
+	t.Duration, err = time.ParseDuration(string(b))
+	return errors.WithStack(err)
+}
+
+// New ...
+func New() *Notifier {
+	return &Notifier{
+		Security: ModeSTARTTLS,
+		Subject:  fmt.Sprintf("Deploy ${%s} - ${%s}", notifications.EnvDeployResult, notifications.EnvDeployID),
+		Message: fmt.Sprintf(
+			"Deploy ${%s}\nDeployment: ${%s}\nCommit: ${%s}\nBy: ${%s}\nError: ${%s}\n",
+			notifications.EnvDeployResult,
+			notifications.EnvDeployID,
+			notifications.EnvDeployCommit,
+			notifications.EnvDeployInitiator,
+			notifications.EnvDeployError,
+		),
+		Batch:   Duration{Duration: 30 * time.Second},
+		Timeout: Duration{Duration: 10 * time.Second},
+		pending: &pending{},
+	}
+}
+
+// Notifier - sends an email for deploy events.
+// when batching is enabled a deploy's begin event is held for the batch window,
+// and if the deploy completes within that window a single email is sent for both.
+type Notifier struct {
+	Address    string   // host:port of the smtp server.
+	Security   string   // one of none, starttls, tls.
+	Insecure   bool     // skip tls verification.
+	Username   string   // optional, enables PLAIN authentication.
+	Password   string   // optional
+	From       string   // sender address.
+	Recipients []string // addresses to deliver the notification to.
+	Subject    string
+	Message    string
+	Batch      Duration // window to combine begin/done events into a single message. zero disables batching.
+	Timeout    Duration
+	pending    *pending
+}
+
+type pending struct {
+	sync.Mutex
+	begin *agent.DeployCommand
+	timer *time.Timer
+}
+
+// Notify send notification about a deploy
+func (t Notifier) Notify(dc *agent.DeployCommand) {
+	if t.Batch.Duration <= 0 || t.pending == nil {
+		t.deliver(dc)
+		return
+	}
+
+	for _, cmds := range t.batch(dc) {
+		t.deliver(cmds...)
+	}
+}
+
+// batch determines which commands are ready to be delivered, each batch is a single message.
+func (t Notifier) batch(dc *agent.DeployCommand) [][]*agent.DeployCommand {
+	t.pending.Lock()
+	defer t.pending.Unlock()
+
+	begin := t.pending.begin
+	if begin != nil && !t.pending.timer.Stop() {
+		// the window elapsed and the begin event is already being delivered.
+		begin = nil
+	}
+	t.pending.begin = nil
+
+	if dc.Command != agent.DeployCommand_Begin {
+		switch {
+		case begin == nil:
+			return [][]*agent.DeployCommand{{dc}}
+		case bytes.Equal(begin.GetArchive().GetDeploymentID(), dc.GetArchive().GetDeploymentID()):
+			return [][]*agent.DeployCommand{{begin, dc}}
+		default:
+			// the command belongs to a different deploy, deliver them separately.
+			return [][]*agent.DeployCommand{{begin}, {dc}}
+		}
+	}
+
+	t.pending.begin = dc
+	t.pending.timer = time.AfterFunc(t.Batch.Duration, func() {
+		t.pending.Lock()
+		expired := t.pending.begin == dc
+		if expired {
+			t.pending.begin = nil
+		}
+		t.pending.Unlock()
+
+		if expired {
+			t.deliver(dc)
+		}
+	})
+
+	if begin == nil {
+		return nil
+	}
+
+	// a previous deploy never completed, deliver its begin event by itself.
+	return [][]*agent.DeployCommand{{begin}}
+}
+
+// deliver a single message covering the provided commands.
+// the subject is generated from the latest command.
+func (t Notifier) deliver(cmds ...*agent.DeployCommand) {
+	var (
+		body = make([]string, 0, len(cmds))
+	)
+
+	if len(cmds) == 0 {
+		return
+	}
+
+	for _, dc := range cmds {
+		body = append(body, notifications.ExpandEnv(t.Message, dc))
+	}
+
+	subject := notifications.ExpandEnv(t.Subject, cmds[len(cmds)-1])
+
+	errorsx.Log(errors.Wrap(t.send(subject, strings.Join(body, "\n")), "failed to send email notification"))
+}
+
+func (t Notifier) send(subject, body string) (err error) {
+	var (
+		conn   net.Conn
+		client *smtp.Client
+		host   string
+	)
+
+	if len(t.Recipients) == 0 {
+		log.Println("smtp notification has no recipients. ignoring.")
+		return nil
+	}
+
+	if host, _, err = net.SplitHostPort(t.Address); err != nil {
+		return errors.Wrapf(err, "invalid smtp address: %s", t.Address)
+	}
+
+	tlsconfig := &tls.Config{ServerName: host, InsecureSkipVerify: t.Insecure}
+	dialer := &net.Dialer{Timeout: t.Timeout.Duration}
+
+	switch t.Security {
+	case ModeTLS:
+		conn, err = tls.DialWithDialer(dialer, "tcp", t.Address, tlsconfig)
+	default:
+		conn, err = dialer.Dial("tcp", t.Address)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "failed to connect")
+	}
+
+	if t.Timeout.Duration > 0 {
+		errorsx.Log(errors.WithStack(conn.SetDeadline(time.Now().Add(t.Timeout.Duration))))
+	}
+
+	if client, err = smtp.NewClient(conn, host); err != nil {
+		return errorsx.Compact(errors.Wrap(err, "failed to initialize client"), conn.Close())
+	}
+	defer client.Close()
+
+	if t.Security == ModeSTARTTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("server does not support STARTTLS")
+		}
+
+		if err = client.StartTLS(tlsconfig); err != nil {
+			return errors.Wrap(err, "starttls failed")
+		}
+	}
+
+	if t.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", t.Username, t.Password, host)); err != nil {
+			return errors.Wrap(err, "authentication failed")
+		}
+	}
+
+	if err = client.Mail(t.From); err != nil {
+		return errors.Wrap(err, "sender rejected")
+	}
+
+	for _, rcpt := range t.Recipients {
+		if err = client.Rcpt(rcpt); err != nil {
+			return errors.Wrapf(err, "recipient rejected: %s", rcpt)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return errors.Wrap(err, "failed to initiate message")
+	}
+
+	if _, err = w.Write(message(t.From, t.Recipients, subject, body)); err != nil {
+		return errorsx.Compact(errors.Wrap(err, "failed to write message"), w.Close())
+	}
+
+	if err = w.Close(); err != nil {
+		return errors.Wrap(err, "failed to send message")
+	}
+
+	return errors.WithStack(client.Quit())
+}
+
+func message(from string, to []string, subject, body string) []byte {
+	buf := bytes.NewBuffer(nil)
+	fmt.Fprintf(buf, "From: %s\r\n", from)
+	fmt.Fprintf(buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(buf, "Subject: %s\r\n", strings.NewReplacer("\r", " ", "\n", " ").Replace(subject))
+	fmt.Fprintf(buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return buf.Bytes()
+}
@@ -0,0 +1,240 @@
+package smtp_test
+
+import (
+	"bufio"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/deployment/notifications/smtp"
+	"github.com/james-lawrence/bw/internal/tlsx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// mailbox is a minimal smtp server that records the messages it receives.
+type mailbox struct {
+	l        net.Listener
+	security string
+	tls      *tls.Config
+	messages chan string
+	secured  chan bool
+}
+
+func newMailbox() mailbox {
+	return newSecureMailbox(smtp.ModeNone)
+}
+
+// newSecureMailbox serves the mailbox using the transport security mode with a self signed certificate.
+func newSecureMailbox(security string) mailbox {
+	template, err := tlsx.X509Template(time.Hour, tlsx.X509OptionHosts("127.0.0.1"), tlsx.X509OptionCA())
+	Expect(err).To(Succeed())
+	key, der, err := tlsx.SelfSignedRSAGen(1024, template)
+	Expect(err).To(Succeed())
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).To(Succeed())
+
+	m := mailbox{
+		l:        l,
+		security: security,
+		tls:      &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}},
+		messages: make(chan string, 10),
+		secured:  make(chan bool, 10),
+	}
+
+	if security == smtp.ModeTLS {
+		m.l = tls.NewListener(l, m.tls)
+	}
+
+	go m.serve()
+	return m
+}
+
+func (t mailbox) serve() {
+	for {
+		conn, err := t.l.Accept()
+		if err != nil {
+			return
+		}
+
+		go t.session(conn)
+	}
+}
+
+func (t mailbox) session(conn net.Conn) {
+	defer func() { conn.Close() }()
+	r := bufio.NewReader(conn)
+	reply := func(s string) { fmt.Fprintf(conn, "%s\r\n", s) }
+	reply("220 localhost ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			if _, secure := conn.(*tls.Conn); t.security == smtp.ModeSTARTTLS && !secure {
+				reply("250-localhost")
+				reply("250 STARTTLS")
+				continue
+			}
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "STARTTLS"):
+			reply("220 ready to start tls")
+			conn = tls.Server(conn, t.tls)
+			r = bufio.NewReader(conn)
+		case strings.HasPrefix(cmd, "DATA"):
+			reply("354 go ahead")
+			body := strings.Builder{}
+			for line, err = r.ReadString('\n'); err == nil && line != ".\r\n"; line, err = r.ReadString('\n') {
+				body.WriteString(line)
+			}
+			_, secure := conn.(*tls.Conn)
+			t.secured <- secure
+			t.messages <- body.String()
+			reply("250 ok")
+		case strings.HasPrefix(cmd, "QUIT"):
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (t mailbox) Close() error {
+	return t.l.Close()
+}
+
+func notifier(address string, batch time.Duration) *smtp.Notifier {
+	n := smtp.New()
+	n.Address = address
+	n.Security = smtp.ModeNone
+	n.From = "bw@example.com"
+	n.Recipients = []string{"ops@example.com"}
+	n.Batch = smtp.Duration{Duration: batch}
+	return n
+}
+
+func command(c agent.DeployCommand_Command) *agent.DeployCommand {
+	return deployCommand(c, "deployment")
+}
+
+func deployCommand(c agent.DeployCommand_Command, id string) *agent.DeployCommand {
+	return &agent.DeployCommand{
+		Command:   c,
+		Initiator: "jane doe",
+		Error:     "",
+		Archive: &agent.Archive{
+			DeploymentID: []byte(id),
+			Commit:       "deadbeef",
+		},
+	}
+}
+
+var _ = Describe("Notifier", func() {
+	It("should deliver the templated message", func() {
+		m := newMailbox()
+		defer m.Close()
+
+		dc := command(agent.DeployCommand_Failed)
+		dc.Error = "deploy failed on 2 node(s)"
+
+		notifier(m.l.Addr().String(), 0).Notify(dc)
+
+		var msg string
+		Eventually(m.messages).Should(Receive(&msg))
+		Expect(msg).To(ContainSubstring("Subject: Deploy Failed"))
+		Expect(msg).To(ContainSubstring("To: ops@example.com"))
+		Expect(msg).To(ContainSubstring("Commit: deadbeef"))
+		Expect(msg).To(ContainSubstring("By: jane doe"))
+		Expect(msg).To(ContainSubstring("Error: deploy failed on 2 node(s)"))
+	})
+
+	It("should combine begin and done events within the batch window", func() {
+		m := newMailbox()
+		defer m.Close()
+
+		n := notifier(m.l.Addr().String(), time.Minute)
+		n.Notify(command(agent.DeployCommand_Begin))
+		n.Notify(command(agent.DeployCommand_Done))
+
+		var msg string
+		Eventually(m.messages).Should(Receive(&msg))
+		Expect(msg).To(ContainSubstring("Subject: Deploy Done"))
+		Expect(msg).To(ContainSubstring("Deploy Begin"))
+		Expect(msg).To(ContainSubstring("Deploy Done"))
+		Consistently(m.messages, 100*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("should deliver the begin event once the batch window elapses", func() {
+		m := newMailbox()
+		defer m.Close()
+
+		n := notifier(m.l.Addr().String(), 10*time.Millisecond)
+		n.Notify(command(agent.DeployCommand_Begin))
+
+		var msg string
+		Eventually(m.messages).Should(Receive(&msg))
+		Expect(msg).To(ContainSubstring("Subject: Deploy Begin"))
+
+		n.Notify(command(agent.DeployCommand_Done))
+		Eventually(m.messages).Should(Receive(&msg))
+		Expect(msg).To(ContainSubstring("Subject: Deploy Done"))
+		Expect(msg).ToNot(ContainSubstring("Deploy Begin"))
+	})
+
+	It("should not combine events from different deploys", func() {
+		m := newMailbox()
+		defer m.Close()
+
+		n := notifier(m.l.Addr().String(), time.Minute)
+		n.Notify(deployCommand(agent.DeployCommand_Begin, "deployment1"))
+		n.Notify(deployCommand(agent.DeployCommand_Done, "deployment2"))
+
+		var msg string
+		Eventually(m.messages).Should(Receive(&msg))
+		Expect(msg).To(ContainSubstring("Subject: Deploy Begin"))
+		Expect(msg).ToNot(ContainSubstring("Deploy Done"))
+
+		Eventually(m.messages).Should(Receive(&msg))
+		Expect(msg).To(ContainSubstring("Subject: Deploy Done"))
+		Expect(msg).ToNot(ContainSubstring("Deploy Begin"))
+	})
+
+	DescribeTable("should deliver over a secured transport",
+		func(security string) {
+			m := newSecureMailbox(security)
+			defer m.Close()
+
+			n := notifier(m.l.Addr().String(), 0)
+			n.Security = security
+			n.Insecure = true
+			n.Notify(command(agent.DeployCommand_Done))
+
+			var msg string
+			Eventually(m.messages).Should(Receive(&msg))
+			Expect(msg).To(ContainSubstring("Subject: Deploy Done"))
+			Expect(m.secured).To(Receive(BeTrue()))
+		},
+		Entry("tls", smtp.ModeTLS),
+		Entry("starttls", smtp.ModeSTARTTLS),
+	)
+
+	It("should refuse servers that do not support starttls", func() {
+		m := newMailbox()
+		defer m.Close()
+
+		n := notifier(m.l.Addr().String(), 0)
+		n.Security = smtp.ModeSTARTTLS
+		n.Notify(command(agent.DeployCommand_Done))
+
+		Consistently(m.messages, 100*time.Millisecond).ShouldNot(Receive())
+	})
+})
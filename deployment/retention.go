@@ -0,0 +1,252 @@
+package deployment
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+const (
+	retainedLogExt        = ".log"
+	retainedLogCompressed = ".log.gz"
+)
+
+// LogRetention controls how deploy logs are retained once a deploy completes.
+// retained logs outlive the deploy directory, which is removed by the KeepN cleaner.
+type LogRetention struct {
+	KeepN         int           // number of deploy logs to retain, zero disables retention.
+	MaxAge        time.Duration // remove retained logs older than this duration, zero disables.
+	Compress      bool          // gzip retained logs.
+	CompressAfter time.Duration // age at which retained logs are compressed, zero compresses them immediately.
+}
+
+// compressed reports if the log should be compressed at the given age.
+func (t LogRetention) compressed(age time.Duration) bool {
+	return t.Compress && age >= t.CompressAfter
+}
+
+// Enabled reports if deploy logs should be retained.
+func (t LogRetention) Enabled() bool {
+	return t.KeepN > 0
+}
+
+// retainLog copies the deploy log into the logs directory and prunes old logs.
+func retainLog(dir string, r LogRetention, id bw.RandomID, src string) (err error) {
+	if !r.Enabled() {
+		return nil
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrap(err, "unable to create logs directory")
+	}
+
+	if err = copyLog(dir, r, id, src); err != nil {
+		return err
+	}
+
+	return pruneLogs(dir, r, time.Now())
+}
+
+func (t *Coordinator) retainLog(id bw.RandomID, src string) error {
+	t.logs.Lock()
+	defer t.logs.Unlock()
+
+	return retainLog(t.logsRoot, t.retention, id, src)
+}
+
+// PruneLogs applies the retention policy to the retained logs. the policy is otherwise only
+// applied once a deploy completes, leaving the logs of idle nodes uncompressed indefinitely.
+func (t *Coordinator) PruneLogs() (err error) {
+	if !t.retention.Enabled() {
+		return nil
+	}
+
+	t.logs.Lock()
+	defer t.logs.Unlock()
+
+	if err = pruneLogs(t.logsRoot, t.retention, time.Now()); errors.Is(err, os.ErrNotExist) {
+		// no deploy has retained a log.
+		return nil
+	}
+
+	return err
+}
+
+func copyLog(dir string, r LogRetention, id bw.RandomID, src string) (err error) {
+	var (
+		in *os.File
+	)
+
+	if in, err = os.Open(src); os.IsNotExist(err) {
+		// silenced deploys do not generate logs.
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "unable to open deploy log")
+	}
+	defer in.Close()
+
+	return writeLog(dir, id, in, r.compressed(0))
+}
+
+// writeLog atomically stores the retained log of the deployment.
+func writeLog(dir string, id bw.RandomID, src io.Reader, compress bool) (err error) {
+	var (
+		out *os.File
+		dst io.WriteCloser
+	)
+
+	path := filepath.Join(dir, id.String()+retainedLogExt)
+	if compress {
+		path = filepath.Join(dir, id.String()+retainedLogCompressed)
+	}
+
+	// write to a temporary file to ensure readers never observe partial logs.
+	if out, err = os.CreateTemp(dir, id.String()+".*.tmp"); err != nil {
+		return errors.Wrap(err, "unable to create retained log")
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	dst = out
+	if compress {
+		dst = gzip.NewWriter(out)
+	}
+
+	if _, err = io.Copy(dst, src); err != nil {
+		return errors.Wrap(err, "unable to copy deploy log")
+	}
+
+	if compress {
+		if err = dst.Close(); err != nil {
+			return errors.Wrap(err, "unable to compress deploy log")
+		}
+	}
+
+	if err = errorsx.Compact(out.Sync(), out.Close()); err != nil {
+		return errors.Wrap(err, "unable to write retained log")
+	}
+
+	return errors.Wrap(os.Rename(out.Name(), path), "unable to store retained log")
+}
+
+// compressLog replaces the uncompressed retained log with its compressed form,
+// the modification time is preserved so the log keeps its place in the retention order.
+func compressLog(dir string, info os.FileInfo) (err error) {
+	var (
+		id  bw.RandomID
+		src *os.File
+	)
+
+	path := filepath.Join(dir, info.Name())
+	if id, err = bw.ParseRandomID(strings.TrimSuffix(info.Name(), retainedLogExt)); err != nil {
+		return errors.Wrapf(err, "invalid retained log: %s", info.Name())
+	}
+
+	if src, err = os.Open(path); err != nil {
+		return errors.WithStack(err)
+	}
+	defer src.Close()
+
+	if err = writeLog(dir, id, src, true); err != nil {
+		return err
+	}
+
+	compressed := filepath.Join(dir, id.String()+retainedLogCompressed)
+	if err = os.Chtimes(compressed, info.ModTime(), info.ModTime()); err != nil {
+		return errors.WithStack(err)
+	}
+
+	// the compressed log exists before the original is removed, readers always find one of them.
+	return errors.WithStack(os.Remove(path))
+}
+
+// pruneLogs removes retained logs beyond the KeepN limit or older than the maximum age,
+// and compresses the remaining logs once they are older than CompressAfter.
+func pruneLogs(dir string, r LogRetention, now time.Time) (err error) {
+	var (
+		entries []os.DirEntry
+		logs    []os.FileInfo
+	)
+
+	if entries, err = os.ReadDir(dir); err != nil {
+		return errors.Wrap(err, "unable to read logs directory")
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !(strings.HasSuffix(e.Name(), retainedLogExt) || strings.HasSuffix(e.Name(), retainedLogCompressed)) {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		logs = append(logs, info)
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].ModTime().After(logs[j].ModTime())
+	})
+
+	for idx, info := range logs {
+		expired := r.MaxAge > 0 && now.Sub(info.ModTime()) > r.MaxAge
+		if idx < r.KeepN && !expired {
+			if strings.HasSuffix(info.Name(), retainedLogExt) && r.compressed(now.Sub(info.ModTime())) {
+				if err = compressLog(dir, info); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if err = os.Remove(filepath.Join(dir, info.Name())); err != nil && !os.IsNotExist(err) {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+// openRetainedLog opens the retained log for the given deployment, decompressing it if necessary.
+func openRetainedLog(dir string, id bw.RandomID) (_ io.ReadCloser, err error) {
+	var (
+		src *os.File
+		gz  *gzip.Reader
+	)
+
+	if src, err = os.Open(filepath.Join(dir, id.String()+retainedLogExt)); err == nil {
+		return src, nil
+	} else if !os.IsNotExist(err) {
+		return nil, errors.WithStack(err)
+	}
+
+	if src, err = os.Open(filepath.Join(dir, id.String()+retainedLogCompressed)); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if gz, err = gzip.NewReader(src); err != nil {
+		return nil, errorsx.Compact(errors.WithStack(err), src.Close())
+	}
+
+	return gzipReadCloser{Reader: gz, src: src}, nil
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	src io.Closer
+}
+
+func (t gzipReadCloser) Close() error {
+	return errorsx.Compact(t.Reader.Close(), t.src.Close())
+}
@@ -0,0 +1,133 @@
+package deployment
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/internal/testingx"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	g "github.com/onsi/gomega"
+)
+
+var _ = Describe("LogRetention", func() {
+	var (
+		workdir string
+		src     string
+	)
+
+	BeforeEach(func() {
+		workdir = testingx.TempDir()
+		src = filepath.Join(workdir, bw.DeployLog)
+		g.Expect(os.WriteFile(src, []byte("hello world\n"), 0600)).To(g.Succeed())
+	})
+
+	read := func(dir string, id bw.RandomID) string {
+		logs, err := openRetainedLog(dir, id)
+		g.Expect(err).To(g.Succeed())
+		defer logs.Close()
+		content, err := io.ReadAll(logs)
+		g.Expect(err).To(g.Succeed())
+		return string(content)
+	}
+
+	DescribeTable("should retain the deploy log", func(compress bool, ext string) {
+		dir := bw.LogsDir(workdir)
+		id := bw.MustGenerateID()
+
+		g.Expect(retainLog(dir, LogRetention{KeepN: 1, Compress: compress}, id, src)).To(g.Succeed())
+		g.Expect(filepath.Join(dir, id.String()+ext)).To(g.BeARegularFile())
+		g.Expect(read(dir, id)).To(g.Equal("hello world\n"))
+	},
+		Entry("uncompressed", false, retainedLogExt),
+		Entry("compressed", true, retainedLogCompressed),
+	)
+
+	It("should do nothing when disabled", func() {
+		dir := bw.LogsDir(workdir)
+		g.Expect(retainLog(dir, LogRetention{}, bw.MustGenerateID(), src)).To(g.Succeed())
+		g.Expect(dir).ToNot(g.BeADirectory())
+	})
+
+	It("should ignore missing deploy logs", func() {
+		dir := bw.LogsDir(workdir)
+		g.Expect(retainLog(dir, LogRetention{KeepN: 1}, bw.MustGenerateID(), filepath.Join(workdir, "missing.log"))).To(g.Succeed())
+	})
+
+	It("should prune logs beyond keepN and older than the maximum age", func() {
+		dir := bw.LogsDir(workdir)
+		r := LogRetention{KeepN: 2, MaxAge: time.Hour}
+		ids := []bw.RandomID{bw.MustGenerateID(), bw.MustGenerateID(), bw.MustGenerateID(), bw.MustGenerateID()}
+		now := time.Now()
+		g.Expect(os.MkdirAll(dir, 0755)).To(g.Succeed())
+
+		for idx, id := range ids {
+			g.Expect(copyLog(dir, r, id, src)).To(g.Succeed())
+			ts := now.Add(-time.Duration(idx) * 45 * time.Minute)
+			g.Expect(os.Chtimes(filepath.Join(dir, id.String()+retainedLogExt), ts, ts)).To(g.Succeed())
+		}
+
+		g.Expect(pruneLogs(dir, r, now)).To(g.Succeed())
+		g.Expect(filepath.Join(dir, ids[0].String()+retainedLogExt)).To(g.BeARegularFile())
+		g.Expect(filepath.Join(dir, ids[1].String()+retainedLogExt)).To(g.BeARegularFile())
+		g.Expect(filepath.Join(dir, ids[2].String()+retainedLogExt)).ToNot(g.BeAnExistingFile())
+		g.Expect(filepath.Join(dir, ids[3].String()+retainedLogExt)).ToNot(g.BeAnExistingFile())
+
+		g.Expect(pruneLogs(dir, r, now.Add(time.Hour))).To(g.Succeed())
+		g.Expect(filepath.Join(dir, ids[0].String()+retainedLogExt)).To(g.BeARegularFile())
+		g.Expect(filepath.Join(dir, ids[1].String()+retainedLogExt)).ToNot(g.BeAnExistingFile())
+	})
+
+	It("should compress retained logs once they are older than the threshold", func() {
+		dir := bw.LogsDir(workdir)
+		r := LogRetention{KeepN: 2, Compress: true, CompressAfter: time.Hour}
+		recent, old := bw.MustGenerateID(), bw.MustGenerateID()
+		now := time.Now()
+		g.Expect(os.MkdirAll(dir, 0755)).To(g.Succeed())
+
+		g.Expect(copyLog(dir, r, recent, src)).To(g.Succeed())
+		g.Expect(copyLog(dir, r, old, src)).To(g.Succeed())
+		ts := now.Add(-2 * time.Hour)
+		g.Expect(os.Chtimes(filepath.Join(dir, old.String()+retainedLogExt), ts, ts)).To(g.Succeed())
+
+		g.Expect(pruneLogs(dir, r, now)).To(g.Succeed())
+		g.Expect(filepath.Join(dir, recent.String()+retainedLogExt)).To(g.BeARegularFile())
+		g.Expect(filepath.Join(dir, old.String()+retainedLogExt)).ToNot(g.BeAnExistingFile())
+		g.Expect(filepath.Join(dir, old.String()+retainedLogCompressed)).To(g.BeARegularFile())
+		g.Expect(read(dir, old)).To(g.Equal("hello world\n"))
+
+		info, err := os.Stat(filepath.Join(dir, old.String()+retainedLogCompressed))
+		g.Expect(err).To(g.Succeed())
+		g.Expect(info.ModTime().Unix()).To(g.Equal(ts.Unix()))
+	})
+
+	It("should read retained logs once the deploy directory is removed", func() {
+		id := bw.MustGenerateID()
+		c := New(nil, NewDirective(), CoordinatorOptionRoot(workdir), CoordinatorOptionLogRetention(LogRetention{KeepN: 1, Compress: true}))
+		g.Expect(retainLog(c.logsRoot, c.retention, id, src)).To(g.Succeed())
+
+		logs := c.Logs(id)
+		defer logs.Close()
+		content, err := io.ReadAll(logs)
+		g.Expect(err).To(g.Succeed())
+		g.Expect(string(content)).To(g.Equal("hello world\n"))
+	})
+
+	It("should compress the logs of idle nodes", func() {
+		id := bw.MustGenerateID()
+		c := New(nil, NewDirective(), CoordinatorOptionRoot(workdir), CoordinatorOptionLogRetention(LogRetention{KeepN: 1, Compress: true, CompressAfter: time.Hour}))
+		g.Expect(c.PruneLogs()).To(g.Succeed())
+
+		g.Expect(c.retainLog(id, src)).To(g.Succeed())
+		ts := time.Now().Add(-2 * time.Hour)
+		g.Expect(os.Chtimes(filepath.Join(c.logsRoot, id.String()+retainedLogExt), ts, ts)).To(g.Succeed())
+
+		g.Expect(c.PruneLogs()).To(g.Succeed())
+		g.Expect(filepath.Join(c.logsRoot, id.String()+retainedLogCompressed)).To(g.BeARegularFile())
+		g.Expect(read(c.logsRoot, id)).To(g.Equal("hello world\n"))
+	})
+})
@@ -0,0 +1,138 @@
+package deployment
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/archive"
+	"github.com/james-lawrence/bw/internal/rsax"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/notary"
+	"github.com/james-lawrence/bw/storage"
+	"github.com/pkg/errors"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	g "github.com/onsi/gomega"
+)
+
+var _ = Describe("Stage", func() {
+	var (
+		workdir string
+		fs      storage.Filesystem
+		c       Coordinator
+	)
+
+	upload := func(content string) *agent.Archive {
+		src := testingx.TempDir()
+		g.Expect(os.WriteFile(filepath.Join(src, "hello.txt"), []byte(content), 0600)).To(g.Succeed())
+		buf := bytes.NewBuffer(nil)
+		g.Expect(archive.Pack(buf, src)).To(g.Succeed())
+
+		u, err := fs.NewUpload(uint64(buf.Len()))
+		g.Expect(err).To(g.Succeed())
+		_, err = u.Upload(buf)
+		g.Expect(err).To(g.Succeed())
+		checksum, location, err := u.Info()
+		g.Expect(err).To(g.Succeed())
+
+		return &agent.Archive{
+			Location:     location,
+			Checksum:     checksum.Sum(nil),
+			DeploymentID: checksum.Sum(nil),
+		}
+	}
+
+	BeforeEach(func() {
+		workdir = testingx.TempDir()
+		fs = storage.NewFilesystem(testingx.TempDir())
+		c = New(
+			agent.NewPeer("node1"),
+			NewDirective(),
+			CoordinatorOptionRoot(workdir),
+			CoordinatorOptionStorage(storage.New(storage.OptionProtocols(fs))),
+		)
+	})
+
+	It("should only keep the most recently staged archive", func() {
+		a1 := upload("first")
+		a2 := upload("second")
+
+		g.Expect(c.Stage(context.Background(), a1)).To(g.Succeed())
+		g.Expect(c.staged(a1)).To(g.BeAnExistingFile())
+		g.Expect(c.Stage(context.Background(), a1)).To(g.Succeed())
+
+		g.Expect(c.Stage(context.Background(), a2)).To(g.Succeed())
+		g.Expect(c.staged(a2)).To(g.BeAnExistingFile())
+		g.Expect(c.staged(a1)).ToNot(g.BeAnExistingFile())
+
+		entries, err := os.ReadDir(c.stagedRoot)
+		g.Expect(err).To(g.Succeed())
+		g.Expect(entries).To(g.HaveLen(1))
+	})
+
+	It("should deploy the staged archive without retrieving it again", func() {
+		a := upload("staged")
+		g.Expect(c.Stage(context.Background(), a)).To(g.Succeed())
+
+		dir := testingx.TempDir()
+		dctx := &DeployContext{
+			ArchiveFile: filepath.Join(dir, "archive"),
+			ArchiveRoot: filepath.Join(dir, "archive.d"),
+			Log:         StdErrLogger("[TEST] "),
+			Archive:     a,
+		}
+		g.Expect(os.MkdirAll(dctx.ArchiveRoot, 0700)).To(g.Succeed())
+
+		dlreg := storage.NoopRegistry{Err: errors.New("archive should not be downloaded")}
+		g.Expect(downloadArchive(dlreg, nil, c.staged(a), dctx)).To(g.Succeed())
+		g.Expect(os.ReadFile(filepath.Join(dctx.ArchiveRoot, "hello.txt"))).To(g.Equal([]byte("staged")))
+		g.Expect(c.staged(a)).ToNot(g.BeAnExistingFile())
+	})
+
+	It("should deploy archives after the signer's grant has expired", func() {
+		pkey, err := rsax.UnsafeAuto()
+		g.Expect(err).To(g.Succeed())
+		ss, err := notary.NewSigner(pkey)
+		g.Expect(err).To(g.Succeed())
+		_, pub, err := ss.AutoSignerInfo()
+		g.Expect(err).To(g.Succeed())
+
+		// a short lived grant, e.g.) an oidc exchange, that expired after the upload.
+		uploaded := time.Now().Add(-time.Hour)
+		grant := notary.NewGrant(notary.UserFull(), pub, notary.GrantOptionWindow(uploaded.Add(-time.Minute), uploaded.Add(15*time.Minute)))
+
+		a := upload("signed")
+		fp, sig, err := ss.SignArchive(a.Checksum, uploaded)
+		g.Expect(err).To(g.Succeed())
+		a.Signature = &agent.ArchiveSignature{Fingerprint: fp, Format: sig.Format, Data: sig.Data, Ts: uploaded.Unix()}
+
+		dir := testingx.TempDir()
+		dctx := &DeployContext{
+			ArchiveFile: filepath.Join(dir, "archive"),
+			ArchiveRoot: filepath.Join(dir, "archive.d"),
+			Log:         StdErrLogger("[TEST] "),
+			Archive:     a,
+			deadline:    context.Background(),
+		}
+		g.Expect(os.MkdirAll(dctx.ArchiveRoot, 0700)).To(g.Succeed())
+
+		v := notary.NewArchiveVerifier(notary.NewMem(grant))
+		g.Expect(downloadArchive(storage.New(storage.OptionProtocols(fs)), v, c.staged(a), dctx)).To(g.Succeed())
+		g.Expect(os.ReadFile(filepath.Join(dctx.ArchiveRoot, "hello.txt"))).To(g.Equal([]byte("signed")))
+
+		// the signed time can't be altered to predate the expiration of the grant.
+		a.Signature.Ts = uploaded.Add(-time.Minute).Unix()
+		g.Expect(downloadArchive(storage.New(storage.OptionProtocols(fs)), v, c.staged(a), dctx)).ToNot(g.Succeed())
+
+		// archives signed after the grant expired are rejected.
+		fp, sig, err = ss.SignArchive(a.Checksum, time.Now())
+		g.Expect(err).To(g.Succeed())
+		a.Signature = &agent.ArchiveSignature{Fingerprint: fp, Format: sig.Format, Data: sig.Data, Ts: time.Now().Unix()}
+		g.Expect(downloadArchive(storage.New(storage.OptionProtocols(fs)), v, c.staged(a), dctx)).ToNot(g.Succeed())
+	})
+})
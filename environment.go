@@ -11,6 +11,7 @@ const (
 	EnvLogsTLS                           = "BEARDED_WOOKIE_LOGS_TLS"                                   // enable logging for tls credentials. boolean, see strconv.ParseBool for valid values.
 	EnvLogsConfiguration                 = "BEARDED_WOOKIE_LOGS_CONFIGURATION"                         // enable logging for configuration. boolean, see strconv.ParseBool for valid values.
 	EnvDisplayName                       = "BEARDED_WOOKIE_DISPLAY_NAME"                               // environment variable to determine display name to be used, defaults to current user's name.
+	EnvOIDCToken                         = "BEARDED_WOOKIE_OIDC_TOKEN"                                 // oidc token exchanged for a short lived deploy grant by `bw notary exchange`.
 	EnvAgentP2PAdvertised                = "BEARDED_WOOKIE_AGENT_P2P_ADVERTISED"                       // environment variable to specify the network address to advertise to peers. e.g.) 127.0.0.1:2000
 	EnvAgentP2PBind                      = "BEARDED_WOOKIE_AGENT_P2P_BIND"                             // environment variable to specify the network address to listen to. e.g.) 0.0.0.0:2000
 	EnvAgentP2PAlternatesBind            = "BEARDED_WOOKIE_AGENT_P2P_ALTERNATES"                       // environment variable to specify the network address to listen to. e.g.) 127.0.0.1:2000
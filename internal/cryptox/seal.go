@@ -0,0 +1,63 @@
+package cryptox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+
+	"github.com/pkg/errors"
+)
+
+// Seal encrypts the plaintext with AES-GCM, the nonce is prepended to the ciphertext.
+// the secret must be 16, 24, or 32 bytes.
+func Seal(secret []byte, plaintext []byte) (_ []byte, err error) {
+	var (
+		aead cipher.AEAD
+	)
+
+	if aead, err = gcm(secret); err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "unable to generate nonce")
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Open decrypts ciphertext produced by Seal, trying each of the secrets in order.
+func Open(ciphertext []byte, secrets ...[]byte) (plaintext []byte, err error) {
+	var (
+		aead cipher.AEAD
+	)
+
+	for _, secret := range secrets {
+		if aead, err = gcm(secret); err != nil {
+			return nil, err
+		}
+
+		if len(ciphertext) < aead.NonceSize() {
+			return nil, errors.New("ciphertext too short")
+		}
+
+		if plaintext, err = aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], nil); err == nil {
+			return plaintext, nil
+		}
+	}
+
+	return nil, errors.New("unable to open ciphertext with any of the secrets")
+}
+
+func gcm(secret []byte) (_ cipher.AEAD, err error) {
+	var (
+		block cipher.Block
+	)
+
+	if block, err = aes.NewCipher(secret); err != nil {
+		return nil, errors.Wrap(err, "invalid secret")
+	}
+
+	return cipher.NewGCM(block)
+}
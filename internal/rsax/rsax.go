@@ -54,11 +54,16 @@ func CachedAuto(path string) (pkey []byte, err error) {
 
 // CachedAutoDeterministic loads/generates an RSA key at the provided filepath.
 func CachedAutoDeterministic(seed []byte, path string) (pkey []byte, err error) {
+	return CachedDeterministic(seed, path, defaultBits)
+}
+
+// CachedDeterministic loads/generates an RSA key of the given size at the provided filepath.
+func CachedDeterministic(seed []byte, path string, bits int) (pkey []byte, err error) {
 	if systemx.FileExists(path) {
 		return os.ReadFile(path)
 	}
 
-	if pkey, err = Deterministic(seed, defaultBits); err != nil {
+	if pkey, err = Deterministic(seed, bits); err != nil {
 		return nil, err
 	}
 
@@ -1,6 +1,7 @@
 package tlsx
 
 import (
+	"crypto"
 	"crypto/md5"
 	"crypto/rand"
 	"crypto/rsa"
@@ -145,8 +146,8 @@ func X509Template(d time.Duration, options ...X509Option) (template x509.Certifi
 	return X509TemplateRand(rand.Reader, d, stdlibclock{}, options...)
 }
 
-// SignedRSAGen ...
-func SignedRSAGen(bits int, template, parent x509.Certificate, parentKey *rsa.PrivateKey) (_ *rsa.PrivateKey, derBytes []byte, err error) {
+// SignedRSAGen generate a key and certificate signed by the parent.
+func SignedRSAGen(bits int, template, parent x509.Certificate, parentKey crypto.Signer) (_ *rsa.PrivateKey, derBytes []byte, err error) {
 	var (
 		priv *rsa.PrivateKey
 	)
@@ -104,6 +104,10 @@ func (t *M) release(p Protocol) {
 	defer log.Println("release completed")
 
 	delete(t.protocols, p)
+
+	if t.defaulted != nil && t.defaulted.p == p {
+		t.defaulted = nil
+	}
 }
 
 func Listen(ctx context.Context, m *M, l net.Listener) error {
@@ -170,12 +174,16 @@ func accept(ctx context.Context, m *M, conn net.Conn) (err error) {
 		}
 
 		if s := tlsconn.ConnectionState(); s.NegotiatedProtocol != "bw.mux" {
-			if m.defaulted == nil {
+			m.m.RLock()
+			defaulted := m.defaulted
+			m.m.RUnlock()
+
+			if defaulted == nil {
 				conn.Close()
 				return errors.Wrap(err, "tls unknown protocol")
 			}
 
-			m.defaulted.inbound <- conn
+			defaulted.inbound <- conn
 			return nil
 		}
 	}
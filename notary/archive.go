@@ -0,0 +1,109 @@
+package notary
+
+import (
+	"crypto/sha256"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
+
+// archive signatures are over a domain separated digest so they can never
+// be mistaken for signatures of any other data. the digest has a fixed length
+// so the time it was signed follows unambiguously.
+func genArchiveSignatureData(digest []byte, signed time.Time) []byte {
+	return strconv.AppendInt(append([]byte("bw.archive.sha256:"), digest...), signed.Unix(), 10)
+}
+
+// SignArchive signs the sha256 digest of a deployment archive along with the time it was signed.
+func (t Signer) SignArchive(digest []byte, signed time.Time) (fingerprint string, s *Signature, err error) {
+	if len(digest) != sha256.Size {
+		return "", nil, errors.Errorf("invalid archive digest, expected %d bytes found %d", sha256.Size, len(digest))
+	}
+
+	if s, err = genSignatureSHA256(t.signer, genArchiveSignatureData(digest, signed)); err != nil {
+		return "", nil, errors.Wrap(err, "failed to sign archive")
+	}
+
+	return t.fingerprint, s, nil
+}
+
+// ArchiveVerifierOption options for the archive verifier.
+type ArchiveVerifierOption func(*ArchiveVerifier)
+
+// ArchiveVerifierOptionUnsigned accept archives without a signature, e.g.) archives
+// uploaded before archives were signed. signed archives are always verified.
+func ArchiveVerifierOptionUnsigned(b bool) ArchiveVerifierOption {
+	return func(v *ArchiveVerifier) {
+		v.unsigned = b
+	}
+}
+
+// NewArchiveVerifier verifies archives were signed by a grant with deploy permission.
+func NewArchiveVerifier(s storage, options ...ArchiveVerifierOption) ArchiveVerifier {
+	v := ArchiveVerifier{storage: s}
+
+	for _, opt := range options {
+		opt(&v)
+	}
+
+	return v
+}
+
+// ArchiveVerifier verifies deployment archive signatures.
+type ArchiveVerifier struct {
+	storage  storage
+	unsigned bool
+}
+
+// VerifyArchive checks the signature of the archive's sha256 digest and the time it was signed. the grant
+// must have been valid when the archive was signed, not when its deployed, so archives remain deployable
+// after short lived grants expire. the signed time is bounded by the current time.
+func (t ArchiveVerifier) VerifyArchive(digest []byte, signed time.Time, fingerprint string, s *Signature) (err error) {
+	var (
+		g    *Grant
+		pkey ssh.PublicKey
+		now  = time.Now()
+	)
+
+	if s == nil || fingerprint == "" {
+		if t.unsigned {
+			log.Println("accepting unsigned archive")
+			return nil
+		}
+
+		return errors.New("archive is not signed")
+	}
+
+	if signed.IsZero() {
+		return errors.Errorf("archive signature is missing the time it was signed: %s", fingerprint)
+	}
+
+	if g, err = t.storage.Lookup(fingerprint); err != nil {
+		return errors.Wrapf(err, "archive signed by an unknown key: %s", fingerprint)
+	}
+
+	if pkey, _, _, _, err = ssh.ParseAuthorizedKey(g.Authorization); err != nil {
+		return errors.Wrapf(err, "unable to parse grant public key: %s", fingerprint)
+	}
+
+	if err = pkey.Verify(genArchiveSignatureData(digest, signed), s.sig()); err != nil {
+		return errors.Wrapf(err, "invalid archive signature: %s", fingerprint)
+	}
+
+	if signed.After(now) {
+		signed = now
+	}
+
+	if err = g.Valid(signed); err != nil {
+		return errors.Wrapf(err, "archive signed by an invalid grant: %s", fingerprint)
+	}
+
+	if !g.Permission.GetDeploy() {
+		return errors.Errorf("archive signed by a grant without deploy permission: %s", fingerprint)
+	}
+
+	return nil
+}
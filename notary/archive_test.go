@@ -0,0 +1,88 @@
+package notary
+
+import (
+	"crypto/sha256"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/internal/rsax"
+)
+
+var _ = Describe("ArchiveVerifier", func() {
+	digest := sha256.Sum256([]byte("archive"))
+
+	signer := func() (Signer, []byte) {
+		pkey, err := rsax.UnsafeAuto()
+		Expect(err).To(Succeed())
+		ss, err := NewSigner(pkey)
+		Expect(err).To(Succeed())
+		_, pub, err := ss.AutoSignerInfo()
+		Expect(err).To(Succeed())
+		return ss, pub
+	}
+
+	It("should accept archives signed by a grant with deploy permission", func() {
+		ss, pub := signer()
+		now := time.Now()
+		fp, sig, err := ss.SignArchive(digest[:], now)
+		Expect(err).To(Succeed())
+		Expect(NewArchiveVerifier(NewMem(NewGrant(UserFull(), pub))).VerifyArchive(digest[:], now, fp, sig)).To(Succeed())
+	})
+
+	It("should reject modified archives", func() {
+		ss, pub := signer()
+		fp, sig, err := ss.SignArchive(digest[:], time.Now())
+		Expect(err).To(Succeed())
+		modified := sha256.Sum256([]byte("modified"))
+		Expect(NewArchiveVerifier(NewMem(NewGrant(UserFull(), pub))).VerifyArchive(modified[:], time.Now(), fp, sig)).ToNot(Succeed())
+	})
+
+	It("should reject archives signed without deploy permission", func() {
+		ss, pub := signer()
+		fp, sig, err := ss.SignArchive(digest[:], time.Now())
+		Expect(err).To(Succeed())
+		Expect(NewArchiveVerifier(NewMem(NewGrant(&Permission{}, pub))).VerifyArchive(digest[:], time.Now(), fp, sig)).ToNot(Succeed())
+	})
+
+	It("should reject unsigned archives and unknown keys", func() {
+		ss, _ := signer()
+		_, pub := signer()
+		fp, sig, err := ss.SignArchive(digest[:], time.Now())
+		Expect(err).To(Succeed())
+		v := NewArchiveVerifier(NewMem(NewGrant(UserFull(), pub)))
+		Expect(v.VerifyArchive(digest[:], time.Now(), "", nil)).ToNot(Succeed())
+		Expect(v.VerifyArchive(digest[:], time.Now(), fp, sig)).ToNot(Succeed())
+	})
+
+	It("should accept archives signed while the grant was valid after the grant expires", func() {
+		ss, pub := signer()
+		expired := time.Now().Add(-time.Hour)
+		v := NewArchiveVerifier(NewMem(NewGrant(UserFull(), pub, GrantOptionWindow(expired.Add(-time.Hour), expired))))
+
+		signed := expired.Add(-time.Minute)
+		fp, sig, err := ss.SignArchive(digest[:], signed)
+		Expect(err).To(Succeed())
+		Expect(v.VerifyArchive(digest[:], signed, fp, sig)).To(Succeed())
+		// the signed time is covered by the signature.
+		Expect(v.VerifyArchive(digest[:], signed.Add(-time.Minute), fp, sig)).ToNot(Succeed())
+		Expect(v.VerifyArchive(digest[:], time.Time{}, fp, sig)).ToNot(Succeed())
+
+		for _, signed := range []time.Time{expired.Add(time.Minute), expired.Add(-2 * time.Hour), time.Now().Add(time.Hour)} {
+			fp, sig, err := ss.SignArchive(digest[:], signed)
+			Expect(err).To(Succeed())
+			Expect(v.VerifyArchive(digest[:], signed, fp, sig)).ToNot(Succeed())
+		}
+	})
+
+	It("should only accept unsigned archives when explicitly permitted", func() {
+		ss, pub := signer()
+		fp, sig, err := ss.SignArchive(digest[:], time.Now())
+		Expect(err).To(Succeed())
+
+		v := NewArchiveVerifier(NewMem(NewGrant(&Permission{}, pub)), ArchiveVerifierOptionUnsigned(true))
+		Expect(v.VerifyArchive(digest[:], time.Now(), "", nil)).To(Succeed())
+		Expect(v.VerifyArchive(digest[:], time.Now(), fp, sig)).ToNot(Succeed())
+	})
+})
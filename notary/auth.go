@@ -21,12 +21,14 @@ import (
 	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/internal/envx"
 	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/fsx"
 	"github.com/james-lawrence/bw/internal/rsax"
 	"github.com/james-lawrence/bw/internal/sshx"
 )
 
 const (
-	mdkey = "authorization"
+	mdkey           = "authorization"
+	defaultTokenTTL = 10 * time.Second
 )
 
 // ErrUnauthorizedKey used when the key isn't authorized by the cluster its trying to connect too.
@@ -61,9 +63,11 @@ func PrivateKeyPath() string {
 }
 
 // ClearAutoSignerKey clears the autosigner from disk.
+// keys held by the ssh-agent only have their public key on disk.
 func ClearAutoSignerKey() error {
 	return errorsx.Compact(
-		os.Remove(PrivateKeyPath()),
+		errorsx.Ignore(os.Remove(PrivateKeyPath()), os.ErrNotExist),
+		ClearSSHAgentSigner(),
 		os.Remove(PublicKeyPath()),
 	)
 }
@@ -123,6 +127,11 @@ func newAutoSignerPath(location string, comment string, kgen keyGen) (s Signer,
 		return s, errors.Wrapf(err, "unable to read authorization key: %s", location)
 	}
 
+	// credentials initialized with the ssh-agent only have their public key on disk.
+	if marker := sshAgentMarker(location); fsx.IsRegularFile(marker) {
+		return newSSHAgentSignerPath(marker)
+	}
+
 	// if the file just didn't exist then great, lets generate it.
 	log.Println("authorization key not found, generating automatically")
 
@@ -182,11 +191,16 @@ func (t Signer) AutoSignerInfo() (fp string, pub []byte, err error) {
 }
 
 func (t Signer) Token() (encoded string, err error) {
+	return t.TokenTTL(defaultTokenTTL)
+}
+
+// TokenTTL generates an encoded authorization that is valid for the given duration.
+func (t Signer) TokenTTL(ttl time.Duration) (encoded string, err error) {
 	var (
 		sig *Signature
 	)
 
-	tok := GenerateToken(t.fingerprint)
+	tok := GenerateTokenTTL(t.fingerprint, ttl)
 
 	if sig, err = genTokenSignature(t.signer, &tok); err != nil {
 		return "", err
@@ -235,6 +249,16 @@ func (t AuthChecker) Authorization(encoded []byte) (err error) {
 }
 
 func decode(s storage, encodedt string) *Permission {
+	if g := authenticate(s, encodedt); g != nil {
+		return g.Permission
+	}
+
+	return none()
+}
+
+// authenticate the encoded authorization returning the grant it was signed by.
+// returns nil when the authorization is invalid.
+func authenticate(s storage, encodedt string) *Grant {
 	var (
 		err     error
 		encoded []byte
@@ -245,40 +269,45 @@ func decode(s storage, encodedt string) *Permission {
 
 	if a, err = DecodeAuthorization(encodedt); err != nil {
 		log.Println(errors.Wrap(err, "failed to decode authorization"))
-		return none()
+		return nil
 	}
 
 	if a.Token == nil || a.Signature == nil {
 		log.Println(errors.Wrap(err, "missing token/signature"))
-		return none()
+		return nil
 	}
 
 	if time.Now().UTC().Unix() > a.Token.Expires {
 		log.Println("request token is expired")
-		return none()
+		return nil
 	}
 
 	if g, err = s.Lookup(a.Token.Fingerprint); err != nil {
 		log.Println(errors.Wrapf(err, "unknown authorization: %s", a.Token.Fingerprint))
-		return none()
+		return nil
+	}
+
+	if err = g.Valid(time.Now()); err != nil {
+		log.Println(errors.Wrapf(err, "invalid authorization: %s", a.Token.Fingerprint))
+		return nil
 	}
 
 	if pkey, _, _, _, err = ssh.ParseAuthorizedKey(g.Authorization); err != nil {
 		log.Println("parse key failed", a.Token.Fingerprint, len(g.Authorization), err)
-		return none()
+		return nil
 	}
 
 	if encoded, err = genSignatureData(a.Token); err != nil {
 		log.Println(errors.Wrap(err, "failed to generate signature data"))
-		return none()
+		return nil
 	}
 
 	if err = pkey.Verify(encoded, a.Signature.sig()); err != nil {
 		log.Println("verify request failed", a.Token.Fingerprint, err)
-		return none()
+		return nil
 	}
 
-	return g.Permission
+	return g
 }
 
 // NewAuth authorization
@@ -299,34 +328,73 @@ type Auth struct {
 
 // Authorize the given request context.
 func (t Auth) Authorize(ctx context.Context) *Permission {
+	_, p := t.Authenticate(ctx)
+	return p
+}
+
+// Authenticate the given request context, returning the verified fingerprint
+// and its permissions. the fingerprint is empty when the request is unauthenticated.
+func (t Auth) Authenticate(ctx context.Context) (fingerprint string, p *Permission) {
 	var (
 		ok   bool
 		md   metadata.MD
 		vals []string
+		g    *Grant
 	)
 
 	if md, ok = metadata.FromIncomingContext(ctx); !ok {
 		log.Println("token metadata")
-		return none()
+		return "", none()
 	}
 
 	if vals = md.Get(mdkey); len(vals) != 1 {
 		log.Println("recieved invalid token", len(vals))
-		return none()
+		return "", none()
+	}
+
+	if g = authenticate(t.storage, vals[0]); g == nil {
+		return "", none()
 	}
 
-	return decode(t.storage, vals[0])
+	return g.Fingerprint, g.Permission
+}
+
+// Agent checks if the request was made by an agent of the cluster.
+func (t Auth) Agent(ctx context.Context) error {
+	return agentOnly(t.Authorize(ctx))
+}
+
+// agents hold the autocert permission, which already allows requesting the cluster's
+// tls certificates, so it identifies the agents of the cluster.
+func agentOnly(p *Permission) error {
+	if !p.Autocert {
+		return status.Error(codes.PermissionDenied, "only agents of the cluster are permitted")
+	}
+
+	return nil
+}
+
+// Fingerprint of the authenticated request context, empty when unauthenticated.
+func (t Auth) Fingerprint(ctx context.Context) string {
+	fp, _ := t.Authenticate(ctx)
+	return fp
 }
 
 // GenerateToken generates a request token for the given fingerprint.
 // this token is unsigned.
 func GenerateToken(fingerprint string) (t Token) {
+	return GenerateTokenTTL(fingerprint, defaultTokenTTL)
+}
+
+// GenerateTokenTTL generates a request token for the given fingerprint
+// that expires after the provided duration. this token is unsigned.
+func GenerateTokenTTL(fingerprint string, ttl time.Duration) (t Token) {
 	ts := time.Now().UTC()
 	return Token{
 		ID:          uuid.Must(uuid.NewV4()).Bytes(),
 		Fingerprint: fingerprint,
 		Issued:      ts.Unix(),
-		Expires:     ts.Add(10 * time.Second).Unix(),
+		Expires:     ts.Add(ttl).Unix(),
 	}
 }
 
@@ -365,20 +433,99 @@ type auth interface {
 	Authorize(ctx context.Context) *Permission
 }
 
-func NewAgentAuth(a auth) AgentAuth {
-	return AgentAuth{
+// AgentAuthOption options for agent authorization.
+type AgentAuthOption func(*AgentAuth)
+
+// AgentAuthOptionLocal restricts scoped permissions to grants whose scope
+// matches the local peer.
+func AgentAuthOptionLocal(p ScopedPeer) AgentAuthOption {
+	return func(a *AgentAuth) {
+		a.local = p
+	}
+}
+
+func NewAgentAuth(a auth, options ...AgentAuthOption) AgentAuth {
+	aa := AgentAuth{
 		auth: a,
 	}
+
+	for _, opt := range options {
+		opt(&aa)
+	}
+
+	return aa
 }
 
 type AgentAuth struct {
 	auth
+	local ScopedPeer
+}
+
+// deploy returns the permission of the request if it is allowed to deploy to the local peer.
+func (t AgentAuth) deploy(ctx context.Context) (p *Permission, err error) {
+	if p = t.Authorize(ctx); !p.Deploy {
+		return p, status.Error(codes.PermissionDenied, "invalid credentials")
+	}
+
+	if t.local != nil && !p.Scope.Match(t.local) {
+		return p, status.Error(codes.PermissionDenied, "peer is outside of the permitted scope")
+	}
+
+	return p, nil
+}
+
+// action checks if the request is allowed to perform the action against the local peer.
+func (t AgentAuth) action(ctx context.Context, action string) (err error) {
+	var (
+		p *Permission
+	)
+
+	if p, err = t.deploy(ctx); err != nil {
+		return err
+	}
+
+	if !p.Scope.Allows(action) {
+		return status.Errorf(codes.PermissionDenied, "%s is not a permitted action", action)
+	}
+
+	return nil
 }
 
 func (t AgentAuth) Deploy(ctx context.Context) error {
-	if t.Authorize(ctx).Deploy {
-		return nil
+	_, err := t.deploy(ctx)
+	return err
+}
+
+// Agent checks if the request was made by an agent of the cluster.
+func (t AgentAuth) Agent(ctx context.Context) error {
+	return agentOnly(t.Authorize(ctx))
+}
+
+// Restart checks if the request is allowed to restart the local peer.
+func (t AgentAuth) Restart(ctx context.Context) error {
+	return t.action(ctx, ActionRestart)
+}
+
+// Profile checks if the request is allowed to debug the local peer.
+func (t AgentAuth) Profile(ctx context.Context) error {
+	return t.action(ctx, ActionProfile)
+}
+
+// DeployTo checks if the request is allowed to deploy to every one of the peers.
+func (t AgentAuth) DeployTo(ctx context.Context, peers ...ScopedPeer) error {
+	var (
+		p *Permission
+	)
+
+	if p = t.Authorize(ctx); !p.Deploy {
+		return status.Error(codes.PermissionDenied, "invalid credentials")
+	}
+
+	for _, peer := range peers {
+		if !p.Scope.Match(peer) {
+			return status.Errorf(codes.PermissionDenied, "peer %s (%s) is outside of the permitted scope", peer.GetName(), peer.GetIp())
+		}
 	}
 
-	return status.Error(codes.PermissionDenied, "invalid credentials")
+	return nil
 }
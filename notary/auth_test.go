@@ -3,6 +3,7 @@ package notary
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -33,3 +34,23 @@ var _ = Describe("newAutoSignerPath", func() {
 		Expect(err).ToNot(Succeed())
 	})
 })
+
+var _ = Describe("decode", func() {
+	It("should reject grants outside of their window", func() {
+		pkey, err := rsax.UnsafeAuto()
+		Expect(err).To(Succeed())
+		ss, err := NewSigner(pkey)
+		Expect(err).To(Succeed())
+		_, pub, err := ss.AutoSignerInfo()
+		Expect(err).To(Succeed())
+
+		encoded, err := ss.Token()
+		Expect(err).To(Succeed())
+
+		now := time.Now()
+		Expect(decode(NewMem(NewGrant(UserFull(), pub)), encoded)).To(Equal(UserFull()))
+		Expect(decode(NewMem(NewGrant(UserFull(), pub, GrantOptionWindow(now.Add(-time.Hour), now.Add(time.Hour)))), encoded)).To(Equal(UserFull()))
+		Expect(decode(NewMem(NewGrant(UserFull(), pub, GrantOptionWindow(time.Time{}, now.Add(-time.Minute)))), encoded)).To(Equal(none()))
+		Expect(decode(NewMem(NewGrant(UserFull(), pub, GrantOptionWindow(now.Add(time.Hour), time.Time{}))), encoded)).To(Equal(none()))
+	})
+})
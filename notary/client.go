@@ -72,6 +72,38 @@ func (t Client) Revoke(fingerprint string) (g *Grant, err error) {
 	return resp.Grant, err
 }
 
+// Exchange the oidc token for a short lived grant of the signer's public key.
+func (t Client) Exchange(ctx context.Context, token string, ss Signer) (g *Grant, err error) {
+	var (
+		resp *ExchangeResponse
+		c    NotaryClient
+		pub  []byte
+		sig  *Signature
+	)
+
+	if _, pub, err = ss.AutoSignerInfo(); err != nil {
+		return g, err
+	}
+
+	if sig, err = ss.SignExchange(token); err != nil {
+		return g, err
+	}
+
+	if c, err = t.cached(); err != nil {
+		return g, err
+	}
+
+	if resp, err = c.Exchange(ctx, &ExchangeRequest{Token: token, Authorization: pub, Signature: sig}); err != nil {
+		return g, err
+	}
+
+	if resp.Grant == nil {
+		return g, errorsx.String("invalid response")
+	}
+
+	return resp.Grant, err
+}
+
 // Refresh refresh TLS credentials.
 func (t Client) Refresh() (ca, key, cert []byte, err error) {
 	var (
@@ -3,6 +3,7 @@ package notary
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/bits-and-blooms/bloom/v3"
 	"github.com/james-lawrence/bw/internal/debugx"
@@ -41,6 +42,18 @@ func (t Composite) Lookup(fingerprint string) (g *Grant, err error) {
 	return g, err
 }
 
+// Source describes the bucket containing the fingerprint, starting with the primary.
+// empty when no bucket contains the fingerprint.
+func (t Composite) Source(fingerprint string) string {
+	for _, b := range append([]storage{t.primary}, t.buckets...) {
+		if _, err := b.Lookup(fingerprint); err == nil {
+			return describe(b)
+		}
+	}
+
+	return ""
+}
+
 // Bloomfilter generates a bloom filter representing the current state of the notary system.
 func (t Composite) Bloomfilter(ctx context.Context) (b *bloom.BloomFilter, err error) {
 	var (
@@ -62,7 +75,7 @@ func (t Composite) Bloomfilter(ctx context.Context) (b *bloom.BloomFilter, err e
 
 	for g := range c {
 		debugx.Println("bloomfilter adding", g.Fingerprint)
-		b.AddString(g.Fingerprint)
+		b.Add(g.syncKey())
 	}
 
 	select {
@@ -108,3 +121,14 @@ func (t Composite) Insert(g *Grant) (*Grant, error) {
 func (t Composite) Delete(g *Grant) (*Grant, error) {
 	return t.primary.Delete(g)
 }
+
+// GC removes expired grants from the primary.
+func (t Composite) GC(now time.Time) ([]*Grant, error) {
+	if gc, ok := t.primary.(interface {
+		GC(time.Time) ([]*Grant, error)
+	}); ok {
+		return gc.GC(now)
+	}
+
+	return nil, nil
+}
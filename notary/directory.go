@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
@@ -25,6 +26,11 @@ type Directory struct {
 	m    *sync.RWMutex
 }
 
+// String describes the directory.
+func (t Directory) String() string {
+	return t.root
+}
+
 func (t Directory) lookup(fingerprint string) (key string, g *Grant, err error) {
 	if strings.TrimSpace(fingerprint) == "" {
 		return key, nil, errors.New("can not use an empty fingerprint")
@@ -85,7 +91,7 @@ func (t Directory) Sync(ctx context.Context, b Bloomy, c chan *Grant) error {
 			return nil
 		}
 
-		if b.Test([]byte(g.Fingerprint)) {
+		if b.Test(g.syncKey()) {
 			return nil
 		}
 
@@ -155,3 +161,38 @@ func (t Directory) Delete(g *Grant) (_ *Grant, err error) {
 
 	return g, nil
 }
+
+// GC removes the grants that have expired as of the given time.
+func (t Directory) GC(now time.Time) (removed []*Grant, err error) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	err = filepath.Walk(t.root, func(path string, d os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		g, err := t.read(path)
+		if err != nil || !g.Expired(now) {
+			return nil
+		}
+
+		if err = os.Remove(path); err != nil {
+			return errors.Wrapf(err, "unable to remove %s", path)
+		}
+
+		removed = append(removed, g)
+
+		return nil
+	})
+
+	if os.IsNotExist(errors.Cause(err)) {
+		return removed, nil
+	}
+
+	return removed, err
+}
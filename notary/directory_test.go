@@ -1,6 +1,9 @@
 package notary_test
 
 import (
+	"path/filepath"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"google.golang.org/protobuf/proto"
@@ -51,3 +54,37 @@ var _ = Describe("Directory", func() {
 		Expect(proto.Equal(g1f, g1u)).To(BeTrue())
 	})
 })
+
+var _ = Describe("Directory.GC", func() {
+	It("should remove expired grants", func() {
+		now := time.Now()
+		s := NewDirectory(
+			testingx.TempDir(),
+		)
+
+		expired, err := s.Insert(NewGrant(UserFull(), []byte{0}, GrantOptionWindow(time.Time{}, now.Add(-time.Minute))))
+		Expect(err).To(Succeed())
+		active, err := s.Insert(NewGrant(UserFull(), []byte{1}, GrantOptionWindow(time.Time{}, now.Add(time.Hour))))
+		Expect(err).To(Succeed())
+		unbounded, err := s.Insert(NewGrant(UserFull(), []byte{2}))
+		Expect(err).To(Succeed())
+
+		removed, err := s.GC(now)
+		Expect(err).To(Succeed())
+		Expect(removed).To(HaveLen(1))
+		Expect(removed[0].Fingerprint).To(Equal(expired.Fingerprint))
+
+		_, err = s.Lookup(expired.Fingerprint)
+		Expect(err).ToNot(Succeed())
+		_, err = s.Lookup(active.Fingerprint)
+		Expect(err).To(Succeed())
+		_, err = s.Lookup(unbounded.Fingerprint)
+		Expect(err).To(Succeed())
+	})
+
+	It("should succeed when the directory does not exist", func() {
+		removed, err := NewDirectory(filepath.Join(testingx.TempDir(), "missing")).GC(time.Now())
+		Expect(err).To(Succeed())
+		Expect(removed).To(BeEmpty())
+	})
+})
@@ -48,6 +48,11 @@ type file struct {
 	w      *fsnotify.Watcher
 }
 
+// String describes the file.
+func (t *file) String() string {
+	return t.source
+}
+
 func (t *file) background() *file {
 	ts := time.Now()
 	log.Printf("authorization load initiated %s\n", t.source)
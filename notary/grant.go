@@ -1,6 +1,13 @@
 package notary
 
-import "github.com/james-lawrence/bw/internal/sshx"
+import (
+	"fmt"
+	"time"
+
+	"github.com/james-lawrence/bw/internal/sshx"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+)
 
 // EnsureDefaults for the current grant.
 func (t *Grant) EnsureDefaults() *Grant {
@@ -12,5 +19,91 @@ func (t *Grant) EnsureDefaults() *Grant {
 		t.Fingerprint = sshx.FingerprintSHA256(t.Authorization)
 	}
 
+	if t.Comment == "" {
+		if _, comment, _, _, err := ssh.ParseAuthorizedKey(t.Authorization); err == nil {
+			t.Comment = comment
+		}
+	}
+
 	return t
 }
+
+// syncKey identifies the grant during synchronization. bounded grants include
+// their window so renewals propagate to agents that already know the fingerprint.
+func (t *Grant) syncKey() []byte {
+	if t.NotBefore == 0 && t.NotAfter == 0 {
+		return []byte(t.Fingerprint)
+	}
+
+	return []byte(fmt.Sprintf("%s:%d:%d", t.Fingerprint, t.NotBefore, t.NotAfter))
+}
+
+// Valid checks if the grant is usable at the given time.
+// grants without bounds are always valid.
+func (t *Grant) Valid(now time.Time) error {
+	if t.NotBefore > 0 && now.Unix() < t.NotBefore {
+		return errors.Errorf("grant is not valid until %s", time.Unix(t.NotBefore, 0).UTC().Format(time.RFC3339))
+	}
+
+	if t.Expired(now) {
+		return errors.Errorf("grant expired at %s", time.Unix(t.NotAfter, 0).UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// Expired returns true when the grant can no longer be used.
+func (t *Grant) Expired(now time.Time) bool {
+	return t.NotAfter > 0 && now.Unix() >= t.NotAfter
+}
+
+// GrantOptionWindow restrict the grant to the given time window.
+// zero times leave that side of the window unbounded.
+func GrantOptionWindow(notBefore, notAfter time.Time) GrantOption {
+	unix := func(ts time.Time) int64 {
+		if ts.IsZero() {
+			return 0
+		}
+
+		return ts.Unix()
+	}
+
+	return func(g *Grant) {
+		g.NotBefore = unix(notBefore)
+		g.NotAfter = unix(notAfter)
+	}
+}
+
+// GrantOptionTTL restrict the grant to the duration starting now.
+func GrantOptionTTL(ttl time.Duration) GrantOption {
+	ts := time.Now()
+	return GrantOptionWindow(ts, ts.Add(ttl))
+}
+
+// GrantOptionComment set the comment of the grant.
+func GrantOptionComment(comment string) GrantOption {
+	return func(g *Grant) {
+		g.Comment = comment
+	}
+}
+
+// GrantOptionExchanged marks the grant as issued in exchange for an oidc token.
+func GrantOptionExchanged() GrantOption {
+	return func(g *Grant) {
+		g.Exchanged = true
+	}
+}
+
+// NewGrant generate a grant for the given public key.
+func NewGrant(p *Permission, pub []byte, options ...GrantOption) *Grant {
+	g := Grant{
+		Permission:    p,
+		Authorization: pub,
+	}
+
+	for _, opt := range options {
+		opt(&g)
+	}
+
+	return g.EnsureDefaults()
+}
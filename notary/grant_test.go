@@ -0,0 +1,34 @@
+package notary_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw/notary"
+)
+
+var _ = Describe("Grant", func() {
+	now := time.Now()
+
+	DescribeTable("Valid",
+		func(g *Grant, valid bool) {
+			if valid {
+				Expect(g.Valid(now)).To(Succeed())
+			} else {
+				Expect(g.Valid(now)).ToNot(Succeed())
+			}
+		},
+		Entry("unbounded", NewGrant(UserFull(), []byte{0}), true),
+		Entry("within the window", NewGrant(UserFull(), []byte{0}, GrantOptionWindow(now.Add(-time.Hour), now.Add(time.Hour))), true),
+		Entry("not yet valid", NewGrant(UserFull(), []byte{0}, GrantOptionWindow(now.Add(time.Hour), time.Time{})), false),
+		Entry("expired", NewGrant(UserFull(), []byte{0}, GrantOptionWindow(time.Time{}, now.Add(-time.Second))), false),
+	)
+
+	It("should bound the grant by the ttl", func() {
+		g := NewGrant(UserFull(), []byte{0}, GrantOptionTTL(8*time.Hour))
+		Expect(g.Expired(now)).To(BeFalse())
+		Expect(g.Expired(now.Add(8*time.Hour + time.Minute))).To(BeTrue())
+	})
+})
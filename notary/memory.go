@@ -24,6 +24,11 @@ type memory struct {
 	m   *sync.RWMutex
 }
 
+// String describes the memory storage.
+func (t memory) String() string {
+	return "memory"
+}
+
 func (t memory) UnsafeSnapshot() (dst []*Grant) {
 	dst = make([]*Grant, 0, len(t.mem))
 	for _, v := range t.mem {
@@ -75,8 +80,8 @@ func (t memory) Sync(ctx context.Context, b Bloomy, c chan *Grant) (err error) {
 	t.m.RLock()
 	defer t.m.RUnlock()
 
-	for k, g := range t.mem {
-		if b.Test([]byte(k)) {
+	for _, g := range t.mem {
+		if b.Test(g.syncKey()) {
 			continue
 		}
 
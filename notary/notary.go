@@ -3,6 +3,7 @@ package notary
 import (
 	"context"
 	"crypto/x509/pkix"
+	"fmt"
 	"log"
 	"time"
 
@@ -11,11 +12,13 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/bits-and-blooms/bloom/v3"
+	"github.com/go-jose/go-jose/v4/jwt"
 	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/bw/internal/rsax"
 	"github.com/james-lawrence/bw/internal/sshx"
 	"github.com/james-lawrence/bw/internal/tlsx"
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
 )
 
 // GrantOption
@@ -88,6 +91,24 @@ type storage interface {
 
 type option func(*Service)
 
+// OptionOIDC trust the oidc issuers to exchange workload identity tokens for deploy grants.
+func OptionOIDC(issuers ...OIDC) option {
+	return func(s *Service) {
+		s.oidc = append(s.oidc, issuers...)
+	}
+}
+
+// OptionRotation enable rotation of the certificate authority.
+func OptionRotation(r rotator) option {
+	return func(s *Service) {
+		s.rotator = r
+	}
+}
+
+type rotator interface {
+	Rotate(*RotateRequest) (*Rotation, error)
+}
+
 type authority interface {
 	Create(duration time.Duration, bits int, options ...tlsx.X509Option) (ca, key, cert []byte, err error)
 }
@@ -109,6 +130,8 @@ type Service struct {
 	authority  authority
 	storage    storage
 	auth       Auth
+	oidc       oidcExchange
+	rotator    rotator
 }
 
 func (t Service) merge(options ...option) Service {
@@ -124,6 +147,23 @@ func (t Service) Bind(s *grpc.Server, options ...option) {
 	RegisterNotaryServer(s, t)
 }
 
+// BindExchange binds only the oidc exchange to the given grpc server.
+// the exchange is the credential of callers without a grant, so it's served
+// without requiring the caller to be authorized.
+func (t Service) BindExchange(s *grpc.Server) {
+	RegisterNotaryServer(s, exchange{svc: t})
+}
+
+// exchange only implements the oidc exchange of the notary service.
+type exchange struct {
+	UnimplementedNotaryServer
+	svc Service
+}
+
+func (t exchange) Exchange(ctx context.Context, req *ExchangeRequest) (*ExchangeResponse, error) {
+	return t.svc.Exchange(ctx, req)
+}
+
 // Grant add a grant to the notary service.
 func (t Service) Grant(ctx context.Context, req *GrantRequest) (_ *GrantResponse, err error) {
 	var (
@@ -168,6 +208,60 @@ func (t Service) Revoke(ctx context.Context, req *RevokeRequest) (_ *RevokeRespo
 	}, nil
 }
 
+// Exchange a workload identity (oidc) token for a short lived deploy grant.
+// the token is the credential, the request does not need to be signed by an authorized key.
+// the token must be signed by the key being granted, and only grants previously issued by an
+// exchange are replaced.
+func (t Service) Exchange(ctx context.Context, req *ExchangeRequest) (_ *ExchangeResponse, err error) {
+	var (
+		claims jwt.Claims
+		ttl    time.Duration
+		key    ssh.PublicKey
+		g      *Grant
+	)
+
+	if len(t.oidc) == 0 {
+		return nil, status.Error(codes.Unimplemented, "oidc exchange is not configured")
+	}
+
+	if claims, ttl, err = t.oidc.verify(req.Token, time.Now()); err != nil {
+		log.Println(errors.Wrap(err, "oidc exchange rejected"))
+		return nil, status.Error(codes.PermissionDenied, "invalid credentials")
+	}
+
+	if key, _, _, _, err = ssh.ParseAuthorizedKey(req.Authorization); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid public key")
+	}
+
+	if err = verifyExchange(key, req.Token, req.Signature); err != nil {
+		log.Println(errors.Wrap(err, "oidc exchange rejected"))
+		return nil, status.Error(codes.PermissionDenied, "invalid credentials")
+	}
+
+	g = NewGrant(
+		&Permission{Deploy: true},
+		ssh.MarshalAuthorizedKey(key),
+		GrantOptionTTL(ttl),
+		GrantOptionComment(fmt.Sprintf("oidc %s %s", claims.Issuer, claims.Subject)),
+		GrantOptionExchanged(),
+	)
+
+	if existing, cause := t.storage.Lookup(g.Fingerprint); cause == nil && !existing.Exchanged {
+		log.Println("oidc exchange rejected, the key already holds a grant", g.Fingerprint)
+		return nil, status.Error(codes.AlreadyExists, "the key already holds a grant")
+	}
+
+	if g, err = t.storage.Insert(g); err != nil {
+		return nil, err
+	}
+
+	log.Println("oidc exchange granted", g.Fingerprint, g.Comment, ttl)
+
+	return &ExchangeResponse{
+		Grant: g,
+	}, nil
+}
+
 // Refresh generate new TLS credentials
 func (t Service) Refresh(ctx context.Context, req *RefreshRequest) (_ *RefreshResponse, err error) {
 	var (
@@ -194,59 +288,105 @@ func (t Service) Refresh(ctx context.Context, req *RefreshRequest) (_ *RefreshRe
 	return &resp, nil
 }
 
-// Search the notary service for grants.
+// Rotate advance the rotation of this node's certificate authority.
+func (t Service) Rotate(ctx context.Context, req *RotateRequest) (_ *RotateResponse, err error) {
+	var (
+		r *Rotation
+	)
+
+	if p := t.auth.Authorize(ctx); !p.Grant {
+		return nil, status.Error(codes.PermissionDenied, "invalid credentials")
+	}
+
+	if t.rotator == nil {
+		return nil, status.Error(codes.Unimplemented, "certificate authority rotation is not available")
+	}
+
+	if r, err = t.rotator.Rotate(req); err != nil {
+		log.Println("certificate authority rotation failed", req.Phase, req.Generation, err)
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	if req.Phase != Rotation_Idle {
+		log.Println("certificate authority rotation", r.Generation, r.Phase)
+	}
+
+	return &RotateResponse{Rotation: r}, nil
+}
+
+// Search the notary service for grants matching the query.
 func (t Service) Search(req *SearchRequest, s Notary_SearchServer) (err error) {
+	var (
+		q       Query
+		matches []*Grant
+		next    string
+	)
+
 	if p := t.auth.Authorize(s.Context()); !p.Search {
 		return status.Error(codes.PermissionDenied, "invalid credentials")
 	}
-	grantevent := func(grants []*Grant) *SearchResponse {
-		return &SearchResponse{
-			Grants: grants,
-		}
+
+	if q, err = ParseQuery(req.Query); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
 	}
 
 	b := bloom.NewWithEstimates(1000, 0.0001)
 
 	out := make(chan *Grant, 200)
-	errc := make(chan error)
+	errc := make(chan error, 1)
 	go func() {
 		errc <- t.storage.Sync(s.Context(), b, out)
 		close(out)
 	}()
 
-	batch := make([]*Grant, 0, 100)
-
-	for {
-		select {
-		case g, ok := <-out:
-			if !ok {
-				if err = s.Send(grantevent(batch)); err != nil {
-					log.Println(errors.Wrap(err, "failed to send event"))
-					return status.Error(codes.Internal, "failed to send event")
-				}
-				return nil
-			}
-
-			if batch = append(batch, g); len(batch) < cap(batch) {
-				continue
-			}
-
-			if err = s.Send(grantevent(batch)); err != nil {
-				log.Println(errors.Wrap(err, "failed to send event"))
-				return status.Error(codes.Internal, "failed to send event")
-			}
-
-			batch = batch[:0]
-		case err := <-errc:
-			if err == nil {
-				continue
-			}
-
-			if cause := s.Send(grantevent(batch)); cause != nil {
-				log.Println(errors.Wrap(cause, "failed to send event"))
-			}
+	// storages are synced in priority order, the first grant for a fingerprint wins.
+	seen := make(map[string]bool)
+	for g := range out {
+		if seen[g.Fingerprint] || !q.Match(g) {
+			continue
+		}
+
+		seen[g.Fingerprint] = true
+		matches = append(matches, g)
+	}
+
+	if err = <-errc; err != nil {
+		log.Println(errors.Wrap(err, "failed to search grants"))
+		return status.Error(codes.Internal, "failed to search grants")
+	}
+
+	matches, next = page(matches, req.Cursor, int(req.Limit))
+
+	source := func(fingerprint string) string {
+		if c, ok := t.storage.(interface{ Source(string) string }); ok {
+			return c.Source(fingerprint)
+		}
+
+		return describe(t.storage)
+	}
+
+	for len(matches) > 0 || next != "" {
+		batch := matches[:min(len(matches), 100)]
+		matches = matches[len(batch):]
 
+		resp := &SearchResponse{
+			Grants:  batch,
+			Sources: make(map[string]string, len(batch)),
+		}
+
+		for _, g := range batch {
+			resp.Sources[g.Fingerprint] = source(g.Fingerprint)
+		}
+
+		if len(matches) == 0 {
+			resp.Next, next = next, ""
+		}
+
+		if err = s.Send(resp); err != nil {
+			log.Println(errors.Wrap(err, "failed to send event"))
 			return status.Error(codes.Internal, "failed to send event")
 		}
 	}
+
+	return nil
 }
@@ -19,10 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Notary_Grant_FullMethodName   = "/notary.Notary/Grant"
-	Notary_Revoke_FullMethodName  = "/notary.Notary/Revoke"
-	Notary_Refresh_FullMethodName = "/notary.Notary/Refresh"
-	Notary_Search_FullMethodName  = "/notary.Notary/Search"
+	Notary_Grant_FullMethodName    = "/notary.Notary/Grant"
+	Notary_Revoke_FullMethodName   = "/notary.Notary/Revoke"
+	Notary_Refresh_FullMethodName  = "/notary.Notary/Refresh"
+	Notary_Search_FullMethodName   = "/notary.Notary/Search"
+	Notary_Exchange_FullMethodName = "/notary.Notary/Exchange"
+	Notary_Rotate_FullMethodName   = "/notary.Notary/Rotate"
 )
 
 // NotaryClient is the client API for Notary service.
@@ -35,6 +37,8 @@ type NotaryClient interface {
 	Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*RevokeResponse, error)
 	Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*RefreshResponse, error)
 	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SearchResponse], error)
+	Exchange(ctx context.Context, in *ExchangeRequest, opts ...grpc.CallOption) (*ExchangeResponse, error)
+	Rotate(ctx context.Context, in *RotateRequest, opts ...grpc.CallOption) (*RotateResponse, error)
 }
 
 type notaryClient struct {
@@ -94,6 +98,26 @@ func (c *notaryClient) Search(ctx context.Context, in *SearchRequest, opts ...gr
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Notary_SearchClient = grpc.ServerStreamingClient[SearchResponse]
 
+func (c *notaryClient) Exchange(ctx context.Context, in *ExchangeRequest, opts ...grpc.CallOption) (*ExchangeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExchangeResponse)
+	err := c.cc.Invoke(ctx, Notary_Exchange_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notaryClient) Rotate(ctx context.Context, in *RotateRequest, opts ...grpc.CallOption) (*RotateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateResponse)
+	err := c.cc.Invoke(ctx, Notary_Rotate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NotaryServer is the server API for Notary service.
 // All implementations must embed UnimplementedNotaryServer
 // for forward compatibility.
@@ -104,6 +128,8 @@ type NotaryServer interface {
 	Revoke(context.Context, *RevokeRequest) (*RevokeResponse, error)
 	Refresh(context.Context, *RefreshRequest) (*RefreshResponse, error)
 	Search(*SearchRequest, grpc.ServerStreamingServer[SearchResponse]) error
+	Exchange(context.Context, *ExchangeRequest) (*ExchangeResponse, error)
+	Rotate(context.Context, *RotateRequest) (*RotateResponse, error)
 	mustEmbedUnimplementedNotaryServer()
 }
 
@@ -126,6 +152,12 @@ func (UnimplementedNotaryServer) Refresh(context.Context, *RefreshRequest) (*Ref
 func (UnimplementedNotaryServer) Search(*SearchRequest, grpc.ServerStreamingServer[SearchResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method Search not implemented")
 }
+func (UnimplementedNotaryServer) Exchange(context.Context, *ExchangeRequest) (*ExchangeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Exchange not implemented")
+}
+func (UnimplementedNotaryServer) Rotate(context.Context, *RotateRequest) (*RotateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rotate not implemented")
+}
 func (UnimplementedNotaryServer) mustEmbedUnimplementedNotaryServer() {}
 func (UnimplementedNotaryServer) testEmbeddedByValue()                {}
 
@@ -212,6 +244,42 @@ func _Notary_Search_Handler(srv interface{}, stream grpc.ServerStream) error {
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type Notary_SearchServer = grpc.ServerStreamingServer[SearchResponse]
 
+func _Notary_Exchange_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExchangeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotaryServer).Exchange(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Notary_Exchange_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotaryServer).Exchange(ctx, req.(*ExchangeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Notary_Rotate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotaryServer).Rotate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Notary_Rotate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotaryServer).Rotate(ctx, req.(*RotateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Notary_ServiceDesc is the grpc.ServiceDesc for Notary service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -231,6 +299,14 @@ var Notary_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Refresh",
 			Handler:    _Notary_Refresh_Handler,
 		},
+		{
+			MethodName: "Exchange",
+			Handler:    _Notary_Exchange_Handler,
+		},
+		{
+			MethodName: "Rotate",
+			Handler:    _Notary_Rotate_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -0,0 +1,258 @@
+package notary
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// default duration of grants issued in exchange for an oidc token.
+	defaultOIDCTTL = 15 * time.Minute
+	// tolerated clock skew between the issuer and the notary.
+	oidcLeeway = time.Minute
+)
+
+// signature algorithms accepted for oidc tokens.
+var oidcAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// OIDCOption options for an oidc issuer.
+type OIDCOption func(*OIDC)
+
+// OIDCOptionTTL duration of the grants issued for the issuer's tokens.
+func OIDCOptionTTL(d time.Duration) OIDCOption {
+	return func(o *OIDC) {
+		if d > 0 {
+			o.ttl = d
+		}
+	}
+}
+
+// OIDCOptionJWKSFile reload the issuer's keys from the file whenever it is modified,
+// allowing the issuer to rotate its keys without restarting the agent.
+func OIDCOptionJWKSFile(path string) OIDCOption {
+	return func(o *OIDC) {
+		o.jwks = &jwksFile{path: path, m: &sync.Mutex{}}
+	}
+}
+
+// jwksFile json web key set that is reloaded when the file changes.
+type jwksFile struct {
+	path     string
+	m        *sync.Mutex
+	modified time.Time
+	keys     jose.JSONWebKeySet
+}
+
+// current keys within the file, the previously loaded keys are used when the file is unreadable.
+func (t *jwksFile) current(fallback jose.JSONWebKeySet) jose.JSONWebKeySet {
+	var (
+		err  error
+		info os.FileInfo
+		keys jose.JSONWebKeySet
+	)
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if t.modified.IsZero() {
+		t.keys = fallback
+	}
+
+	if info, err = os.Stat(t.path); err != nil {
+		log.Println(errors.Wrapf(err, "unable to reload jwks: %s", t.path))
+		return t.keys
+	}
+
+	if info.ModTime().Equal(t.modified) {
+		return t.keys
+	}
+
+	if keys, err = LoadJWKS(t.path); err != nil {
+		log.Println(errors.Wrap(err, "unable to reload jwks"))
+		return t.keys
+	}
+
+	t.modified, t.keys = info.ModTime(), keys
+
+	return t.keys
+}
+
+// LoadJWKS reads a json web key set from disk.
+func LoadJWKS(path string) (keys jose.JSONWebKeySet, err error) {
+	var (
+		encoded []byte
+	)
+
+	if encoded, err = os.ReadFile(path); err != nil {
+		return keys, errors.Wrapf(err, "unable to read jwks: %s", path)
+	}
+
+	if err = json.Unmarshal(encoded, &keys); err != nil {
+		return keys, errors.Wrapf(err, "invalid jwks: %s", path)
+	}
+
+	return keys, nil
+}
+
+// NewOIDC trust tokens from the issuer for the audience whose subject matches any of the subjects.
+// subjects are regular expressions that must match the entire subject, e.g.) repo:example/app:ref:refs/heads/main.
+func NewOIDC(issuer, audience string, keys jose.JSONWebKeySet, subjects []string, options ...OIDCOption) (o OIDC, err error) {
+	if issuer == "" || audience == "" {
+		return o, errors.New("oidc issuer and audience are required")
+	}
+
+	if len(subjects) == 0 {
+		return o, errors.Errorf("oidc issuer %s must restrict the allowed subjects", issuer)
+	}
+
+	o = OIDC{
+		issuer:   issuer,
+		audience: audience,
+		keys:     keys,
+		ttl:      defaultOIDCTTL,
+	}
+
+	for _, s := range subjects {
+		var (
+			r *regexp.Regexp
+		)
+
+		if r, err = regexp.Compile(fmt.Sprintf("^(?:%s)$", s)); err != nil {
+			return o, errors.Wrapf(err, "invalid oidc subject: %s", s)
+		}
+
+		o.subjects = append(o.subjects, r)
+	}
+
+	for _, opt := range options {
+		opt(&o)
+	}
+
+	return o, nil
+}
+
+// OIDC a trusted issuer of workload identity tokens, e.g.) a CI system.
+type OIDC struct {
+	issuer   string
+	audience string
+	keys     jose.JSONWebKeySet
+	subjects []*regexp.Regexp
+	ttl      time.Duration
+	jwks     *jwksFile
+}
+
+func (t OIDC) keyset() jose.JSONWebKeySet {
+	if t.jwks == nil {
+		return t.keys
+	}
+
+	return t.jwks.current(t.keys)
+}
+
+// Verify the token was signed by the issuer, is currently valid, and its subject is allowed.
+func (t OIDC) Verify(tok *jwt.JSONWebToken, now time.Time) (claims jwt.Claims, err error) {
+	if err = tok.Claims(t.keyset(), &claims); err != nil {
+		return claims, errors.Wrap(err, "invalid oidc token signature")
+	}
+
+	expected := jwt.Expected{
+		Issuer:      t.issuer,
+		AnyAudience: jwt.Audience{t.audience},
+		Time:        now,
+	}
+
+	if err = claims.ValidateWithLeeway(expected, oidcLeeway); err != nil {
+		return claims, errors.Wrap(err, "invalid oidc token")
+	}
+
+	if claims.Expiry == nil {
+		return claims, errors.New("oidc token must expire")
+	}
+
+	for _, r := range t.subjects {
+		if r.MatchString(claims.Subject) {
+			return claims, nil
+		}
+	}
+
+	return claims, errors.Errorf("oidc subject is not allowed: %s", claims.Subject)
+}
+
+// oidcExchange verifies tokens against the matching trusted issuer.
+type oidcExchange []OIDC
+
+// verify the token, returning the claims and the duration of the grant.
+func (t oidcExchange) verify(encoded string, now time.Time) (claims jwt.Claims, ttl time.Duration, err error) {
+	var (
+		tok        *jwt.JSONWebToken
+		unverified jwt.Claims
+	)
+
+	if tok, err = jwt.ParseSigned(encoded, oidcAlgorithms); err != nil {
+		return claims, ttl, errors.Wrap(err, "invalid oidc token")
+	}
+
+	// only used to select the issuer, the claims are verified by the issuer.
+	if err = tok.UnsafeClaimsWithoutVerification(&unverified); err != nil {
+		return claims, ttl, errors.Wrap(err, "invalid oidc token")
+	}
+
+	err = errors.Errorf("untrusted oidc issuer: %s", unverified.Issuer)
+	for _, o := range t {
+		if o.issuer != unverified.Issuer {
+			continue
+		}
+
+		if claims, err = o.Verify(tok, now); err == nil {
+			return claims, o.ttl, nil
+		}
+	}
+
+	return claims, ttl, err
+}
+
+// exchange signatures are over a domain separated token so they can never
+// be mistaken for signatures of any other data.
+func genExchangeSignatureData(token string) []byte {
+	return []byte("bw.exchange:" + token)
+}
+
+// SignExchange signs the oidc token, proving possession of the key being exchanged for a grant.
+func (t Signer) SignExchange(token string) (s *Signature, err error) {
+	if s, err = genSignatureSHA256(t.signer, genExchangeSignatureData(token)); err != nil {
+		return nil, errors.Wrap(err, "failed to sign oidc token")
+	}
+
+	return s, nil
+}
+
+// verifyExchange ensures the token was signed by the key being exchanged for a grant.
+func verifyExchange(key ssh.PublicKey, token string, s *Signature) error {
+	if s == nil {
+		return errors.New("oidc token is not signed")
+	}
+
+	return errors.Wrap(key.Verify(genExchangeSignatureData(token), s.sig()), "invalid oidc token signature")
+}
+
+// AuditRedact the oidc token is a credential, only the requested authorization
+// is recorded into the audit log.
+func (t *ExchangeRequest) AuditRedact() proto.Message {
+	return &ExchangeRequest{Authorization: t.Authorization}
+}
@@ -0,0 +1,302 @@
+package notary_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/agent/discovery"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/muxer"
+	. "github.com/james-lawrence/bw/notary"
+)
+
+const (
+	testIssuer   = "https://ci.example.com"
+	testAudience = "bw"
+)
+
+// write the public keys as the issuer's jwks.
+func writeJWKS(path string, keys map[string]*ecdsa.PrivateKey) {
+	var set jose.JSONWebKeySet
+	for kid, pkey := range keys {
+		set.Keys = append(set.Keys, jose.JSONWebKey{Key: pkey.Public(), KeyID: kid, Algorithm: string(jose.ES256), Use: "sig"})
+	}
+
+	encoded, err := json.Marshal(set)
+	Expect(err).To(Succeed())
+	Expect(os.WriteFile(path, encoded, 0600)).To(Succeed())
+}
+
+// issue a signed oidc token with the given claims.
+func issue(pkey *ecdsa.PrivateKey, kid string, claims jwt.Claims) string {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.ES256, Key: pkey},
+		(&jose.SignerOptions{}).WithType("JWT").WithHeader(jose.HeaderKey("kid"), kid),
+	)
+	Expect(err).To(Succeed())
+
+	encoded, err := jwt.Signed(signer).Claims(claims).Serialize()
+	Expect(err).To(Succeed())
+
+	return encoded
+}
+
+func claims(subject string, ts time.Time) jwt.Claims {
+	return jwt.Claims{
+		Issuer:   testIssuer,
+		Audience: jwt.Audience{testAudience},
+		Subject:  subject,
+		IssuedAt: jwt.NewNumericDate(ts),
+		Expiry:   jwt.NewNumericDate(ts.Add(5 * time.Minute)),
+	}
+}
+
+// quickSigner generates a signer for a new key.
+func quickSigner() Signer {
+	skey, _ := QuickKey()
+	ss, err := NewSigner(skey)
+	Expect(err).To(Succeed())
+	return ss
+}
+
+var _ = Describe("OIDC", func() {
+	var (
+		pkey   *ecdsa.PrivateKey
+		path   string
+		client Client
+		rpc    NotaryClient
+		store  Directory
+		svc    Service
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		pkey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).To(Succeed())
+
+		// the issuer's jwks is loaded from a file in place of the issuer's discovery endpoint.
+		path = filepath.Join(testingx.TempDir(), "jwks.json")
+		writeJWKS(path, map[string]*ecdsa.PrivateKey{"k1": pkey})
+
+		keys, err := LoadJWKS(path)
+		Expect(err).To(Succeed())
+
+		issuer, err := NewOIDC(testIssuer, testAudience, keys, []string{"repo:example/app:ref:refs/heads/.*"}, OIDCOptionTTL(10*time.Minute), OIDCOptionJWKSFile(path))
+		Expect(err).To(Succeed())
+
+		store = NewDirectory(testingx.TempDir())
+		svc = New("", nil, store, OptionOIDC(issuer))
+		conn, server := testingx.NewGRPCServer(func(s *grpc.Server) {
+			svc.Bind(s)
+		})
+		DeferCleanup(testingx.GRPCCleanup, conn, server)
+
+		client = NewClient(NewStaticDialer(conn))
+		rpc = NewNotaryClient(conn)
+	})
+
+	It("should require subjects to be restricted", func() {
+		_, err := NewOIDC(testIssuer, testAudience, jose.JSONWebKeySet{}, nil)
+		Expect(err).To(MatchError(ContainSubstring("must restrict the allowed subjects")))
+	})
+
+	It("should exchange a valid token for a short lived deploy grant", func() {
+		ss := quickSigner()
+		now := time.Now()
+
+		g, err := client.Exchange(context.Background(), issue(pkey, "k1", claims("repo:example/app:ref:refs/heads/main", now)), ss)
+		Expect(err).To(Succeed())
+		Expect(g.Permission).To(Equal(&Permission{Deploy: true}))
+		Expect(g.Comment).To(Equal("oidc https://ci.example.com repo:example/app:ref:refs/heads/main"))
+		Expect(g.Expired(now)).To(BeFalse())
+		Expect(g.Expired(now.Add(11 * time.Minute))).To(BeTrue())
+
+		stored, err := store.Lookup(g.Fingerprint)
+		Expect(err).To(Succeed())
+		Expect(stored.NotAfter).To(Equal(g.NotAfter))
+	})
+
+	DescribeTable("should reject invalid tokens",
+		func(token func() string) {
+			ss := quickSigner()
+			_, err := client.Exchange(context.Background(), token(), ss)
+			Expect(err).To(MatchError(ContainSubstring("PermissionDenied")))
+		},
+		Entry("disallowed subject", func() string {
+			return issue(pkey, "k1", claims("repo:example/other:ref:refs/heads/main", time.Now()))
+		}),
+		Entry("expired", func() string {
+			return issue(pkey, "k1", claims("repo:example/app:ref:refs/heads/main", time.Now().Add(-time.Hour)))
+		}),
+		Entry("untrusted issuer", func() string {
+			c := claims("repo:example/app:ref:refs/heads/main", time.Now())
+			c.Issuer = "https://evil.example.com"
+			return issue(pkey, "k1", c)
+		}),
+		Entry("wrong audience", func() string {
+			c := claims("repo:example/app:ref:refs/heads/main", time.Now())
+			c.Audience = jwt.Audience{"other"}
+			return issue(pkey, "k1", c)
+		}),
+		Entry("unknown signing key", func() string {
+			other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).To(Succeed())
+			return issue(other, "k1", claims("repo:example/app:ref:refs/heads/main", time.Now()))
+		}),
+		Entry("malformed", func() string {
+			return "not.a.token"
+		}),
+	)
+
+	It("should require the token to be signed by the key being granted", func() {
+		ss, other := quickSigner(), quickSigner()
+		token := issue(pkey, "k1", claims("repo:example/app:ref:refs/heads/main", time.Now()))
+		_, pub, err := ss.AutoSignerInfo()
+		Expect(err).To(Succeed())
+
+		_, err = rpc.Exchange(context.Background(), &ExchangeRequest{Token: token, Authorization: pub})
+		Expect(err).To(MatchError(ContainSubstring("PermissionDenied")))
+
+		forged, err := other.SignExchange(token)
+		Expect(err).To(Succeed())
+		_, err = rpc.Exchange(context.Background(), &ExchangeRequest{Token: token, Authorization: pub, Signature: forged})
+		Expect(err).To(MatchError(ContainSubstring("PermissionDenied")))
+
+		signed, err := ss.SignExchange(token)
+		Expect(err).To(Succeed())
+		_, err = rpc.Exchange(context.Background(), &ExchangeRequest{Token: token, Authorization: pub, Signature: signed})
+		Expect(err).To(Succeed())
+	})
+
+	It("should only replace grants issued by an exchange", func() {
+		ss := quickSigner()
+		fp, pub, err := ss.AutoSignerInfo()
+		Expect(err).To(Succeed())
+
+		_, err = client.Exchange(context.Background(), issue(pkey, "k1", claims("repo:example/app:ref:refs/heads/main", time.Now())), ss)
+		Expect(err).To(Succeed())
+		g, err := client.Exchange(context.Background(), issue(pkey, "k1", claims("repo:example/app:ref:refs/heads/main", time.Now())), ss)
+		Expect(err).To(Succeed())
+		Expect(g.Exchanged).To(BeTrue())
+
+		_, err = store.Insert(NewGrant(UserFull(), pub))
+		Expect(err).To(Succeed())
+
+		_, err = client.Exchange(context.Background(), issue(pkey, "k1", claims("repo:example/app:ref:refs/heads/main", time.Now())), ss)
+		Expect(err).To(MatchError(ContainSubstring("AlreadyExists")))
+
+		stored, err := store.Lookup(fp)
+		Expect(err).To(Succeed())
+		Expect(stored.Permission).To(Equal(UserFull()))
+		Expect(stored.NotAfter).To(BeZero())
+	})
+
+	It("should reload the issuer's keys when the jwks changes", func() {
+		ss := quickSigner()
+		rotated, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).To(Succeed())
+		token := issue(rotated, "k2", claims("repo:example/app:ref:refs/heads/main", time.Now()))
+
+		_, err = client.Exchange(context.Background(), token, ss)
+		Expect(err).To(MatchError(ContainSubstring("PermissionDenied")))
+
+		writeJWKS(path, map[string]*ecdsa.PrivateKey{"k2": rotated})
+		future := time.Now().Add(time.Minute)
+		Expect(os.Chtimes(path, future, future)).To(Succeed())
+
+		_, err = client.Exchange(context.Background(), token, ss)
+		Expect(err).To(Succeed())
+	})
+
+	It("should allow keys without a grant to exchange tokens and then use the proxy", func() {
+		ctx, done := context.WithCancel(context.Background())
+		defer done()
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(Succeed())
+		defer l.Close()
+
+		m := muxer.New()
+		go muxer.Listen(ctx, m, l)
+
+		proxied, err := m.Bind(bw.ProtocolProxy, l.Addr())
+		Expect(err).To(Succeed())
+		// mirrors the agent's proxy, which only admits signers permitted to deploy.
+		go discovery.Proxy(proxied, &net.Dialer{}, NewAuthChecker(store, func(p *Permission) error {
+			if p.Deploy {
+				return nil
+			}
+
+			return errors.New("unauthorized")
+		}))
+
+		exchanged, err := m.Bind(bw.ProtocolExchange, l.Addr())
+		Expect(err).To(Succeed())
+		server := grpc.NewServer()
+		svc.BindExchange(server)
+		go server.Serve(exchanged)
+		defer server.Stop()
+
+		ss := quickSigner()
+
+		proxy := discovery.ProxyDialer{
+			Proxy:  l.Addr().String(),
+			Signer: ss,
+			Dialer: muxer.NewDialer(bw.ProtocolProxy, &net.Dialer{}),
+		}
+
+		_, err = proxy.DialContext(ctx, "tcp", l.Addr().String())
+		Expect(err).To(MatchError(ContainSubstring("proxy request failed")))
+
+		dd, err := dialers.DefaultDialer(l.Addr().String(), &net.Dialer{})
+		Expect(err).To(Succeed())
+		exchange := NewClient(dialers.NewDirect(agent.URIExchange(l.Addr().String()), dd.Defaults()...))
+
+		_, err = exchange.Revoke("SHA256:unknown")
+		Expect(err).To(MatchError(ContainSubstring("Unimplemented")))
+
+		_, err = exchange.Exchange(ctx, issue(pkey, "k1", claims("repo:example/app:ref:refs/heads/main", time.Now())), ss)
+		Expect(err).To(Succeed())
+
+		conn, err := proxy.DialContext(ctx, "tcp", l.Addr().String())
+		Expect(err).To(Succeed())
+		Expect(conn.Close()).To(Succeed())
+	})
+
+	It("should be unavailable when no issuers are configured", func() {
+		svc := New("", nil, NewDirectory(testingx.TempDir()))
+		conn, server := testingx.NewGRPCServer(func(s *grpc.Server) {
+			svc.Bind(s)
+		})
+		defer testingx.GRPCCleanup(conn, server)
+
+		ss := quickSigner()
+		_, err := NewClient(NewStaticDialer(conn)).Exchange(context.Background(), issue(pkey, "k1", claims("repo:example/app:ref:refs/heads/main", time.Now())), ss)
+		Expect(err).To(MatchError(ContainSubstring("Unimplemented")))
+	})
+
+	It("should not record the token into the audit log", func() {
+		req := &ExchangeRequest{Token: "secret", Authorization: []byte("ssh-ed25519 AAAA")}
+		redacted := req.AuditRedact().(*ExchangeRequest)
+		Expect(redacted.Token).To(BeEmpty())
+		Expect(redacted.Authorization).To(Equal(req.Authorization))
+	})
+})
@@ -0,0 +1,89 @@
+package notary
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// permission presets for common roles.
+const (
+	PresetAdmin    = "admin"
+	PresetDeployer = "deployer"
+	PresetReadOnly = "readonly"
+)
+
+// Presets returns the named permission presets.
+func Presets() map[string]*Permission {
+	return map[string]*Permission{
+		PresetAdmin: UserFull(),
+		PresetDeployer: {
+			Refresh: true,
+			Search:  true,
+			Deploy:  true,
+		},
+		PresetReadOnly: {
+			Refresh: true,
+			Search:  true,
+		},
+	}
+}
+
+// permission names mapped to their setters.
+var permissions = map[string]func(*Permission) *bool{
+	"refresh":  func(p *Permission) *bool { return &p.Refresh },
+	"search":   func(p *Permission) *bool { return &p.Search },
+	"grant":    func(p *Permission) *bool { return &p.Grant },
+	"revoke":   func(p *Permission) *bool { return &p.Revoke },
+	"deploy":   func(p *Permission) *bool { return &p.Deploy },
+	"autocert": func(p *Permission) *bool { return &p.Autocert },
+	"sync":     func(p *Permission) *bool { return &p.Sync },
+}
+
+// ParsePermissions builds the union of the named permissions and presets.
+// e.g.) ParsePermissions("readonly", "deploy")
+func ParsePermissions(names ...string) (p *Permission, err error) {
+	p = none()
+	presets := Presets()
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		if preset, ok := presets[name]; ok {
+			for _, n := range PermissionNames(preset) {
+				*permissions[n](p) = true
+			}
+			continue
+		}
+
+		set, ok := permissions[name]
+		if !ok {
+			return nil, errors.Errorf("unknown permission or preset: %s", name)
+		}
+
+		*set(p) = true
+	}
+
+	return p, nil
+}
+
+// PermissionNames returns the sorted names of the permissions that are granted.
+func PermissionNames(p *Permission) (names []string) {
+	if p == nil {
+		return names
+	}
+
+	for name, get := range permissions {
+		if *get(p) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,29 @@
+package notary_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw/notary"
+)
+
+var _ = Describe("ParsePermissions", func() {
+	DescribeTable("should resolve permissions and presets",
+		func(expected []string, names ...string) {
+			p, err := ParsePermissions(names...)
+			Expect(err).To(Succeed())
+			Expect(PermissionNames(p)).To(Equal(expected))
+		},
+		Entry("no permissions", []string(nil)),
+		Entry("individual permissions", []string{"deploy", "search"}, "deploy", "search"),
+		Entry("readonly preset", []string{"refresh", "search"}, PresetReadOnly),
+		Entry("deployer preset", []string{"deploy", "refresh", "search"}, PresetDeployer),
+		Entry("admin preset", []string{"deploy", "grant", "refresh", "revoke", "search", "sync"}, PresetAdmin),
+		Entry("union of presets and permissions", []string{"refresh", "revoke", "search"}, " ReadOnly ", "revoke"),
+	)
+
+	It("should reject unknown permissions", func() {
+		_, err := ParsePermissions("deploy", "superuser")
+		Expect(err).To(MatchError("unknown permission or preset: superuser"))
+	})
+})
@@ -63,6 +63,17 @@ func (t Proxy) Revoke(ctx context.Context, req *RevokeRequest) (resp *RevokeResp
 	return c.Revoke(t.metadata(ctx), req)
 }
 
+// Exchange an oidc token for a short lived grant.
+func (t Proxy) Exchange(ctx context.Context, req *ExchangeRequest) (resp *ExchangeResponse, err error) {
+	var (
+		c NotaryClient
+	)
+	if c, err = t.cached(); err != nil {
+		return nil, err
+	}
+	return c.Exchange(t.metadata(ctx), req)
+}
+
 // Refresh generate new TLS credentials
 func (t Proxy) Refresh(ctx context.Context, req *RefreshRequest) (resp *RefreshResponse, err error) {
 	var (
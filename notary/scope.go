@@ -0,0 +1,128 @@
+package notary
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// actions that can be allowed by a scope.
+const (
+	ActionRestart = "restart"
+	ActionProfile = "profile"
+)
+
+// ScopedPeer the peer information a scope is matched against.
+// an alias so that any peer implementation with the matching methods
+// can be provided without depending on this package.
+type ScopedPeer = interface {
+	GetName() string
+	GetIp() string
+	GetLabels() map[string]string
+}
+
+// ParseScope builds a scope from label selectors, peer filters, and actions.
+// returns nil when no restrictions are provided.
+func ParseScope(labels, filters, actions []string) (_ *Scope, err error) {
+	s := &Scope{}
+
+	for _, l := range labels {
+		if l = strings.TrimSpace(l); l == "" {
+			continue
+		}
+
+		if k, _, ok := strings.Cut(l, "="); !ok || strings.TrimSpace(k) == "" {
+			return nil, errors.Errorf("invalid label selector, expected key=value: %s", l)
+		}
+
+		s.Labels = append(s.Labels, l)
+	}
+
+	for _, f := range filters {
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+
+		if _, err = compileFilter(f); err != nil {
+			return nil, errors.Wrapf(err, "invalid filter: %s", f)
+		}
+
+		s.Filters = append(s.Filters, f)
+	}
+
+	for _, a := range actions {
+		switch a = strings.ToLower(strings.TrimSpace(a)); a {
+		case "":
+			continue
+		case ActionRestart, ActionProfile:
+			s.Actions = append(s.Actions, a)
+		default:
+			return nil, errors.Errorf("unknown action: %s", a)
+		}
+	}
+
+	if len(s.Labels) == 0 && len(s.Filters) == 0 && len(s.Actions) == 0 {
+		return nil, nil
+	}
+
+	sort.Strings(s.Actions)
+
+	return s, nil
+}
+
+// Match determines if the peer is within the scope.
+// a nil scope matches every peer.
+func (t *Scope) Match(p ScopedPeer) bool {
+	if t == nil {
+		return true
+	}
+
+	labels := p.GetLabels()
+	for _, selector := range t.Labels {
+		k, v, _ := strings.Cut(selector, "=")
+		if actual, ok := labels[strings.TrimSpace(k)]; !ok || actual != strings.TrimSpace(v) {
+			return false
+		}
+	}
+
+	if len(t.Filters) == 0 {
+		return true
+	}
+
+	for _, f := range t.Filters {
+		r, err := compileFilter(f)
+		if err != nil {
+			continue
+		}
+
+		if r.MatchString(p.GetName()) || r.MatchString(p.GetIp()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compileFilter filters must match the entire name or ip, otherwise a filter
+// like prod would also match preprod.
+func compileFilter(f string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + f + ")$")
+}
+
+// Allows determines if the action is permitted by the scope.
+// a nil scope allows every action.
+func (t *Scope) Allows(action string) bool {
+	if t == nil {
+		return true
+	}
+
+	for _, a := range t.Actions {
+		if a == action {
+			return true
+		}
+	}
+
+	return false
+}
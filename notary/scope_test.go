@@ -0,0 +1,109 @@
+package notary_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/james-lawrence/bw/agent"
+	. "github.com/james-lawrence/bw/notary"
+)
+
+func scoped(labels, filters, actions []string) *Permission {
+	s, err := ParseScope(labels, filters, actions)
+	Expect(err).To(Succeed())
+	p := all()
+	p.Scope = s
+	return p
+}
+
+var _ = Describe("Scope", func() {
+	staging := agent.NewPeer("staging-1", agent.PeerOptionIP([]byte{10, 0, 0, 1}), agent.PeerOptionLabels(map[string]string{"environment": "staging", "zone": "a"}))
+	production := agent.NewPeer("production-1", agent.PeerOptionIP([]byte{10, 0, 1, 1}), agent.PeerOptionLabels(map[string]string{"environment": "production", "zone": "a"}))
+
+	It("should return nil when unrestricted", func() {
+		s, err := ParseScope(nil, []string{" "}, nil)
+		Expect(err).To(Succeed())
+		Expect(s).To(BeNil())
+		Expect(s.Match(production)).To(BeTrue())
+		Expect(s.Allows(ActionRestart)).To(BeTrue())
+	})
+
+	DescribeTable("should reject invalid scopes",
+		func(labels, filters, actions []string) {
+			_, err := ParseScope(labels, filters, actions)
+			Expect(err).ToNot(Succeed())
+		},
+		Entry("label without a value", []string{"environment"}, nil, nil),
+		Entry("label without a key", []string{"=staging"}, nil, nil),
+		Entry("invalid filter", nil, []string{"("}, nil),
+		Entry("unknown action", nil, nil, []string{"shutdown"}),
+	)
+
+	DescribeTable("Match",
+		func(labels, filters []string, expected bool) {
+			s, err := ParseScope(labels, filters, nil)
+			Expect(err).To(Succeed())
+			Expect(s.Match(staging)).To(Equal(expected))
+		},
+		Entry("matching label", []string{"environment=staging"}, nil, true),
+		Entry("every label must match", []string{"environment=staging", "zone=b"}, nil, false),
+		Entry("missing label", []string{"team=payments"}, nil, false),
+		Entry("matching name filter", nil, []string{"staging-.*"}, true),
+		Entry("matching ip filter", nil, []string{`10\.0\.0\..*`}, true),
+		Entry("any filter may match", nil, []string{"production-.*", "staging-.*"}, true),
+		Entry("labels and filters must match", []string{"environment=staging"}, []string{"production-.*"}, false),
+		Entry("filters match the entire name", nil, []string{"staging"}, false),
+		Entry("filters are anchored", nil, []string{"taging-.*|nothing"}, false),
+	)
+
+	It("should only allow the listed actions", func() {
+		s, err := ParseScope([]string{"environment=staging"}, nil, []string{"Profile"})
+		Expect(err).To(Succeed())
+		Expect(s.Allows(ActionProfile)).To(BeTrue())
+		Expect(s.Allows(ActionRestart)).To(BeFalse())
+	})
+})
+
+var _ = Describe("AgentAuth", func() {
+	staging := agent.NewPeer("staging-1", agent.PeerOptionLabels(map[string]string{"environment": "staging"}))
+	production := agent.NewPeer("production-1", agent.PeerOptionLabels(map[string]string{"environment": "production"}))
+	contractor := staticauth{Permission: scoped([]string{"environment=staging"}, nil, []string{ActionRestart})}
+
+	denied := func(err error) {
+		Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	}
+
+	It("should allow unscoped grants everywhere", func() {
+		a := NewAgentAuth(staticauth{Permission: all()}, AgentAuthOptionLocal(production))
+		Expect(a.Deploy(context.Background())).To(Succeed())
+		Expect(a.Restart(context.Background())).To(Succeed())
+		Expect(a.Profile(context.Background())).To(Succeed())
+		Expect(a.DeployTo(context.Background(), staging, production)).To(Succeed())
+	})
+
+	It("should restrict scoped grants to matching peers", func() {
+		Expect(NewAgentAuth(contractor, AgentAuthOptionLocal(staging)).Deploy(context.Background())).To(Succeed())
+		denied(NewAgentAuth(contractor, AgentAuthOptionLocal(production)).Deploy(context.Background()))
+	})
+
+	It("should restrict scoped grants to the allowed actions", func() {
+		a := NewAgentAuth(contractor, AgentAuthOptionLocal(staging))
+		Expect(a.Restart(context.Background())).To(Succeed())
+		denied(a.Profile(context.Background()))
+		denied(NewAgentAuth(contractor, AgentAuthOptionLocal(production)).Restart(context.Background()))
+	})
+
+	It("should reject deploys that include peers outside the scope", func() {
+		a := NewAgentAuth(contractor)
+		Expect(a.DeployTo(context.Background(), staging)).To(Succeed())
+		denied(a.DeployTo(context.Background(), staging, production))
+	})
+
+	It("should require the deploy permission", func() {
+		denied(NewAgentAuth(staticauth{Permission: &Permission{Search: true}}).DeployTo(context.Background(), staging))
+	})
+})
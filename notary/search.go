@@ -0,0 +1,99 @@
+package notary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// query term prefixes.
+const (
+	termFingerprint = "fingerprint:"
+	termPermission  = "permission:"
+)
+
+// Query parsed representation of a search request's query.
+// every term must match for a grant to match.
+type Query struct {
+	fingerprints []string
+	permissions  []string
+	text         []string
+}
+
+// ParseQuery parses whitespace separated query terms.
+//
+//	fingerprint:<prefix> - fingerprint starts with the prefix.
+//	permission:<name>    - grant has the permission.
+//	<text>               - comment contains the text (case insensitive) or fingerprint starts with it.
+func ParseQuery(s string) (q Query, err error) {
+	for _, term := range strings.Fields(s) {
+		switch {
+		case strings.HasPrefix(term, termFingerprint):
+			q.fingerprints = append(q.fingerprints, strings.TrimPrefix(term, termFingerprint))
+		case strings.HasPrefix(term, termPermission):
+			name := strings.ToLower(strings.TrimPrefix(term, termPermission))
+			if _, ok := permissions[name]; !ok {
+				return q, errors.Errorf("unknown permission: %s", name)
+			}
+			q.permissions = append(q.permissions, name)
+		default:
+			q.text = append(q.text, term)
+		}
+	}
+
+	return q, nil
+}
+
+// Match determines if the grant satisfies the query.
+func (t Query) Match(g *Grant) bool {
+	for _, prefix := range t.fingerprints {
+		if !strings.HasPrefix(g.Fingerprint, prefix) {
+			return false
+		}
+	}
+
+	for _, name := range t.permissions {
+		if p := g.GetPermission(); p == nil || !*permissions[name](p) {
+			return false
+		}
+	}
+
+	comment := strings.ToLower(g.Comment)
+	for _, text := range t.text {
+		if !strings.HasPrefix(g.Fingerprint, text) && !strings.Contains(comment, strings.ToLower(text)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// page the matching grants, grants are ordered by fingerprint and resume after the cursor.
+// next is the cursor for the following page, empty when there are no more results.
+func page(grants []*Grant, cursor string, limit int) (_ []*Grant, next string) {
+	sort.Slice(grants, func(i, j int) bool {
+		return grants[i].Fingerprint < grants[j].Fingerprint
+	})
+
+	offset := sort.Search(len(grants), func(i int) bool {
+		return grants[i].Fingerprint > cursor
+	})
+	grants = grants[offset:]
+
+	if limit <= 0 || len(grants) <= limit {
+		return grants, ""
+	}
+
+	return grants[:limit], grants[limit-1].Fingerprint
+}
+
+// describe the storage a grant originated from.
+func describe(s storage) string {
+	if n, ok := s.(fmt.Stringer); ok {
+		return n.String()
+	}
+
+	return fmt.Sprintf("%T", s)
+}
@@ -0,0 +1,133 @@
+package notary_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	. "github.com/james-lawrence/bw/notary"
+)
+
+// commented generate a public key with the given comment.
+func commented(comment string) []byte {
+	_, pubkey := QuickKey()
+	return append(append(bytes.TrimSpace(pubkey), ' '), []byte(comment+"\n")...)
+}
+
+// collect every grant and the final cursor from a search.
+func collect(c Client, req *SearchRequest) (grants []*Grant, next string) {
+	s, err := c.Search(context.Background(), req)
+	Expect(err).To(Succeed())
+
+	for {
+		page, err := s.Recv()
+		if err == io.EOF {
+			return grants, next
+		}
+		Expect(err).To(Succeed())
+
+		grants = append(grants, page.Grants...)
+		for _, g := range page.Grants {
+			Expect(page.Sources).To(HaveKey(g.Fingerprint))
+		}
+
+		if page.Next != "" {
+			next = page.Next
+		}
+	}
+}
+
+var _ = Describe("Search", func() {
+	DescribeTable("Query",
+		func(query string, g *Grant, matched bool) {
+			q, err := ParseQuery(query)
+			Expect(err).To(Succeed())
+			Expect(q.Match(g)).To(Equal(matched))
+		},
+		Entry("empty", "", NewGrant(UserFull(), commented("alice")), true),
+		Entry("comment", "ALI", NewGrant(UserFull(), commented("alice@example.com")), true),
+		Entry("comment mismatch", "bob", NewGrant(UserFull(), commented("alice")), false),
+		Entry("fingerprint mismatch", "fingerprint:zz", NewGrant(UserFull(), commented("alice")), false),
+		Entry("permission", "permission:deploy", NewGrant(&Permission{Deploy: true}, commented("alice")), true),
+		Entry("permission mismatch", "permission:grant", NewGrant(&Permission{Deploy: true}, commented("alice")), false),
+		Entry("every term must match", "alice permission:grant", NewGrant(&Permission{Deploy: true}, commented("alice")), false),
+	)
+
+	It("should match fingerprint prefixes", func() {
+		g := NewGrant(UserFull(), commented("alice"))
+
+		for _, query := range []string{"fingerprint:" + g.Fingerprint[:8], g.Fingerprint[:8]} {
+			q, err := ParseQuery(query)
+			Expect(err).To(Succeed())
+			Expect(q.Match(g)).To(BeTrue())
+		}
+	})
+
+	It("should retain comments from authorized keys files", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "authorized_keys")
+		Expect(os.WriteFile(path, append(commented("alice"), commented("bob")...), 0600)).To(Succeed())
+
+		m := NewMem()
+		Expect(LoadAuthorizedKeys(m, path)).To(Succeed())
+
+		comments := []string{}
+		for _, g := range m.UnsafeSnapshot() {
+			comments = append(comments, g.Comment)
+		}
+		Expect(comments).To(ConsistOf("alice", "bob"))
+	})
+
+	It("should reject unknown permissions", func() {
+		_, err := ParseQuery("permission:unknown")
+		Expect(err).To(MatchError("unknown permission: unknown"))
+	})
+
+	It("should filter grants by the query", func() {
+		c, cleanup := QuickService()
+		defer cleanup()
+
+		alice, err := c.Grant(NewGrant(&Permission{Deploy: true}, commented("alice")))
+		Expect(err).To(Succeed())
+		_, err = c.Grant(NewGrant(&Permission{Search: true}, commented("bob")))
+		Expect(err).To(Succeed())
+
+		grants, next := collect(c, &SearchRequest{Query: "alice permission:deploy"})
+		Expect(next).To(BeEmpty())
+		Expect(grants).To(HaveLen(1))
+		Expect(grants[0].Fingerprint).To(Equal(alice.Fingerprint))
+	})
+
+	It("should page through the grants", func() {
+		c, cleanup := QuickService()
+		defer cleanup()
+
+		for i := 0; i < 4; i++ {
+			_, err := c.Grant(NewGrant(&Permission{Deploy: true}, commented("paged")))
+			Expect(err).To(Succeed())
+		}
+
+		first, next := collect(c, &SearchRequest{Query: "paged", Limit: 3})
+		Expect(first).To(HaveLen(3))
+		Expect(next).To(Equal(first[2].Fingerprint))
+
+		second, next := collect(c, &SearchRequest{Query: "paged", Limit: 3, Cursor: next})
+		Expect(second).To(HaveLen(1))
+		Expect(next).To(BeEmpty())
+		Expect(second[0].Fingerprint > first[2].Fingerprint).To(BeTrue())
+	})
+
+	It("should reject invalid queries", func() {
+		c, cleanup := QuickService()
+		defer cleanup()
+
+		s, err := c.Search(context.Background(), &SearchRequest{Query: "permission:unknown"})
+		Expect(err).To(Succeed())
+		_, err = s.Recv()
+		Expect(err).To(MatchError(ContainSubstring("InvalidArgument")))
+	})
+})
@@ -42,6 +42,29 @@ func genSignature(k ssh.Signer, b []byte) (s *Signature, err error) {
 	return &Signature{
 		Format: ss.Format,
 		Data:   ss.Blob,
+		Rest:   ss.Rest,
+	}, nil
+}
+
+// genSignatureSHA256 prefers sha256 over the legacy sha1 signatures of rsa keys.
+func genSignatureSHA256(k ssh.Signer, b []byte) (s *Signature, err error) {
+	var (
+		ss *ssh.Signature
+	)
+
+	as, ok := k.(ssh.AlgorithmSigner)
+	if !ok || k.PublicKey().Type() != ssh.KeyAlgoRSA {
+		return genSignature(k, b)
+	}
+
+	if ss, err = as.SignWithAlgorithm(rand.Reader, b, ssh.KeyAlgoRSASHA256); err != nil {
+		return s, errors.Wrap(err, "failed to generate signature")
+	}
+
+	return &Signature{
+		Format: ss.Format,
+		Data:   ss.Blob,
+		Rest:   ss.Rest,
 	}, nil
 }
 
@@ -62,5 +85,6 @@ func (t *Signature) sig() *ssh.Signature {
 	return &ssh.Signature{
 		Format: t.Format,
 		Blob:   t.Data,
+		Rest:   t.Rest,
 	}
 }
@@ -0,0 +1,170 @@
+package notary
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	sshagent "golang.org/x/crypto/ssh/agent"
+
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/james-lawrence/bw/internal/sshx"
+)
+
+// EnvSSHAuthSock environment variable holding the path to the ssh-agent socket.
+const EnvSSHAuthSock = "SSH_AUTH_SOCK"
+
+// DialSSHAgent connect to the ssh-agent specified by SSH_AUTH_SOCK.
+func DialSSHAgent() (_ sshagent.ExtendedAgent, err error) {
+	var (
+		conn net.Conn
+		sock = os.Getenv(EnvSSHAuthSock)
+	)
+
+	if strings.TrimSpace(sock) == "" {
+		return nil, errors.Errorf("ssh-agent unavailable, %s is not set", EnvSSHAuthSock)
+	}
+
+	if conn, err = net.Dial("unix", sock); err != nil {
+		return nil, errors.Wrapf(err, "unable to connect to ssh-agent: %s", sock)
+	}
+
+	return sshagent.NewClient(conn), nil
+}
+
+// SSHAgentKey selects the key from the agent matching the selector.
+// the selector matches the key's comment or its fingerprint (SHA256:...).
+// an empty selector is only allowed when the agent holds a single key.
+func SSHAgentKey(a sshagent.Agent, selector string) (_ *sshagent.Key, err error) {
+	var (
+		keys    []*sshagent.Key
+		matched []*sshagent.Key
+	)
+
+	if keys, err = a.List(); err != nil {
+		return nil, errors.Wrap(err, "unable to list ssh-agent keys")
+	}
+
+	for _, k := range keys {
+		if selector == "" || k.Comment == selector || ssh.FingerprintSHA256(k) == selector {
+			matched = append(matched, k)
+		}
+	}
+
+	switch len(matched) {
+	case 1:
+		return matched[0], nil
+	case 0:
+		return nil, errors.Errorf("no ssh-agent key matches '%s'", selector)
+	default:
+		desc := make([]string, 0, len(matched))
+		for _, k := range matched {
+			desc = append(desc, ssh.FingerprintSHA256(k)+" "+k.Comment)
+		}
+
+		return nil, errors.Errorf("multiple ssh-agent keys match '%s', select one by comment or fingerprint:\n%s", selector, strings.Join(desc, "\n"))
+	}
+}
+
+// NewSSHAgentSigner a request signer backed by a key held within the ssh-agent.
+// the private key never leaves the agent, which allows for hardware backed (sk-*) keys.
+// keys requiring user presence will prompt for every signed request.
+func NewSSHAgentSigner(a sshagent.Agent, pub ssh.PublicKey) (s Signer, err error) {
+	var (
+		signers []ssh.Signer
+	)
+
+	if signers, err = a.Signers(); err != nil {
+		return s, errors.Wrap(err, "unable to retrieve ssh-agent signers")
+	}
+
+	for _, ss := range signers {
+		if !bytes.Equal(ss.PublicKey().Marshal(), pub.Marshal()) {
+			continue
+		}
+
+		return Signer{
+			fingerprint: sshx.FingerprintSHA256(ssh.MarshalAuthorizedKey(pub)),
+			signer:      ss,
+		}, nil
+	}
+
+	return s, errors.Errorf("ssh-agent does not hold the key %s", ssh.FingerprintSHA256(pub))
+}
+
+// newSSHAgentSignerPath a signer for the public key stored at the path, using the ssh-agent.
+func newSSHAgentSignerPath(path string) (s Signer, err error) {
+	var (
+		a       sshagent.ExtendedAgent
+		pub     ssh.PublicKey
+		encoded []byte
+	)
+
+	if encoded, err = os.ReadFile(path); err != nil {
+		return s, errors.Wrapf(err, "unable to read public key: %s", path)
+	}
+
+	if pub, _, _, _, err = ssh.ParseAuthorizedKey(encoded); err != nil {
+		return s, errors.Wrapf(err, "invalid public key: %s", path)
+	}
+
+	if a, err = DialSSHAgent(); err != nil {
+		return s, err
+	}
+
+	return NewSSHAgentSigner(a, pub)
+}
+
+// the presence of the marker opts the credentials into using the ssh-agent, it holds the agent's public key.
+func sshAgentMarker(location string) string {
+	return location + ".ssh-agent"
+}
+
+// ClearSSHAgentSigner stops the user's credentials from using the ssh-agent.
+func ClearSSHAgentSigner() error {
+	return errorsx.Ignore(os.Remove(sshAgentMarker(PrivateKeyPath())), os.ErrNotExist)
+}
+
+// InitSSHAgentSigner configures the user's credentials to use the ssh-agent key.
+// only the public key is written to disk, any previously generated private key is removed.
+func InitSSHAgentSigner(a sshagent.Agent, k *sshagent.Key) (s Signer, err error) {
+	return initSSHAgentSignerPath(PrivateKeyPath(), a, k)
+}
+
+func initSSHAgentSignerPath(location string, a sshagent.Agent, k *sshagent.Key) (s Signer, err error) {
+	var (
+		pub ssh.PublicKey
+	)
+
+	if pub, err = ssh.ParsePublicKey(k.Marshal()); err != nil {
+		return s, errors.Wrap(err, "invalid ssh-agent key")
+	}
+
+	if s, err = NewSSHAgentSigner(a, pub); err != nil {
+		return s, err
+	}
+
+	if err = os.Remove(location); err != nil && !os.IsNotExist(err) {
+		return s, errors.Wrap(err, "unable to remove generated authorization key")
+	}
+
+	if err = os.MkdirAll(filepath.Dir(location), 0700); err != nil {
+		return s, errors.Wrap(err, "failed to create credential directory")
+	}
+
+	encoded := sshx.Comment(ssh.MarshalAuthorizedKey(pub), k.Comment)
+
+	if err = os.WriteFile(location+".pub", encoded, 0600); err != nil {
+		return s, errors.Wrap(err, "failed to write public key")
+	}
+
+	if err = os.WriteFile(sshAgentMarker(location), encoded, 0600); err != nil {
+		return s, errors.Wrap(err, "failed to write ssh-agent marker")
+	}
+
+	return s, nil
+}
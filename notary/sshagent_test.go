@@ -0,0 +1,188 @@
+package notary
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+	sshagent "golang.org/x/crypto/ssh/agent"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/internal/rsax"
+	"github.com/james-lawrence/bw/internal/testingx"
+)
+
+// serve the keyring over a unix socket and point SSH_AUTH_SOCK at it.
+func serveSSHAgent(keyring sshagent.Agent) {
+	dir, err := os.MkdirTemp("", "sshagent")
+	Expect(err).To(Succeed())
+	DeferCleanup(os.RemoveAll, dir)
+
+	l, err := net.Listen("unix", filepath.Join(dir, "agent.sock"))
+	Expect(err).To(Succeed())
+	DeferCleanup(l.Close)
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go sshagent.ServeAgent(keyring, conn)
+		}
+	}()
+
+	DeferCleanup(os.Setenv, EnvSSHAuthSock, os.Getenv(EnvSSHAuthSock))
+	Expect(os.Setenv(EnvSSHAuthSock, l.Addr().String())).To(Succeed())
+}
+
+func ed25519Key() crypto.PrivateKey {
+	_, pkey, err := ed25519.GenerateKey(rand.Reader)
+	Expect(err).To(Succeed())
+	return pkey
+}
+
+func ecdsaKey() crypto.PrivateKey {
+	pkey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).To(Succeed())
+	return pkey
+}
+
+// skSigner emulates a security key (sk-ssh-ed25519@openssh.com) as held by the ssh-agent.
+type skSigner struct {
+	pkey ed25519.PrivateKey
+}
+
+func (t skSigner) PublicKey() ssh.PublicKey {
+	pub, err := ssh.ParsePublicKey(ssh.Marshal(struct {
+		Name        string
+		KeyBytes    []byte
+		Application string
+	}{ssh.KeyAlgoSKED25519, t.pkey.Public().(ed25519.PublicKey), "ssh:"}))
+	Expect(err).To(Succeed())
+	return pub
+}
+
+func (t skSigner) Sign(_ io.Reader, data []byte) (*ssh.Signature, error) {
+	const (
+		flags   = byte(0x01) // user presence.
+		counter = uint32(7)
+	)
+
+	app := sha256.Sum256([]byte("ssh:"))
+	digest := sha256.Sum256(data)
+	signed := ssh.Marshal(struct {
+		ApplicationDigest []byte `ssh:"rest"`
+		Flags             byte
+		Counter           uint32
+		MessageDigest     []byte `ssh:"rest"`
+	}{app[:], flags, counter, digest[:]})
+
+	return &ssh.Signature{
+		Format: ssh.KeyAlgoSKED25519,
+		Blob:   ed25519.Sign(t.pkey, signed),
+		Rest: ssh.Marshal(struct {
+			Flags   byte
+			Counter uint32
+		}{flags, counter}),
+	}, nil
+}
+
+var _ = Describe("ssh-agent signer", func() {
+	It("should authenticate with security keys", func() {
+		sk := skSigner{pkey: ed25519Key().(ed25519.PrivateKey)}
+		pub := ssh.MarshalAuthorizedKey(sk.PublicKey())
+		g := NewGrant(UserFull(), pub)
+		ss := Signer{fingerprint: g.Fingerprint, signer: sk}
+
+		encoded, err := ss.Token()
+		Expect(err).To(Succeed())
+		Expect(decode(NewMem(g), encoded)).To(Equal(UserFull()))
+	})
+
+	DescribeTable("should authenticate with keys held by the agent",
+		func(pkey crypto.PrivateKey) {
+			keyring := sshagent.NewKeyring()
+			Expect(keyring.Add(sshagent.AddedKey{PrivateKey: pkey, Comment: "alice"})).To(Succeed())
+			serveSSHAgent(keyring)
+
+			location := filepath.Join(testingx.TempDir(), bw.DefaultNotaryKey)
+			Expect(os.WriteFile(location, []byte("stale"), 0600)).To(Succeed())
+
+			k, err := SSHAgentKey(keyring, "")
+			Expect(err).To(Succeed())
+			_, err = initSSHAgentSignerPath(location, keyring, k)
+			Expect(err).To(Succeed())
+			Expect(location).ToNot(BeAnExistingFile())
+
+			ss, err := newAutoSignerPath(location, "", func() ([]byte, error) {
+				Fail("key generation should not occur")
+				return nil, nil
+			})
+			Expect(err).To(Succeed())
+
+			fp, pub, err := ss.AutoSignerInfo()
+			Expect(err).To(Succeed())
+			g := NewGrant(UserFull(), pub)
+			Expect(fp).To(Equal(g.Fingerprint))
+
+			encoded, err := ss.Token()
+			Expect(err).To(Succeed())
+			Expect(decode(NewMem(g), encoded)).To(Equal(UserFull()))
+		},
+		Entry("ed25519", ed25519Key()),
+		Entry("ecdsa", ecdsaKey()),
+	)
+
+	It("should only use the ssh-agent when explicitly initialized", func() {
+		location := filepath.Join(testingx.TempDir(), bw.DefaultNotaryKey)
+		pub, err := ssh.NewPublicKey(ed25519Key().(ed25519.PrivateKey).Public())
+		Expect(err).To(Succeed())
+		Expect(os.WriteFile(location+".pub", ssh.MarshalAuthorizedKey(pub), 0600)).To(Succeed())
+
+		generated := false
+		_, err = newAutoSignerPath(location, "", func() ([]byte, error) {
+			generated = true
+			return rsax.Auto()
+		})
+		Expect(err).To(Succeed())
+		Expect(generated).To(BeTrue())
+		Expect(location).To(BeAnExistingFile())
+	})
+
+	It("should require a selector when the agent holds multiple keys", func() {
+		keyring := sshagent.NewKeyring()
+		Expect(keyring.Add(sshagent.AddedKey{PrivateKey: ed25519Key(), Comment: "alice"})).To(Succeed())
+		Expect(keyring.Add(sshagent.AddedKey{PrivateKey: ecdsaKey(), Comment: "bob"})).To(Succeed())
+
+		_, err := SSHAgentKey(keyring, "")
+		Expect(err).To(MatchError(ContainSubstring("multiple ssh-agent keys match")))
+
+		k, err := SSHAgentKey(keyring, "bob")
+		Expect(err).To(Succeed())
+		Expect(k.Comment).To(Equal("bob"))
+
+		k, err = SSHAgentKey(keyring, ssh.FingerprintSHA256(k))
+		Expect(err).To(Succeed())
+		Expect(k.Comment).To(Equal("bob"))
+	})
+
+	It("should fail when the agent does not hold the key", func() {
+		pub, err := ssh.NewPublicKey(ed25519Key().(ed25519.PrivateKey).Public())
+		Expect(err).To(Succeed())
+
+		_, err = NewSSHAgentSigner(sshagent.NewKeyring(), pub)
+		Expect(err).To(MatchError(ContainSubstring("ssh-agent does not hold the key")))
+	})
+})
@@ -132,10 +132,11 @@ func LoadAuthorizedKeys(s storage, path string) (err error) {
 
 	for i = 0; len(encoded) != 0; i++ {
 		var (
-			key ssh.PublicKey
+			key     ssh.PublicKey
+			comment string
 		)
 
-		if key, _, _, encoded, err = ssh.ParseAuthorizedKey(encoded); err != nil {
+		if key, comment, _, encoded, err = ssh.ParseAuthorizedKey(encoded); err != nil {
 			if sshx.IsNoKeyFound(err) {
 				continue
 			}
@@ -146,6 +147,7 @@ func LoadAuthorizedKeys(s storage, path string) (err error) {
 		g := (&Grant{
 			Permission:    UserFull(),
 			Authorization: ssh.MarshalAuthorizedKey(key),
+			Comment:       comment,
 		}).EnsureDefaults()
 
 		if _, err = s.Insert(g); err != nil {
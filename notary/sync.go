@@ -3,6 +3,7 @@ package notary
 import (
 	"bytes"
 	"log"
+	"time"
 
 	"github.com/bits-and-blooms/bloom/v3"
 	"github.com/james-lawrence/bw/internal/iox"
@@ -25,8 +26,15 @@ func NewSyncRequest(b *bloom.BloomFilter) (r *SyncRequest, err error) {
 	return &SyncRequest{Bloom: buf.Bytes()}, nil
 }
 
-// Sync from a client connection
-func Sync(stream Sync_StreamClient, b Bloomy, s storage) (err error) {
+// RetentionCutoff grants that expired before the cut-off are beyond the retention period,
+// garbage collection removes them and synchronization never propagates them.
+func RetentionCutoff(now time.Time, retention time.Duration) time.Time {
+	return now.Add(-retention)
+}
+
+// Sync from a client connection, expired grants are retained for the retention period
+// so archives they signed remain deployable on every agent.
+func Sync(stream Sync_StreamClient, b Bloomy, s storage, retention time.Duration) (err error) {
 	for {
 		var (
 			event *SyncStream
@@ -40,12 +48,17 @@ func Sync(stream Sync_StreamClient, b Bloomy, s storage) (err error) {
 		switch evt := event.Events.(type) {
 		case *SyncStream_Chunk:
 			for _, g := range evt.Chunk.Grants {
+				if g.Expired(RetentionCutoff(time.Now(), retention)) {
+					log.Println("ignoring expired grant", g.Fingerprint)
+					continue
+				}
+
 				log.Println("retrieved", g.Fingerprint)
 				if _, err := s.Insert(g); err != nil {
 					return err
 				}
 
-				b.Add([]byte(g.Fingerprint))
+				b.Add(g.syncKey())
 			}
 		}
 	}
@@ -53,18 +66,35 @@ func Sync(stream Sync_StreamClient, b Bloomy, s storage) (err error) {
 	return err
 }
 
+// SyncServiceOption options for the sync service.
+type SyncServiceOption func(*SyncService)
+
+// SyncServiceOptionRetention propagate expired grants for the retention period.
+func SyncServiceOptionRetention(d time.Duration) SyncServiceOption {
+	return func(s *SyncService) {
+		s.retention = d
+	}
+}
+
 // NewSyncService ...
-func NewSyncService(a auth, s SyncStorage) *SyncService {
-	return &SyncService{
+func NewSyncService(a auth, s SyncStorage, options ...SyncServiceOption) *SyncService {
+	svc := &SyncService{
 		auth:        a,
 		SyncStorage: s,
 	}
+
+	for _, opt := range options {
+		opt(svc)
+	}
+
+	return svc
 }
 
 type SyncService struct {
 	UnimplementedSyncServer
 	auth
 	SyncStorage
+	retention time.Duration
 }
 
 // Bind the service to the given grpc server.
@@ -113,6 +143,11 @@ func (t SyncService) Stream(r *SyncRequest, s Sync_StreamServer) (err error) {
 				return nil
 			}
 
+			// grants beyond the retention period are never propagated, they're awaiting garbage collection.
+			if g.Expired(RetentionCutoff(time.Now(), t.retention)) {
+				continue
+			}
+
 			if batch = append(batch, g); len(batch) < cap(batch) {
 				continue
 			}
@@ -2,6 +2,7 @@ package notary_test
 
 import (
 	"context"
+	"time"
 
 	"github.com/bits-and-blooms/bloom/v3"
 	. "github.com/onsi/ginkgo/v2"
@@ -45,7 +46,7 @@ var _ = Describe("SyncServer", func() {
 
 		stream, err := client.Stream(context.Background(), req)
 		Expect(err).To(Succeed())
-		Expect(notary.Sync(stream, b, s2)).To(Succeed())
+		Expect(notary.Sync(stream, b, s2, 0)).To(Succeed())
 		for _, g := range expected {
 			_, err := s2.Lookup(g.Fingerprint)
 			Expect(err).To(Succeed())
@@ -67,3 +68,77 @@ var _ = Describe("SyncServer", func() {
 		),
 	)
 })
+
+var _ = Describe("SyncServer grant windows", func() {
+	It("should propagate bounded grants and ignore expired grants", func() {
+		now := time.Now()
+		active := notary.NewGrant(all(), QuickGrant().Authorization, notary.GrantOptionTTL(time.Hour))
+		expired := notary.NewGrant(all(), QuickGrant().Authorization, notary.GrantOptionWindow(time.Time{}, now.Add(-time.Minute)))
+		// a renewal of a grant the receiver already has.
+		renewed := notary.NewGrant(all(), QuickGrant().Authorization)
+		previous := notary.NewGrant(all(), renewed.Authorization, notary.GrantOptionWindow(time.Time{}, now.Add(time.Minute)))
+		renewed = notary.NewGrant(all(), renewed.Authorization, notary.GrantOptionTTL(8*time.Hour))
+
+		d, srv := testingx.NewGRPCServer2(func(s *grpc.Server) {
+			notary.NewSyncService(staticauth{Permission: all()}, notary.NewMem(active, expired, renewed)).Bind(s)
+		})
+		defer testingx.GRPCCleanup(nil, srv)
+
+		conn, err := d.Dial()
+		Expect(err).To(Succeed())
+
+		dst := notary.NewComposite("", notary.NewMem(previous))
+		b, err := dst.Bloomfilter(context.Background())
+		Expect(err).To(Succeed())
+
+		req, err := notary.NewSyncRequest(b)
+		Expect(err).To(Succeed())
+
+		stream, err := notary.NewSyncClient(conn).Stream(context.Background(), req)
+		Expect(err).To(Succeed())
+		Expect(notary.Sync(stream, b, dst, 0)).To(Succeed())
+
+		g, err := dst.Lookup(active.Fingerprint)
+		Expect(err).To(Succeed())
+		Expect(g.NotAfter).To(Equal(active.NotAfter))
+
+		_, err = dst.Lookup(expired.Fingerprint)
+		Expect(err).ToNot(Succeed())
+
+		g, err = dst.Lookup(renewed.Fingerprint)
+		Expect(err).To(Succeed())
+		Expect(g.NotAfter).To(Equal(renewed.NotAfter))
+	})
+
+	It("should propagate expired grants within the retention period", func() {
+		now := time.Now()
+		retained := notary.NewGrant(all(), QuickGrant().Authorization, notary.GrantOptionWindow(time.Time{}, now.Add(-time.Minute)))
+		collected := notary.NewGrant(all(), QuickGrant().Authorization, notary.GrantOptionWindow(time.Time{}, now.Add(-2*time.Hour)))
+
+		d, srv := testingx.NewGRPCServer2(func(s *grpc.Server) {
+			notary.NewSyncService(staticauth{Permission: all()}, notary.NewMem(retained, collected), notary.SyncServiceOptionRetention(time.Hour)).Bind(s)
+		})
+		defer testingx.GRPCCleanup(nil, srv)
+
+		conn, err := d.Dial()
+		Expect(err).To(Succeed())
+
+		dst := notary.NewComposite("", notary.NewMem())
+		b, err := dst.Bloomfilter(context.Background())
+		Expect(err).To(Succeed())
+
+		req, err := notary.NewSyncRequest(b)
+		Expect(err).To(Succeed())
+
+		stream, err := notary.NewSyncClient(conn).Stream(context.Background(), req)
+		Expect(err).To(Succeed())
+		Expect(notary.Sync(stream, b, dst, time.Hour)).To(Succeed())
+
+		g, err := dst.Lookup(retained.Fingerprint)
+		Expect(err).To(Succeed())
+		Expect(g.NotAfter).To(Equal(retained.NotAfter))
+
+		_, err = dst.Lookup(collected.Fingerprint)
+		Expect(err).ToNot(Succeed())
+	})
+})
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"os"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/errorsx"
+	"github.com/pkg/errors"
+)
+
+// ChunkLocation location of a chunk held by the local node.
+type ChunkLocation struct {
+	Path   string
+	Offset int64
+	Length int64
+}
+
+// ChunkIndex locates the chunks held by the local node, keyed by the hex encoded digest.
+type ChunkIndex interface {
+	Index() (map[string]ChunkLocation, error)
+}
+
+// SeekDownloader downloaders capable of reading portions of an archive.
+type SeekDownloader interface {
+	Open(context.Context, *agent.Archive) (io.ReadSeekCloser, error)
+}
+
+// OptionChunks reuse chunks held by the local node when downloading archives,
+// only the missing chunks are retrieved from the remote.
+func OptionChunks(idx ChunkIndex) Option {
+	return func(r *Registry) {
+		r.chunks = idx
+	}
+}
+
+// ReadChunk reads the chunk from the location, verifying its digest.
+func ReadChunk(loc ChunkLocation, digest []byte) (_ []byte, err error) {
+	var (
+		src *os.File
+	)
+
+	if src, err = os.Open(loc.Path); err != nil {
+		return nil, errors.Wrap(err, "unable to open chunk")
+	}
+	defer src.Close()
+
+	buf := make([]byte, loc.Length)
+	if _, err = src.ReadAt(buf, loc.Offset); err != nil {
+		return nil, errors.Wrap(err, "unable to read chunk")
+	}
+
+	if actual := sha256.Sum256(buf); !bytes.Equal(actual[:], digest) {
+		return nil, errors.Errorf("chunk digest mismatch: %s != %s", hex.EncodeToString(actual[:]), hex.EncodeToString(digest))
+	}
+
+	return buf, nil
+}
+
+type chunked struct {
+	idx    ChunkIndex
+	remote Downloader
+}
+
+// Download reassembles the archive from the chunks held locally and the missing chunks
+// from the remote. falls back to downloading the entire archive when no chunks
+// are held or the remote is unable to read portions of the archive.
+func (t chunked) Download(ctx context.Context, archive *agent.Archive) io.ReadCloser {
+	var (
+		err   error
+		held  map[string]ChunkLocation
+		found int
+	)
+
+	seeker, ok := t.remote.(SeekDownloader)
+	if len(archive.Chunks) == 0 || !ok {
+		return t.remote.Download(ctx, archive)
+	}
+
+	if held, err = t.idx.Index(); err != nil {
+		log.Println("unable to index local chunks, downloading the entire archive", err)
+		return t.remote.Download(ctx, archive)
+	}
+
+	for _, c := range archive.Chunks {
+		if _, ok := held[hex.EncodeToString(c.Digest)]; ok {
+			found++
+		}
+	}
+
+	if found == 0 {
+		return t.remote.Download(ctx, archive)
+	}
+
+	log.Printf("reusing %d/%d chunks for archive %s\n", found, len(archive.Chunks), archive.Location)
+
+	r, w := io.Pipe()
+	go func() {
+		w.CloseWithError(reassemble(ctx, seeker, archive, held, w))
+	}()
+
+	return r
+}
+
+func reassemble(ctx context.Context, remote SeekDownloader, archive *agent.Archive, held map[string]ChunkLocation, dst io.Writer) (err error) {
+	var (
+		offset int64
+		src    io.ReadSeekCloser
+	)
+
+	defer func() {
+		if src != nil {
+			errorsx.Log(errors.Wrap(src.Close(), "failed to close remote archive"))
+		}
+	}()
+
+	for _, c := range archive.Chunks {
+		length := int64(c.Length)
+
+		if loc, ok := held[hex.EncodeToString(c.Digest)]; ok {
+			buf, cause := ReadChunk(loc, c.Digest)
+			if cause == nil {
+				if _, err = dst.Write(buf); err != nil {
+					return errors.Wrap(err, "failed to write chunk")
+				}
+
+				offset += length
+				continue
+			}
+
+			log.Println("local chunk unusable, downloading", hex.EncodeToString(c.Digest), cause)
+		}
+
+		if src == nil {
+			if src, err = remote.Open(ctx, archive); err != nil {
+				return errors.Wrap(err, "unable to open remote archive")
+			}
+		}
+
+		if _, err = src.Seek(offset, io.SeekStart); err != nil {
+			return errors.Wrap(err, "unable to seek remote archive")
+		}
+
+		digest := sha256.New()
+		if _, err = io.CopyN(io.MultiWriter(dst, digest), src, length); err != nil {
+			return errors.Wrap(err, "failed to download chunk")
+		}
+
+		if !bytes.Equal(digest.Sum(nil), c.Digest) {
+			return errors.Errorf("downloaded chunk digest mismatch: %s", hex.EncodeToString(c.Digest))
+		}
+
+		offset += length
+	}
+
+	return nil
+}
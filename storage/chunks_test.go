@@ -0,0 +1,144 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/archive"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/storage"
+)
+
+type chunkIndex map[string]storage.ChunkLocation
+
+func (t chunkIndex) Index() (map[string]storage.ChunkLocation, error) {
+	return t, nil
+}
+
+// countingProtocol tracks the bytes read from the remote.
+type countingProtocol struct {
+	storage.Filesystem
+	read *int64
+}
+
+func (t countingProtocol) New() storage.Downloader {
+	return t
+}
+
+func (t countingProtocol) Open(ctx context.Context, a *agent.Archive) (io.ReadSeekCloser, error) {
+	src, err := t.Filesystem.Open(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+
+	return countingReader{ReadSeekCloser: src, read: t.read}, nil
+}
+
+type countingReader struct {
+	io.ReadSeekCloser
+	read *int64
+}
+
+func (t countingReader) Read(p []byte) (int, error) {
+	n, err := t.ReadSeekCloser.Read(p)
+	*t.read += int64(n)
+	return n, err
+}
+
+func packed(dir string, seed int64) (encoded []byte, chunks []*agent.ArchiveChunk) {
+	buf := make([]byte, 8*1024*1024)
+	_, err := rand.New(rand.NewSource(1)).Read(buf)
+	Expect(err).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "large"), buf, 0600)).To(Succeed())
+	_, err = rand.New(rand.NewSource(seed)).Read(buf[:1024])
+	Expect(err).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "small"), buf[:1024], 0600)).To(Succeed())
+
+	out := bytes.NewBuffer(nil)
+	Expect(archive.Pack(out, dir)).To(Succeed())
+	cc, err := archive.Chunks(bytes.NewReader(out.Bytes()))
+	Expect(err).To(Succeed())
+
+	for _, c := range cc {
+		chunks = append(chunks, &agent.ArchiveChunk{Digest: c.Digest, Length: uint64(c.Length)})
+	}
+
+	return out.Bytes(), chunks
+}
+
+func index(path string, chunks []*agent.ArchiveChunk) chunkIndex {
+	idx := chunkIndex{}
+	offset := int64(0)
+	for _, c := range chunks {
+		idx[hex.EncodeToString(c.Digest)] = storage.ChunkLocation{Path: path, Offset: offset, Length: int64(c.Length)}
+		offset += int64(c.Length)
+	}
+	return idx
+}
+
+var _ = Describe("Chunks", func() {
+	It("should only download missing chunks", func() {
+		var read int64
+		content := testingx.TempDir()
+		previous, pchunks := packed(content, 2)
+		current, cchunks := packed(content, 3)
+
+		local := filepath.Join(testingx.TempDir(), "archive.tar.gz")
+		Expect(os.WriteFile(local, previous, 0600)).To(Succeed())
+
+		fs := storage.NewFilesystem(testingx.TempDir())
+		u, err := fs.NewUpload(uint64(len(current)))
+		Expect(err).To(Succeed())
+		_, err = u.Upload(bytes.NewReader(current))
+		Expect(err).To(Succeed())
+		_, location, err := u.Info()
+		Expect(err).To(Succeed())
+
+		reg := storage.New(
+			storage.OptionProtocols(countingProtocol{Filesystem: fs, read: &read}),
+			storage.OptionChunks(index(local, pchunks)),
+		)
+
+		r := reg.New(location).Download(context.Background(), &agent.Archive{Location: location, Chunks: cchunks})
+		defer r.Close()
+		Expect(io.ReadAll(r)).To(Equal(current))
+		Expect(read).To(BeNumerically(">", 0))
+		Expect(read).To(BeNumerically("<", len(current)/2))
+	})
+
+	It("should download chunks whose local copy is corrupt", func() {
+		var read int64
+		content := testingx.TempDir()
+		current, chunks := packed(content, 2)
+
+		local := filepath.Join(testingx.TempDir(), "archive.tar.gz")
+		Expect(os.WriteFile(local, make([]byte, len(current)), 0600)).To(Succeed())
+
+		fs := storage.NewFilesystem(testingx.TempDir())
+		u, err := fs.NewUpload(uint64(len(current)))
+		Expect(err).To(Succeed())
+		_, err = u.Upload(bytes.NewReader(current))
+		Expect(err).To(Succeed())
+		_, location, err := u.Info()
+		Expect(err).To(Succeed())
+
+		reg := storage.New(
+			storage.OptionProtocols(countingProtocol{Filesystem: fs, read: &read}),
+			storage.OptionChunks(index(local, chunks)),
+		)
+
+		r := reg.New(location).Download(context.Background(), &agent.Archive{Location: location, Chunks: chunks})
+		defer r.Close()
+		Expect(io.ReadAll(r)).To(Equal(current))
+		Expect(read).To(Equal(int64(len(current))))
+	})
+})
@@ -69,14 +69,21 @@ func New(options ...Option) (r Registry) {
 // Registry of protocols for downloads.
 type Registry struct {
 	protocols map[string]DownloadProtocol
+	chunks    ChunkIndex
 }
 
 // New connect to the specified location by creating a io.ReadCloser
 func (t Registry) New(location string) Downloader {
 	for prefix, p := range t.protocols {
-		if strings.HasPrefix(location, prefix) {
-			return p.New()
+		if !strings.HasPrefix(location, prefix) {
+			continue
 		}
+
+		if t.chunks != nil {
+			return chunked{idx: t.chunks, remote: p.New()}
+		}
+
+		return p.New()
 	}
 
 	return newDownload(newErrReader(errors.Errorf("unknown protocol: [%s]", location)))
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"hash"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+)
+
+// NewFilesystem stores archives within a directory shared by every node, e.g.) an NFS mount.
+func NewFilesystem(dir string) Filesystem {
+	return Filesystem{dir: dir}
+}
+
+// Filesystem archive storage backed by a shared directory.
+type Filesystem struct {
+	dir string
+}
+
+// Protocol ...
+func (t Filesystem) Protocol() string {
+	return filesystemProtocol
+}
+
+// New ...
+func (t Filesystem) New() Downloader {
+	return t
+}
+
+// NewUpload ...
+func (t Filesystem) NewUpload(bytes uint64) (_ Uploader, err error) {
+	var (
+		dst *os.File
+	)
+
+	if err = os.MkdirAll(t.dir, 0755); err != nil {
+		return nil, errors.Wrap(err, "unable to create archive directory")
+	}
+
+	// hidden until the upload completes and is renamed to the deployment ID.
+	if dst, err = os.CreateTemp(t.dir, ".upload-*"); err != nil {
+		return nil, errors.Wrap(err, "unable to create upload")
+	}
+
+	return filesystemU{sha: sha256.New(), dst: dst}, nil
+}
+
+// Download the archive, archives are always read from the configured directory
+// since the mount point of the shared directory can differ between nodes.
+func (t Filesystem) Download(ctx context.Context, archive *agent.Archive) io.ReadCloser {
+	src, err := t.Open(ctx, archive)
+	if err != nil {
+		return newErrReader(err)
+	}
+
+	return src
+}
+
+// Open the archive.
+func (t Filesystem) Open(ctx context.Context, archive *agent.Archive) (_ io.ReadSeekCloser, err error) {
+	var (
+		u   *url.URL
+		src *os.File
+	)
+
+	if u, err = url.Parse(archive.Location); err != nil {
+		return nil, errors.Wrapf(err, "invalid archive location: %s", archive.Location)
+	}
+
+	if src, err = os.Open(filepath.Join(t.dir, filepath.Base(u.Path))); err != nil {
+		return nil, errors.Wrap(err, "unable to open archive")
+	}
+
+	return src, nil
+}
+
+type filesystemU struct {
+	sha hash.Hash
+	dst *os.File
+}
+
+func (t filesystemU) Upload(r io.Reader) (hash.Hash, error) {
+	return upload(r, t.sha, t.dst)
+}
+
+// Info ...
+func (t filesystemU) Info() (hash.Hash, string, error) {
+	// IMPORTANT: this id must match the deployment ID.
+	path := filepath.Join(filepath.Dir(t.dst.Name()), bw.RandomID(t.sha.Sum(nil)).String())
+
+	if err := t.dst.Sync(); err != nil {
+		return nil, "", errors.Wrap(err, "failed to sync to disk")
+	}
+
+	if err := t.dst.Close(); err != nil {
+		return nil, "", errors.Wrap(err, "failed to close upload")
+	}
+
+	if err := os.Rename(t.dst.Name(), path); err != nil {
+		return nil, "", errors.Wrap(err, "failed to rename upload")
+	}
+
+	return t.sha, (&url.URL{Scheme: filesystemProtocol, Path: path}).String(), nil
+}
+
+// Abort removes the partially written upload.
+func (t filesystemU) Abort() error {
+	return abort(t.dst)
+}
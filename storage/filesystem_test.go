@@ -0,0 +1,76 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/storage"
+)
+
+// roundtrip uploads the content and downloads it from the resulting location.
+func roundtrip(up storage.UploadProtocol, down storage.DownloadProtocol, content []byte) (location string, downloaded []byte) {
+	u, err := up.NewUpload(uint64(len(content)))
+	Expect(err).To(Succeed())
+	_, err = u.Upload(bytes.NewReader(content))
+	Expect(err).To(Succeed())
+	checksum, location, err := u.Info()
+	Expect(err).To(Succeed())
+	Expect(strings.HasPrefix(location, down.Protocol())).To(BeTrue())
+
+	dl := storage.New(storage.OptionProtocols(down)).New(location)
+	r := dl.Download(context.Background(), &agent.Archive{Location: location, Checksum: checksum.Sum(nil)})
+	defer r.Close()
+	downloaded, err = io.ReadAll(r)
+	Expect(err).To(Succeed())
+
+	return location, downloaded
+}
+
+var _ = Describe("Filesystem", func() {
+	It("should download uploaded archives", func() {
+		dir := testingx.TempDir()
+		fs := storage.NewFilesystem(dir)
+		content := []byte("hello world")
+
+		location, downloaded := roundtrip(fs, fs, content)
+		Expect(downloaded).To(Equal(content))
+		Expect(filepath.Base(location)).To(Equal(bw.RandomID(sha256sum(content)).String()))
+
+		entries, err := os.ReadDir(dir)
+		Expect(err).To(Succeed())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("should remove aborted uploads", func() {
+		dir := testingx.TempDir()
+		u, err := storage.NewFilesystem(dir).NewUpload(5)
+		Expect(err).To(Succeed())
+		_, err = u.Upload(bytes.NewBufferString("hello"))
+		Expect(err).To(Succeed())
+		Expect(u.Abort()).To(Succeed())
+		Expect(u.Abort()).To(Succeed())
+
+		entries, err := os.ReadDir(dir)
+		Expect(err).To(Succeed())
+		Expect(entries).To(BeEmpty())
+	})
+
+	It("should only read archives from the configured directory", func() {
+		dir := testingx.TempDir()
+		Expect(os.WriteFile(filepath.Join(dir, "archive"), []byte("expected"), 0600)).To(Succeed())
+
+		r := storage.NewFilesystem(dir).New().Download(context.Background(), &agent.Archive{Location: "file:///elsewhere/../archive"})
+		defer r.Close()
+		Expect(io.ReadAll(r)).To(Equal([]byte("expected")))
+	})
+})
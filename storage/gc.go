@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/torrent"
+	"github.com/pkg/errors"
+)
+
+// reasons an archive was retained.
+const (
+	retainedPinned   = "pinned"
+	retainedDeployed = "deployed"
+	retainedRecent   = "recent"
+	retainedCurrent  = "current"
+	retainedStaged   = "staged"
+)
+
+// Retention policy for the archives held by the torrent storage. archives of the
+// deploy directories retained by the coordinator's KeepN, archives staged ahead of
+// their deploy, and the archives of the quorum's current deploys are never removed.
+type Retention struct {
+	KeepN  int      // number of the most recently stored archives to retain.
+	Pinned []string // deployment ids of archives that are never removed.
+}
+
+// CurrentDeploys retrieves the archives of the quorum's latest and in progress deploys.
+type CurrentDeploys func() ([]*agent.Archive, error)
+
+// TorrentGCOption options for the garbage collection.
+type TorrentGCOption func(*TorrentGC)
+
+// TorrentGCOptionCurrent consults the quorum for the archives being deployed. recency
+// is local to the node, nodes that were not the target of a deploy never extracted its
+// archive and would otherwise be free to remove it.
+func TorrentGCOptionCurrent(c CurrentDeploys) TorrentGCOption {
+	return func(t *TorrentGC) {
+		t.current = c
+	}
+}
+
+// NewTorrentGC garbage collects the archives held by the torrent storage.
+func NewTorrentGC(c TorrentConfig, r Retention, options ...TorrentGCOption) TorrentGC {
+	gc := TorrentGC{
+		c:       c,
+		r:       r,
+		deploys: filepath.Join(filepath.Dir(c.ClientConfig.DataDir), bw.DirDeploys),
+		staged:  filepath.Join(filepath.Dir(c.ClientConfig.DataDir), bw.DirStaged),
+		current: func() ([]*agent.Archive, error) { return nil, nil },
+		m:       &sync.Mutex{},
+	}
+
+	for _, opt := range options {
+		opt(&gc)
+	}
+
+	return gc
+}
+
+// TorrentGC removes archives from the torrent storage based on a retention policy.
+type TorrentGC struct {
+	c       TorrentConfig
+	r       Retention
+	deploys string
+	staged  string
+	current CurrentDeploys
+	m       *sync.Mutex
+}
+
+// GC removes the archives not retained by the policy, dropping their torrents
+// from the client and deleting their data. a dry run only reports the archives
+// that would be removed.
+func (t TorrentGC) GC(dryrun bool) (s *agent.StorageGCResponse, err error) {
+	type archive struct {
+		id   string
+		info os.FileInfo
+	}
+
+	var (
+		entries  []os.DirEntry
+		archives []archive
+		deploys  []*agent.Archive
+		pinned   = make(map[string]bool, len(t.r.Pinned))
+		current  = make(map[string]bool)
+		running  = make(map[string]torrent.Torrent)
+	)
+
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	for _, id := range t.r.Pinned {
+		pinned[id] = true
+	}
+
+	// nothing is removed when the quorum's deploys are unknown.
+	if deploys, err = t.current(); err != nil {
+		return nil, errors.Wrap(err, "unable to retrieve the current deploys")
+	}
+
+	for _, a := range deploys {
+		current[bw.RandomID(a.DeploymentID).String()] = true
+	}
+
+	if entries, err = os.ReadDir(t.c.ClientConfig.DataDir); err != nil {
+		return nil, errors.Wrap(err, "failed to read torrent storage")
+	}
+
+	// only completed archives are named by their deployment id, partial uploads and the
+	// torrent databases are ignored.
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+
+		if _, cause := bw.ParseRandomID(e.Name()); cause != nil {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to stat archive: %s", e.Name())
+		}
+
+		archives = append(archives, archive{id: e.Name(), info: info})
+	}
+
+	sort.SliceStable(archives, func(i, j int) bool {
+		return archives[i].info.ModTime().After(archives[j].info.ModTime())
+	})
+
+	for _, tt := range t.c.client.Torrents() {
+		if tt.Info() == nil {
+			continue
+		}
+
+		for _, tf := range tt.Files() {
+			running[tf.Path()] = tt
+		}
+	}
+
+	s = &agent.StorageGCResponse{DryRun: dryrun}
+	for idx, a := range archives {
+		sa := &agent.StorageArchive{Id: a.id, Bytes: uint64(a.info.Size())}
+
+		switch {
+		case pinned[a.id]:
+			sa.Reason = retainedPinned
+		case current[a.id]:
+			sa.Reason = retainedCurrent
+		case t.deployed(a.id):
+			sa.Reason = retainedDeployed
+		case t.isstaged(a.id):
+			sa.Reason = retainedStaged
+		case idx < t.r.KeepN:
+			sa.Reason = retainedRecent
+		}
+
+		if sa.Reason != "" {
+			s.Retained = append(s.Retained, sa)
+			continue
+		}
+
+		s.Removed = append(s.Removed, sa)
+
+		if dryrun {
+			continue
+		}
+
+		if tt, ok := running[a.id]; ok {
+			if err = t.c.client.Stop(tt.Metadata()); err != nil {
+				return s, errors.Wrapf(err, "failed to stop torrent: %s", a.id)
+			}
+		}
+
+		if err = os.Remove(filepath.Join(t.c.ClientConfig.DataDir, a.id)); err != nil && !os.IsNotExist(err) {
+			return s, errors.Wrapf(err, "failed to remove archive: %s", a.id)
+		}
+	}
+
+	return s, nil
+}
+
+// Collect the storage, logging the outcome.
+func (t TorrentGC) Collect() {
+	s, err := t.GC(false)
+	if err != nil {
+		log.Println("archive garbage collection failed", err)
+		return
+	}
+
+	log.Println(len(s.Removed), "archives removed", len(s.Retained), "archives retained")
+}
+
+func (t TorrentGC) deployed(id string) bool {
+	_, err := os.Stat(filepath.Join(t.deploys, id))
+	return err == nil
+}
+
+// isstaged whether the coordinator staged the archive ahead of its deploy.
+func (t TorrentGC) isstaged(id string) bool {
+	_, err := os.Stat(filepath.Join(t.staged, id))
+	return err == nil
+}
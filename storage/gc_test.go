@@ -0,0 +1,156 @@
+package storage_test
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/testingx"
+	"github.com/james-lawrence/bw/internal/torrentx"
+	"github.com/james-lawrence/bw/storage"
+	"github.com/james-lawrence/torrent"
+	"github.com/pkg/errors"
+)
+
+type localcluster struct{}
+
+func (localcluster) Quorum() []*agent.Peer { return nil }
+func (localcluster) Peers() []*agent.Peer  { return nil }
+func (localcluster) Local() *agent.Peer    { return &agent.Peer{Name: "local"} }
+
+func ids(archives []*agent.StorageArchive) (r []string) {
+	for _, a := range archives {
+		r = append(r, a.Id)
+	}
+	return r
+}
+
+var _ = Describe("TorrentGC", func() {
+	var (
+		root     string
+		tc       storage.TorrentConfig
+		archives []string
+	)
+
+	BeforeEach(func() {
+		root = testingx.TempDir()
+		archives = nil
+
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).To(Succeed())
+		DeferCleanup(l.Close)
+
+		tc, err = storage.NewTorrent(
+			localcluster{},
+			storage.TorrentOptionBind(torrent.NewSocketsBind(torrentx.Socket{Listener: l, Dialer: &net.Dialer{}})),
+			storage.TorrentOptionDataDir(filepath.Join(root, bw.DirTorrents)),
+		)
+		Expect(err).To(Succeed())
+
+		// oldest to newest.
+		now := time.Now()
+		for idx, content := range []string{"pinned", "deployed", "expired", "recent"} {
+			u, err := tc.Uploader().NewUpload(uint64(len(content)))
+			Expect(err).To(Succeed())
+			_, err = u.Upload(bytes.NewBufferString(content))
+			Expect(err).To(Succeed())
+			checksum, _, err := u.Info()
+			Expect(err).To(Succeed())
+
+			id := bw.RandomID(checksum.Sum(nil)).String()
+			ts := now.Add(time.Duration(idx-4) * time.Hour)
+			Expect(os.Chtimes(filepath.Join(root, bw.DirTorrents, id), ts, ts)).To(Succeed())
+			archives = append(archives, id)
+		}
+
+		Expect(os.MkdirAll(filepath.Join(root, bw.DirDeploys, archives[1]), 0700)).To(Succeed())
+
+		// partial uploads are never collected.
+		Expect(os.WriteFile(filepath.Join(root, bw.DirTorrents, "upload-1.bin"), []byte("partial"), 0600)).To(Succeed())
+	})
+
+	It("should only report the archives that would be removed during a dry run", func() {
+		s, err := storage.NewTorrentGC(tc, storage.Retention{KeepN: 1, Pinned: archives[:1]}).GC(true)
+		Expect(err).To(Succeed())
+		Expect(s.DryRun).To(BeTrue())
+		Expect(ids(s.Removed)).To(Equal([]string{archives[2]}))
+		Expect(ids(s.Retained)).To(ConsistOf(archives[0], archives[1], archives[3]))
+
+		for _, id := range archives {
+			Expect(filepath.Join(root, bw.DirTorrents, id)).To(BeAnExistingFile())
+		}
+	})
+
+	It("should retain recent, deployed, and pinned archives", func() {
+		s, err := storage.NewTorrentGC(tc, storage.Retention{KeepN: 1, Pinned: archives[:1]}).GC(false)
+		Expect(err).To(Succeed())
+		Expect(ids(s.Removed)).To(Equal([]string{archives[2]}))
+
+		reasons := map[string]string{}
+		for _, a := range s.Retained {
+			reasons[a.Id] = a.Reason
+		}
+		Expect(reasons).To(Equal(map[string]string{
+			archives[0]: "pinned",
+			archives[1]: "deployed",
+			archives[3]: "recent",
+		}))
+
+		Expect(filepath.Join(root, bw.DirTorrents, archives[2])).ToNot(BeAnExistingFile())
+		Expect(filepath.Join(root, bw.DirTorrents, "upload-1.bin")).To(BeAnExistingFile())
+	})
+
+	It("should retain archives staged ahead of their deploy", func() {
+		Expect(os.MkdirAll(filepath.Join(root, bw.DirStaged), 0700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(root, bw.DirStaged, archives[2]), []byte("expired"), 0600)).To(Succeed())
+
+		s, err := storage.NewTorrentGC(tc, storage.Retention{KeepN: 0}).GC(false)
+		Expect(err).To(Succeed())
+		Expect(ids(s.Removed)).To(ConsistOf(archives[0], archives[3]))
+
+		reasons := map[string]string{}
+		for _, a := range s.Retained {
+			reasons[a.Id] = a.Reason
+		}
+		Expect(reasons).To(Equal(map[string]string{
+			archives[1]: "deployed",
+			archives[2]: "staged",
+		}))
+		Expect(filepath.Join(root, bw.DirTorrents, archives[2])).To(BeAnExistingFile())
+	})
+
+	It("should retain the archive of the quorum's current deploy", func() {
+		id, err := bw.ParseRandomID(archives[2])
+		Expect(err).To(Succeed())
+
+		current := storage.TorrentGCOptionCurrent(func() ([]*agent.Archive, error) {
+			return []*agent.Archive{{DeploymentID: id}}, nil
+		})
+
+		s, err := storage.NewTorrentGC(tc, storage.Retention{KeepN: 0}, current).GC(false)
+		Expect(err).To(Succeed())
+		Expect(ids(s.Removed)).To(ConsistOf(archives[0], archives[3]))
+		Expect(ids(s.Retained)).To(ConsistOf(archives[1], archives[2]))
+		Expect(filepath.Join(root, bw.DirTorrents, archives[2])).To(BeAnExistingFile())
+	})
+
+	It("should not remove archives when the quorum's deploys are unknown", func() {
+		current := storage.TorrentGCOptionCurrent(func() ([]*agent.Archive, error) {
+			return nil, errors.New("quorum unavailable")
+		})
+
+		_, err := storage.NewTorrentGC(tc, storage.Retention{KeepN: 0}, current).GC(false)
+		Expect(err).ToNot(Succeed())
+
+		for _, id := range archives {
+			Expect(filepath.Join(root, bw.DirTorrents, id)).To(BeAnExistingFile())
+		}
+	})
+})
@@ -0,0 +1,237 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+// S3Option options for the s3 storage.
+type S3Option func(*S3)
+
+// S3OptionPrefix key prefix for archives within the bucket.
+func S3OptionPrefix(p string) S3Option {
+	return func(s *S3) {
+		s.prefix = strings.Trim(p, "/")
+	}
+}
+
+// NewS3 stores archives within a bucket of a s3 compatible service, e.g.) aws or minio.
+func NewS3(c *s3.Client, bucket string, options ...S3Option) S3 {
+	s := S3{
+		client: c,
+		bucket: bucket,
+	}
+
+	for _, opt := range options {
+		opt(&s)
+	}
+
+	return s
+}
+
+// S3 archive storage backed by a bucket.
+type S3 struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// Protocol ...
+func (t S3) Protocol() string {
+	return s3Protocol
+}
+
+// New ...
+func (t S3) New() Downloader {
+	return t
+}
+
+// NewUpload stages the upload locally, it is sent to the bucket once complete.
+func (t S3) NewUpload(bytes uint64) (_ Uploader, err error) {
+	var (
+		dst *os.File
+	)
+
+	if dst, err = os.CreateTemp("", "bw-s3-upload-*"); err != nil {
+		return nil, errors.Wrap(err, "unable to create upload")
+	}
+
+	return s3U{S3: t, sha: sha256.New(), dst: dst}, nil
+}
+
+// Download the archive from the bucket.
+func (t S3) Download(ctx context.Context, archive *agent.Archive) io.ReadCloser {
+	var (
+		err error
+		key string
+		out *s3.GetObjectOutput
+	)
+
+	if key, err = t.key(archive.Location); err != nil {
+		return newErrReader(err)
+	}
+
+	if out, err = t.client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(t.bucket), Key: aws.String(key)}); err != nil {
+		return newErrReader(errors.Wrapf(err, "unable to download archive: %s", archive.Location))
+	}
+
+	return out.Body
+}
+
+// Open the archive, portions of the archive are retrieved with range requests.
+func (t S3) Open(ctx context.Context, archive *agent.Archive) (_ io.ReadSeekCloser, err error) {
+	var (
+		key string
+	)
+
+	if key, err = t.key(archive.Location); err != nil {
+		return nil, err
+	}
+
+	return &s3Range{ctx: ctx, client: t.client, bucket: t.bucket, key: key}, nil
+}
+
+// key of the archive within the bucket, locations outside of the configured
+// bucket and prefix are refused.
+func (t S3) key(location string) (_ string, err error) {
+	var (
+		u *url.URL
+	)
+
+	if u, err = url.Parse(location); err != nil {
+		return "", errors.Wrapf(err, "invalid archive location: %s", location)
+	}
+
+	key := path.Clean(strings.TrimPrefix(u.Path, "/"))
+	if u.Scheme != s3Protocol || u.Host != t.bucket || key != path.Join(t.prefix, path.Base(key)) {
+		return "", errors.Errorf("archive location is outside of the bucket: %s", location)
+	}
+
+	return key, nil
+}
+
+// s3Range reads the object from the current offset, seeking discards the
+// outstanding request.
+type s3Range struct {
+	ctx    context.Context
+	client *s3.Client
+	bucket string
+	key    string
+	offset int64
+	body   io.ReadCloser
+}
+
+func (t *s3Range) Read(p []byte) (n int, err error) {
+	var (
+		out *s3.GetObjectOutput
+	)
+
+	if t.body == nil {
+		in := &s3.GetObjectInput{
+			Bucket: aws.String(t.bucket),
+			Key:    aws.String(t.key),
+			Range:  aws.String(fmt.Sprintf("bytes=%d-", t.offset)),
+		}
+
+		if out, err = t.client.GetObject(t.ctx, in); err != nil {
+			return 0, errors.Wrapf(err, "unable to download archive: s3://%s/%s", t.bucket, t.key)
+		}
+
+		t.body = out.Body
+	}
+
+	n, err = t.body.Read(p)
+	t.offset += int64(n)
+	return n, err
+}
+
+func (t *s3Range) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += t.offset
+	default:
+		return t.offset, errors.Errorf("unsupported seek: %d", whence)
+	}
+
+	if offset == t.offset {
+		return t.offset, nil
+	}
+
+	t.offset = offset
+	return t.offset, t.Close()
+}
+
+func (t *s3Range) Close() (err error) {
+	if t.body == nil {
+		return nil
+	}
+
+	err = t.body.Close()
+	t.body = nil
+	return errors.Wrap(err, "failed to close archive")
+}
+
+type s3U struct {
+	S3
+	sha hash.Hash
+	dst *os.File
+}
+
+func (t s3U) Upload(r io.Reader) (hash.Hash, error) {
+	return upload(r, t.sha, t.dst)
+}
+
+// Info sends the archive to the bucket.
+func (t s3U) Info() (_ hash.Hash, location string, err error) {
+	var (
+		info os.FileInfo
+	)
+
+	defer func() {
+		errorsx.Log(errorsx.Compact(t.dst.Close(), os.Remove(t.dst.Name())))
+	}()
+
+	if info, err = t.dst.Stat(); err != nil {
+		return nil, "", errors.Wrap(err, "unable to stat upload")
+	}
+
+	if _, err = t.dst.Seek(0, io.SeekStart); err != nil {
+		return nil, "", errors.Wrap(err, "unable to rewind upload")
+	}
+
+	// IMPORTANT: this id must match the deployment ID.
+	key := path.Join(t.prefix, bw.RandomID(t.sha.Sum(nil)).String())
+
+	_, err = t.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:        aws.String(t.bucket),
+		Key:           aws.String(key),
+		Body:          t.dst,
+		ContentLength: aws.Int64(info.Size()),
+	})
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "unable to upload archive to %s/%s", t.bucket, key)
+	}
+
+	return t.sha, (&url.URL{Scheme: s3Protocol, Host: t.bucket, Path: "/" + key}).String(), nil
+}
+
+// Abort removes the partially written upload, nothing is sent to the bucket until Info.
+func (t s3U) Abort() error {
+	return abort(t.dst)
+}
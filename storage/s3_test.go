@@ -0,0 +1,112 @@
+package storage_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/storage"
+)
+
+func sha256sum(b []byte) []byte {
+	digest := sha256.Sum256(b)
+	return digest[:]
+}
+
+// fakeS3 minimal path style object storage.
+type fakeS3 struct {
+	sync.Mutex
+	objects map[string][]byte
+}
+
+func (t *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t.Lock()
+	defer t.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		b, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		t.objects[r.URL.Path] = b
+	case http.MethodGet:
+		b, ok := t.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(b)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+var _ = Describe("S3", func() {
+	It("should download uploaded archives", func() {
+		fake := &fakeS3{objects: map[string][]byte{}}
+		srv := httptest.NewServer(fake)
+		defer srv.Close()
+
+		client := s3.New(s3.Options{
+			Region:       "us-east-1",
+			BaseEndpoint: aws.String(srv.URL),
+			UsePathStyle: true,
+			Credentials:  aws.AnonymousCredentials{},
+		})
+
+		s := storage.NewS3(client, "archives", storage.S3OptionPrefix("/deploys/"))
+		content := []byte("hello world")
+
+		location, downloaded := roundtrip(s, s, content)
+		Expect(downloaded).To(Equal(content))
+		Expect(location).To(HavePrefix("s3://archives/deploys/"))
+		Expect(fake.objects).To(HaveLen(1))
+		for k := range fake.objects {
+			Expect(strings.TrimPrefix(k, "/archives")).To(Equal(strings.TrimPrefix(location, "s3://archives")))
+		}
+	})
+
+	It("should refuse locations outside of the bucket and prefix", func() {
+		fake := &fakeS3{objects: map[string][]byte{
+			"/archives/secrets/key":  []byte("secret"),
+			"/other/deploys/archive": []byte("secret"),
+		}}
+		srv := httptest.NewServer(fake)
+		defer srv.Close()
+
+		client := s3.New(s3.Options{
+			Region:       "us-east-1",
+			BaseEndpoint: aws.String(srv.URL),
+			UsePathStyle: true,
+			Credentials:  aws.AnonymousCredentials{},
+		})
+
+		s := storage.NewS3(client, "archives", storage.S3OptionPrefix("deploys"))
+		for _, location := range []string{
+			"s3://other/deploys/archive",
+			"s3://archives/secrets/key",
+			"s3://archives/deploys/../secrets/key",
+			"file:///archives/deploys/archive",
+		} {
+			_, err := s.Open(context.Background(), &agent.Archive{Location: location})
+			Expect(err).ToNot(Succeed(), location)
+
+			r := s.Download(context.Background(), &agent.Archive{Location: location})
+			_, err = io.ReadAll(r)
+			Expect(err).ToNot(Succeed(), location)
+			Expect(r.Close()).To(Succeed())
+		}
+	})
+})
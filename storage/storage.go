@@ -1,6 +1,6 @@
 // Package storage provides implementations for downloading and uploading archives
 // to nodes within the cluster.
-// current implementations: torrent (bittorrent), and s3.
+// current implementations: torrent (bittorrent), s3, and filesystem (shared directory).
 package storage
 
 import (
@@ -10,12 +10,14 @@ import (
 )
 
 const (
-	s3Protocol      = "s3"
-	torrentProtocol = "magnet"
+	s3Protocol         = "s3"
+	filesystemProtocol = "file"
+	torrentProtocol    = "magnet"
 )
 
 type cluster interface {
 	Quorum() []*agent.Peer
+	Peers() []*agent.Peer
 	Local() *agent.Peer
 }
 
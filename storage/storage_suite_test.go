@@ -1,6 +1,7 @@
 package storage_test
 
 import (
+	"github.com/james-lawrence/bw/internal/testingx"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -11,3 +12,5 @@ func TestStorage(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Storage Suite")
 }
+
+var _ = SynchronizedAfterSuite(func() {}, testingx.Cleanup)
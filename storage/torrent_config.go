@@ -6,6 +6,7 @@ import (
 	"log"
 	"net"
 	"os"
+	"time"
 
 	"github.com/james-lawrence/bw"
 	"github.com/james-lawrence/bw/internal/envx"
@@ -13,6 +14,7 @@ import (
 	"github.com/james-lawrence/torrent/connections"
 	"github.com/james-lawrence/torrent/dht/v2"
 	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
 )
 
 // TorrentOption ...
@@ -32,7 +34,10 @@ func TorrentOptionDataDir(a string) TorrentOption {
 	}
 }
 
-// TorrentOptionDHTPeers set the directory to store data in.
+// TorrentOptionDHTPeers seed the DHT with the quorum's peers regardless of their zone.
+// the DHT is shared by every torrent and peers it discovers are connected to directly,
+// so the zone preference of downloads is best effort: peers in other zones can be
+// used before the zone fallback elapses.
 func TorrentOptionDHTPeers(a cluster) TorrentOption {
 	return func(c *TorrentConfig) {
 		c.ClientConfig.DhtStartingNodes = func() (peers []dht.Addr, err error) {
@@ -45,6 +50,38 @@ func TorrentOptionDHTPeers(a cluster) TorrentOption {
 	}
 }
 
+// TorrentOptionRateLimits limit the bytes per second the client uploads and downloads,
+// zero disables the limit.
+func TorrentOptionRateLimits(upload, download int) TorrentOption {
+	return func(c *TorrentConfig) {
+		if upload > 0 {
+			c.ClientConfig.UploadRateLimiter = limiter(upload)
+		}
+
+		if download > 0 {
+			c.ClientConfig.DownloadRateLimiter = limiter(download)
+		}
+	}
+}
+
+// TorrentOptionZoneFallback how long downloads only add peers within the local zone before
+// peers in other zones are added, see TorrentOptionDHTPeers for peers discovered by the DHT.
+func TorrentOptionZoneFallback(d time.Duration) TorrentOption {
+	return func(c *TorrentConfig) {
+		c.fallback = d
+	}
+}
+
+// limiter for the bytes per second, the burst must fit an entire chunk and the
+// largest read performed by the client.
+func limiter(n int) *rate.Limiter {
+	const (
+		minburst = 64 * 1024
+	)
+
+	return rate.NewLimiter(rate.Limit(n), max(n, minburst))
+}
+
 // NewTorrent ...
 func NewTorrent(cls cluster, options ...TorrentOption) (c TorrentConfig, err error) {
 	var (
@@ -53,6 +90,7 @@ func NewTorrent(cls cluster, options ...TorrentOption) (c TorrentConfig, err err
 
 	c = TorrentConfig{
 		c:            cls,
+		fallback:     10 * time.Second,
 		ClientConfig: torrent.NewDefaultClientConfig(),
 	}
 
@@ -90,8 +128,9 @@ func NewTorrent(cls cluster, options ...TorrentOption) (c TorrentConfig, err err
 
 // TorrentConfig ...
 type TorrentConfig struct {
-	b torrent.Binder
-	c cluster
+	b        torrent.Binder
+	c        cluster
+	fallback time.Duration
 	*torrent.ClientConfig
 	client *torrent.Client
 }
@@ -99,9 +138,10 @@ type TorrentConfig struct {
 // Downloader ...
 func (t TorrentConfig) Downloader() DownloadProtocol {
 	return torrentP{
-		c:      t.c,
-		config: t.ClientConfig,
-		client: t.client,
+		c:        t.c,
+		fallback: t.fallback,
+		config:   t.ClientConfig,
+		client:   t.client,
 	}
 }
 
@@ -115,10 +155,11 @@ func (t TorrentConfig) Uploader() UploadProtocol {
 }
 
 type torrentP struct {
-	c      cluster
-	config *torrent.ClientConfig
-	client *torrent.Client
-	util   TorrentUtil
+	c        cluster
+	fallback time.Duration
+	config   *torrent.ClientConfig
+	client   *torrent.Client
+	util     TorrentUtil
 }
 
 func (t torrentP) Protocol() string {
@@ -127,9 +168,10 @@ func (t torrentP) Protocol() string {
 
 func (t torrentP) New() Downloader {
 	return torrentD{
-		c:      t.c,
-		dir:    t.config.DataDir,
-		client: t.client,
+		c:        t.c,
+		fallback: t.fallback,
+		dir:      t.config.DataDir,
+		client:   t.client,
 	}
 }
 
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"io"
+	"log"
 	"net"
 	"time"
 
@@ -11,10 +12,14 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ZoneLabel label identifying the zone of a peer, downloads prefer peers within the local zone.
+const ZoneLabel = "zone"
+
 type torrentD struct {
-	c      cluster
-	client *torrent.Client
-	dir    string
+	c        cluster
+	fallback time.Duration
+	client   *torrent.Client
+	dir      string
 }
 
 func peerToNode(p *agent.Peer) torrent.Peer {
@@ -33,22 +38,98 @@ func peersToNode(peers ...*agent.Peer) (r []torrent.Peer) {
 	return r
 }
 
+// zoned partitions the peers into those within the zone of the local peer and the remainder.
+// peers without a zone are never considered near.
+func zoned(local *agent.Peer, peers ...*agent.Peer) (near, far []*agent.Peer) {
+	zone := local.GetLabels()[ZoneLabel]
+	seen := make(map[string]bool, len(peers))
+
+	for _, p := range peers {
+		if p.Name == local.Name || seen[p.Name] {
+			continue
+		}
+		seen[p.Name] = true
+
+		if zone != "" && p.GetLabels()[ZoneLabel] == zone {
+			near = append(near, p)
+		} else {
+			far = append(far, p)
+		}
+	}
+
+	return near, far
+}
+
 func (t torrentD) Download(ctx context.Context, archive *agent.Archive) (r io.ReadCloser) {
 	var (
-		err      error
+		err error
+	)
+
+	if r, err = t.Open(ctx, archive); err != nil {
+		return newErrReader(err)
+	}
+
+	return r
+}
+
+// Open the archive, pieces are retrieved from the swarm as they are read.
+func (t torrentD) Open(ctx context.Context, archive *agent.Archive) (r io.ReadSeekCloser, err error) {
+	var (
 		metadata torrent.Metadata
 	)
 
 	if metadata, err = torrent.NewFromMagnet(archive.Location); err != nil {
-		return newErrReader(errors.WithStack(err))
+		return nil, errors.WithStack(err)
+	}
+
+	near, far := zoned(t.c.Local(), append(t.c.Peers(), t.c.Quorum()...)...)
+
+	// peers within the zone are tried first, peers in other zones are added
+	// once the fallback elapses.
+	if len(near) > 0 {
+		if err = t.prefer(ctx, metadata, near, far); err != nil {
+			return nil, err
+		}
+	} else {
+		near = far
 	}
 
 	wait, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	if r, err = t.client.Download(wait, metadata, torrent.TunePeers(peersToNode(t.c.Quorum()...)...)); err != nil {
-		return newErrReader(errors.WithStack(err))
+	if r, err = t.client.Download(wait, metadata, torrent.TunePeers(peersToNode(near...)...)); err != nil {
+		return nil, errors.WithStack(err)
 	}
 
-	return r
+	return r, nil
+}
+
+func (t torrentD) prefer(ctx context.Context, metadata torrent.Metadata, near, far []*agent.Peer) (err error) {
+	var (
+		tt torrent.Torrent
+	)
+
+	if tt, _, err = t.client.Start(metadata); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if err = tt.Tune(torrent.TunePeers(peersToNode(near...)...)); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if len(far) == 0 {
+		return nil
+	}
+
+	go func() {
+		select {
+		case <-time.After(t.fallback):
+			if err := tt.Tune(torrent.TunePeers(peersToNode(far...)...)); err != nil {
+				log.Println("unable to add peers outside of the zone", err)
+			}
+		case <-ctx.Done():
+		}
+	}()
+
+	return nil
 }
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"github.com/james-lawrence/bw/agent"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	g "github.com/onsi/gomega"
+)
+
+var _ = Describe("zoned", func() {
+	peer := func(name, zone string) *agent.Peer {
+		p := &agent.Peer{Name: name}
+		if zone != "" {
+			p.Labels = map[string]string{ZoneLabel: zone}
+		}
+		return p
+	}
+
+	It("should prefer peers within the local zone", func() {
+		local := peer("local", "us-east-1a")
+		p1 := peer("p1", "us-east-1a")
+		p2 := peer("p2", "us-east-1b")
+		p3 := peer("p3", "")
+
+		near, far := zoned(local, local, p1, p2, p3, p1)
+		g.Expect(near).To(g.Equal([]*agent.Peer{p1}))
+		g.Expect(far).To(g.Equal([]*agent.Peer{p2, p3}))
+	})
+
+	It("should treat every peer as far when the local zone is unknown", func() {
+		local := peer("local", "")
+		p1 := peer("p1", "us-east-1a")
+		p2 := peer("p2", "")
+
+		near, far := zoned(local, p1, p2)
+		g.Expect(near).To(g.BeEmpty())
+		g.Expect(far).To(g.Equal([]*agent.Peer{p1, p2}))
+	})
+})
@@ -50,3 +50,8 @@ func (t torrentU) Info() (hash.Hash, string, error) {
 
 	return t.sha, util.magnet(mi).String(), nil
 }
+
+// Abort removes the partially written upload.
+func (t torrentU) Abort() error {
+	return abort(t.dst)
+}
@@ -6,9 +6,6 @@ import (
 	"path/filepath"
 
 	"github.com/davecgh/go-spew/spew"
-	"github.com/james-lawrence/bw"
-	"github.com/james-lawrence/bw/internal/debugx"
-	"github.com/james-lawrence/bw/internal/errorsx"
 	"github.com/james-lawrence/torrent"
 	"github.com/james-lawrence/torrent/dht/v2"
 	"github.com/james-lawrence/torrent/metainfo"
@@ -35,28 +32,6 @@ func (TorrentUtil) Bootstrap(c *torrent.Client) {
 	}
 }
 
-// ClearTorrents periodically flushes torrents from storage based on whether or not
-// the deployment directory is still around.
-func (TorrentUtil) ClearTorrents(c TorrentConfig) {
-	debugx.Println("torrent data directory", c.ClientConfig.DataDir)
-	deploysDir := filepath.Join(filepath.Dir(c.ClientConfig.DataDir), bw.DirDeploys)
-	dropped := 0
-	for _, tt := range c.client.Torrents() {
-		if info := tt.Info(); info != nil {
-			for _, tf := range tt.Files() {
-				deployDir := filepath.Join(deploysDir, tf.Path())
-				if _, cause := os.Stat(deployDir); os.IsNotExist(cause) {
-					c.client.Stop(tt.Metadata())
-					errorsx.Log(os.Remove(filepath.Join(c.ClientConfig.DataDir, tf.Path())))
-					dropped = dropped + 1
-				}
-			}
-		}
-	}
-
-	log.Println(dropped, "torrents dropped due to missing deploy directory")
-}
-
 // PrintTorrentInfo prints information about the torrent cluster.
 func (t TorrentUtil) PrintTorrentInfo(c TorrentConfig) {
 	t.printTorrentInfo(c.client)
@@ -4,8 +4,11 @@ import (
 	"crypto/sha256"
 	"hash"
 	"io"
+	"os"
 
 	"github.com/pkg/errors"
+
+	"github.com/james-lawrence/bw/internal/errorsx"
 )
 
 // UploadProtocol builds io.WriteCloser given the expected size of the upload.
@@ -22,6 +25,9 @@ type Uploader interface {
 	// Info returns the result of the upload. this includes the overall checksum of the
 	// file, the string uri of its location or an error.
 	Info() (hash.Hash, string, error)
+
+	// Abort discards the partially written upload.
+	Abort() error
 }
 
 // NewNoopUpload utility helper for returning a uploader that does nothing but
@@ -46,6 +52,11 @@ func (t errUploader) Info() (hash.Hash, string, error) {
 	return nil, "", t.err
 }
 
+// Abort does nothing, nothing was written.
+func (t errUploader) Abort() error {
+	return nil
+}
+
 // NewErrUploadProtocol upload protocol builder that returns an error
 func NewErrUploadProtocol(err error) UploadProtocol {
 	return errUploadProtocol{err: err}
@@ -64,3 +75,11 @@ func upload(src io.Reader, sha hash.Hash, dst io.Writer) (hash.Hash, error) {
 	_, err := io.Copy(io.MultiWriter(sha, crc, dst), src)
 	return crc, errors.WithStack(err)
 }
+
+// abort removes a partially written upload, the file may already be closed or removed.
+func abort(dst *os.File) error {
+	return errorsx.Compact(
+		errorsx.Ignore(dst.Close(), os.ErrClosed),
+		errors.Wrap(errorsx.Ignore(os.Remove(dst.Name()), os.ErrNotExist), "failed to remove upload"),
+	)
+}
@@ -0,0 +1,789 @@
+package ux
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/logrusorgru/aurora"
+	"github.com/mattn/go-runewidth"
+	"github.com/pkg/errors"
+	"github.com/pterm/pterm"
+	"golang.org/x/term"
+	"google.golang.org/grpc"
+
+	"github.com/james-lawrence/bw"
+	"github.com/james-lawrence/bw/agent"
+	"github.com/james-lawrence/bw/agent/dialers"
+	"github.com/james-lawrence/bw/internal/errorsx"
+)
+
+const (
+	maxEventLines = 500
+	maxLogLines   = 2000
+)
+
+type nodeStatus int
+
+const (
+	nodePending nodeStatus = iota
+	nodeDeploying
+	nodeDone
+	nodeFailed
+)
+
+func (t nodeStatus) String() string {
+	switch t {
+	case nodeDeploying:
+		return "deploying"
+	case nodeDone:
+		return "done"
+	case nodeFailed:
+		return "failed"
+	default:
+		return "pending"
+	}
+}
+
+type node struct {
+	peer   *agent.Peer
+	status nodeStatus
+	latest *agent.Message // most recent deploy event for the node.
+}
+
+func (t node) label() string {
+	if len(t.peer.Name) <= 24 {
+		return t.peer.Name
+	}
+
+	return net.JoinHostPort(t.peer.Ip, strconv.Itoa(int(t.peer.P2PPort)))
+}
+
+// interactive full screen deploy view, falls back to the standard
+// deploy output when not attached to a terminal.
+func interactive(ctx context.Context, s cState, events chan *agent.Message) {
+	var (
+		err   error
+		state *term.State
+		stdin = int(os.Stdin.Fd())
+	)
+
+	if !term.IsTerminal(stdin) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		s.Logger.Println("interactive display requires a terminal, ignoring")
+		s.run(ctx, events, deploying{cState: s})
+		return
+	}
+
+	if state, err = term.MakeRaw(stdin); err != nil {
+		s.Logger.Println("unable to initialize interactive display, ignoring", err)
+		s.run(ctx, events, deploying{cState: s})
+		return
+	}
+
+	scr := newScreen(s, os.Stdout, func() (int, int) {
+		return pterm.GetTerminalWidth(), pterm.GetTerminalHeight()
+	})
+
+	// anything logged while the view is active is displayed in the event pane.
+	original := log.Writer()
+	log.SetOutput(scr.events)
+
+	scr.open()
+	rctx, done := context.WithCancel(ctx)
+	go scr.refresh(rctx, 100*time.Millisecond)
+
+	in, err := interruptible(rctx, stdin)
+	if err != nil {
+		s.Logger.Println("unable to interrupt the keyboard, input is read until the next key press", err)
+		in = os.Stdin
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		scr.keyboard(rctx, in)
+	}()
+
+	scr.cState.run(ctx, events, scr)
+
+	done()
+	<-stopped
+	scr.close()
+	errorsx.Log(errors.Wrap(term.Restore(stdin, state), "unable to restore terminal"))
+	log.SetOutput(original)
+
+	scr.summary(s)
+}
+
+func newScreen(s cState, out io.Writer, size func() (int, int)) *screen {
+	ev := &lines{max: maxEventLines}
+	scr := &screen{
+		m:     &sync.Mutex{},
+		out:   out,
+		size:  size,
+		nodes: make(map[string]*node, len(s.peers)),
+		events: &lineWriter{
+			lines: ev,
+		},
+		eventlog: ev,
+		logs:     &lines{max: maxLogLines},
+		status:   "waiting for deploy",
+	}
+
+	// events are rendered with the standard formatting.
+	s.Logger = log.New(scr.events, "", 0)
+	s.au = aurora.NewAurora(false)
+	scr.cState = s
+
+	for _, p := range s.peers {
+		scr.track(p)
+	}
+
+	return scr
+}
+
+// screen tracks the state of the deploy and renders it.
+type screen struct {
+	cState
+	m          *sync.Mutex
+	out        io.Writer
+	size       func() (width, height int)
+	nodes      map[string]*node
+	order      []string
+	found      int64
+	completed  int64
+	archive    *agent.Archive
+	deployer   string
+	restarting bool
+	finished   *agent.Message
+	selected   int
+	failedOnly bool
+	confirm    bool
+	status     string
+	transfer   *agent.Transfer
+	events     *lineWriter
+	eventlog   *lines
+	logs       *lines
+	logsource  string
+	dirty      bool
+}
+
+// Consume implements the consumer interface.
+func (t *screen) Consume(m *agent.Message) consumer {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.dirty = true
+
+	// transfers are displayed by the progress bar.
+	if m.Type == agent.Message_TransferEvent {
+		t.transfer = m.GetTransfer()
+		t.status = t.transfer.Description
+		return t
+	}
+
+	t.cState.print(m)
+
+	switch m.Type {
+	case agent.Message_PeersFoundEvent:
+		t.found = m.GetInt()
+	case agent.Message_PeersCompletedEvent:
+		t.completed = m.GetInt()
+	case agent.Message_DeployEvent:
+		n := t.track(m.Peer)
+		n.latest = m
+		switch m.GetDeploy().Stage {
+		case agent.Deploy_Deploying:
+			n.status = nodeDeploying
+		case agent.Deploy_Completed:
+			n.status = nodeDone
+		case agent.Deploy_Failed:
+			n.status = nodeFailed
+		}
+	case agent.Message_DeployCommandEvent:
+		dc := m.GetDeployCommand()
+		switch dc.Command {
+		case agent.DeployCommand_Begin:
+			t.begin(dc)
+		case agent.DeployCommand_Restart:
+			t.restarting = true
+			t.status = fmt.Sprintf("restarted by %s", dc.Initiator)
+		case agent.DeployCommand_Cancel, agent.DeployCommand_Failed:
+			// a restart emits a cancel, ignore it and wait for the deploy to begin again.
+			if t.restarting {
+				return t
+			}
+
+			t.finished = m
+			return nil
+		case agent.DeployCommand_Done:
+			t.finished = m
+			return nil
+		}
+	}
+
+	return t
+}
+
+func (t *screen) begin(dc *agent.DeployCommand) {
+	t.restarting = false
+	t.archive = dc.Archive
+	t.deployer = dc.Initiator
+	t.found, t.completed = 0, 0
+	t.status = "deploying"
+
+	for _, n := range t.nodes {
+		n.status = nodePending
+		n.latest = nil
+	}
+}
+
+func (t *screen) track(p *agent.Peer) *node {
+	if n, ok := t.nodes[p.Name]; ok {
+		return n
+	}
+
+	n := &node{peer: p}
+	t.nodes[p.Name] = n
+	t.order = append(t.order, p.Name)
+	sort.Slice(t.order, func(i, j int) bool {
+		return t.nodes[t.order[i]].label() < t.nodes[t.order[j]].label()
+	})
+
+	return n
+}
+
+// visible nodes based on the current filter.
+func (t *screen) visible() (nodes []*node) {
+	for _, name := range t.order {
+		n := t.nodes[name]
+		if t.failedOnly && n.status != nodeFailed {
+			continue
+		}
+
+		nodes = append(nodes, n)
+	}
+
+	return nodes
+}
+
+func (t *screen) failures() (failed []*node) {
+	for _, name := range t.order {
+		if n := t.nodes[name]; n.status == nodeFailed {
+			failed = append(failed, n)
+		}
+	}
+
+	return failed
+}
+
+func (t *screen) counts() (c [4]int) {
+	for _, n := range t.nodes {
+		c[n.status]++
+	}
+
+	return c
+}
+
+// enter the alternate screen buffer.
+func (t *screen) open() {
+	errorsx.Log(writeString(t.out, "\x1b[?1049h\x1b[?25l"))
+	t.render()
+}
+
+// leave the alternate screen buffer.
+func (t *screen) close() {
+	errorsx.Log(writeString(t.out, "\x1b[?25h\x1b[?1049l"))
+}
+
+// periodically render the screen when its state has changed.
+func (t *screen) refresh(ctx context.Context, d time.Duration) {
+	tick := time.NewTicker(d)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+			t.m.Lock()
+			if t.dirty || t.events.flush() {
+				t.dirty = false
+				t.m.Unlock()
+				t.render()
+				continue
+			}
+			t.m.Unlock()
+		}
+	}
+}
+
+func (t *screen) render() {
+	t.m.Lock()
+	view := t.view()
+	t.m.Unlock()
+
+	errorsx.Log(writeString(t.out, "\x1b[H"+strings.Join(view, "\x1b[K\r\n")+"\x1b[K\x1b[J"))
+}
+
+// view generates the lines of the screen.
+func (t *screen) view() (view []string) {
+	width, height := t.size()
+	width = max(width, 20)
+	height = max(height, 10)
+
+	counts := t.counts()
+	did := "unknown"
+	if t.archive != nil {
+		did = bw.RandomID(t.archive.DeploymentID).String()
+	}
+
+	view = append(view,
+		pterm.Bold.Sprint(truncate(fmt.Sprintf("deploy %s by %s - %s", did, t.deployer, t.status), width)),
+		t.progress(width),
+		truncate(fmt.Sprintf(
+			"%s pending %d %s deploying %d %s done %d %s failed %d",
+			symbol(nodePending), counts[nodePending],
+			symbol(nodeDeploying), counts[nodeDeploying],
+			symbol(nodeDone), counts[nodeDone],
+			symbol(nodeFailed), counts[nodeFailed],
+		), width),
+		"",
+	)
+
+	// reserve roughly half the screen for the node grid.
+	view = append(view, t.grid(width, max((height-8)/2, 1))...)
+	view = append(view, "")
+
+	footer := truncate("[↑/↓] select  [enter] logs  [esc] events  [f] failed only  [c] cancel deploy  [q] quit", width)
+	if t.confirm {
+		footer = pterm.FgRed.Sprint(truncate("cancel the deploy? [y/n]", width))
+	}
+
+	pane, title := t.eventlog, "events"
+	if t.logsource != "" {
+		pane, title = t.logs, "logs: "+t.logsource
+	}
+
+	remaining := max(height-len(view)-2, 1)
+	view = append(view, pterm.Bold.Sprint(truncate(title, width)))
+	for _, l := range pane.tail(remaining) {
+		view = append(view, truncate(l, width))
+	}
+
+	for len(view) < height-1 {
+		view = append(view, "")
+	}
+
+	return append(view, footer)
+}
+
+func (t *screen) progress(width int) string {
+	var (
+		ratio float64
+	)
+
+	// until the deploy begins display the progress of the archive transfer.
+	if t.archive == nil && t.transfer != nil {
+		return bar(width, transferRatio(t.transfer), transferLabel(t.transfer))
+	}
+
+	if t.found > 0 {
+		ratio = float64(t.completed) / float64(t.found)
+	}
+
+	return bar(width, ratio, fmt.Sprintf(" %d/%d peers", t.completed, t.found))
+}
+
+func bar(width int, ratio float64, label string) string {
+	ratio = min(max(ratio, 0), 1)
+	size := max(width-len(label)-2, 1)
+	filled := int(ratio * float64(size))
+
+	return "[" + pterm.FgGreen.Sprint(strings.Repeat("█", filled)) + pterm.FgGray.Sprint(strings.Repeat("░", size-filled)) + "]" + label
+}
+
+func (t *screen) grid(width, rows int) (view []string) {
+	nodes := t.visible()
+	if len(nodes) == 0 {
+		if t.failedOnly {
+			return []string{"no failed nodes"}
+		}
+
+		return []string{"no nodes have reported"}
+	}
+
+	t.selected = min(max(t.selected, 0), len(nodes)-1)
+
+	cell := 0
+	for _, n := range nodes {
+		cell = max(cell, len(n.label()))
+	}
+	cell += 3
+	columns := max(width/cell, 1)
+
+	total := (len(nodes) + columns - 1) / columns
+	offset := 0
+	if srow := t.selected / columns; srow >= rows {
+		offset = srow - rows + 1
+	}
+
+	for row := offset; row < total && row < offset+rows; row++ {
+		var line strings.Builder
+		for col := 0; col < columns; col++ {
+			idx := row*columns + col
+			if idx >= len(nodes) {
+				break
+			}
+
+			n := nodes[idx]
+			label := fmt.Sprintf("%-*s", cell-2, n.label())
+			if idx == t.selected {
+				label = pterm.NewStyle(pterm.BgGray, pterm.FgBlack).Sprint(label)
+			}
+
+			line.WriteString(symbol(n.status) + " " + label)
+		}
+
+		view = append(view, line.String())
+	}
+
+	if total > rows {
+		view = append(view, pterm.FgGray.Sprintf("... %d rows", total))
+	}
+
+	return view
+}
+
+func symbol(s nodeStatus) string {
+	switch s {
+	case nodeDeploying:
+		return pterm.FgYellow.Sprint("●")
+	case nodeDone:
+		return pterm.FgGreen.Sprint("●")
+	case nodeFailed:
+		return pterm.FgRed.Sprint("●")
+	default:
+		return pterm.FgGray.Sprint("○")
+	}
+}
+
+// interruptible reader of the terminal, closed once the context is done. reads
+// from stdin otherwise block until the next key press, long after the view closed.
+func interruptible(ctx context.Context, fd int) (_ io.Reader, err error) {
+	var (
+		dup int
+	)
+
+	if dup, err = syscall.Dup(fd); err != nil {
+		return nil, errors.Wrap(err, "unable to duplicate stdin")
+	}
+
+	// non blocking files are read through the runtime's poller, which allows closing
+	// the file to interrupt a pending read.
+	if err = syscall.SetNonblock(dup, true); err != nil {
+		return nil, errorsx.Compact(errors.Wrap(err, "unable to configure stdin"), syscall.Close(dup))
+	}
+
+	in := os.NewFile(uintptr(dup), "stdin")
+	go func() {
+		<-ctx.Done()
+		errorsx.Log(errors.Wrap(in.Close(), "unable to close stdin"))
+		// the duplicate shares the file status flags with stdin.
+		errorsx.Log(errors.Wrap(syscall.SetNonblock(fd, false), "unable to restore stdin"))
+	}()
+
+	return in, nil
+}
+
+// keyboard processes input from the terminal, which is in raw mode.
+func (t *screen) keyboard(ctx context.Context, in io.Reader) {
+	r := bufio.NewReader(in)
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		// arrow keys are sent as escape sequences, a lone escape closes the log pane.
+		if b == 0x1b {
+			if r.Buffered() >= 2 {
+				seq, _ := r.Peek(2)
+				if seq[0] == '[' {
+					_, _ = r.Discard(2)
+					t.key(ctx, rune(seq[1])|escape)
+					continue
+				}
+			}
+		}
+
+		t.key(ctx, rune(b))
+	}
+}
+
+// marks a key as being part of an escape sequence.
+const escape = 1 << 20
+
+func (t *screen) key(ctx context.Context, k rune) {
+	t.m.Lock()
+	defer func() {
+		t.dirty = true
+		t.m.Unlock()
+	}()
+
+	if t.confirm {
+		t.confirm = false
+		if k == 'y' || k == 'Y' {
+			t.status = "cancelling"
+			go t.cancel(ctx)
+		}
+		return
+	}
+
+	columns := 1
+	if nodes := t.visible(); len(nodes) > 0 {
+		width, _ := t.size()
+		cell := 0
+		for _, n := range nodes {
+			cell = max(cell, len(n.label()))
+		}
+		columns = max(width/(cell+3), 1)
+	}
+
+	switch k {
+	case 'q', 0x03: // ctrl+c
+		go interrupt()
+	case 'c':
+		t.confirm = true
+	case 'f':
+		t.failedOnly = !t.failedOnly
+		t.selected = 0
+	case 'k', 'A' | escape:
+		t.selected -= columns
+	case 'j', 'B' | escape:
+		t.selected += columns
+	case 'l', 'C' | escape:
+		t.selected++
+	case 'h', 'D' | escape:
+		t.selected--
+	case 0x1b:
+		t.logsource = ""
+	case '\r', '\n':
+		nodes := t.visible()
+		if len(nodes) == 0 {
+			return
+		}
+
+		n := nodes[min(max(t.selected, 0), len(nodes)-1)]
+		t.logsource = n.label()
+		t.logs.reset()
+		go t.fetchLogs(ctx, n)
+	}
+
+	t.selected = max(t.selected, 0)
+}
+
+// interrupt the process, mirroring ctrl+c outside of raw mode.
+func interrupt() {
+	p, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		log.Println("unable to interrupt", err)
+		return
+	}
+
+	errorsx.Log(errors.Wrap(p.Signal(os.Interrupt), "unable to interrupt"))
+}
+
+func (t *screen) cancel(ctx context.Context) {
+	var (
+		err  error
+		conn *grpc.ClientConn
+	)
+
+	cctx, done := context.WithTimeout(ctx, 20*time.Second)
+	defer done()
+
+	if conn, err = t.cached.DialContext(cctx); err != nil {
+		log.Println(errors.Wrap(err, "unable to connect to cancel the deploy"))
+		return
+	}
+
+	if err = agent.NewDeployConn(conn).Cancel(cctx, &agent.CancelRequest{Initiator: t.initiator}); err != nil {
+		log.Println(errors.Wrap(err, "unable to cancel the deploy"))
+	}
+}
+
+// fetchLogs retrieves the deploy logs of a node using Agent.Logs.
+func (t *screen) fetchLogs(ctx context.Context, n *node) {
+	var (
+		err  error
+		conn *grpc.ClientConn
+		did  []byte
+	)
+
+	t.m.Lock()
+	if did = n.latest.GetDeploy().GetArchive().GetDeploymentID(); len(did) == 0 {
+		did = t.archive.GetDeploymentID()
+	}
+	logs := t.logs
+	t.m.Unlock()
+
+	if len(did) == 0 {
+		logs.append("no deployment to retrieve logs for")
+		return
+	}
+
+	cctx, done := context.WithTimeout(ctx, 20*time.Second)
+	defer done()
+
+	d := dialers.NewDirect(agent.RPCAddress(n.peer), t.cached.Defaults()...)
+	if conn, err = d.DialContext(cctx); err != nil {
+		logs.append(errors.Wrap(err, "unable to connect to node").Error())
+		return
+	}
+	defer conn.Close()
+
+	src := agent.NewConn(conn).Logs(cctx, n.peer, did)
+	defer src.Close()
+
+	scanner := bufio.NewScanner(src)
+	for scanner.Scan() {
+		logs.append(scanner.Text())
+		t.m.Lock()
+		t.dirty = true
+		t.m.Unlock()
+	}
+
+	if err = scanner.Err(); err != nil {
+		logs.append(errors.Wrap(err, "log retrieval failed").Error())
+	}
+}
+
+// summary of the deploy once the view has closed.
+func (t *screen) summary(s cState) {
+	t.m.Lock()
+	counts := t.counts()
+	failed := t.failures()
+	finished := t.finished
+	t.m.Unlock()
+
+	s.Logger.Printf("deploy summary: %d done, %d failed, %d deploying, %d pending\n", counts[nodeDone], counts[nodeFailed], counts[nodeDeploying], counts[nodePending])
+
+	if finished != nil {
+		s.printDeployCommand(finished)
+	}
+
+	if len(failed) == 0 {
+		return
+	}
+
+	// only display each distinct failure once.
+	displayed := make(map[string]bool, len(failed))
+	for _, n := range failed {
+		if n.latest == nil {
+			continue
+		}
+
+		digest := md5.Sum([]byte(n.latest.GetDeploy().Error))
+		if key := hex.EncodeToString(digest[:]); !displayed[key] {
+			displayed[key] = true
+			s.FailureDisplay.Display(s, n.latest)
+		}
+	}
+
+	s.failed(errorsx.String("deploy failed"))
+}
+
+// lines is a bounded buffer of lines.
+type lines struct {
+	m       sync.Mutex
+	max     int
+	content []string
+}
+
+func (t *lines) append(l ...string) {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	t.content = append(t.content, l...)
+	if overflow := len(t.content) - t.max; overflow > 0 {
+		t.content = append(t.content[:0], t.content[overflow:]...)
+	}
+}
+
+func (t *lines) reset() {
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.content = t.content[:0]
+}
+
+func (t *lines) tail(n int) []string {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	if len(t.content) <= n {
+		return append([]string(nil), t.content...)
+	}
+
+	return append([]string(nil), t.content[len(t.content)-n:]...)
+}
+
+// lineWriter splits written content into lines.
+type lineWriter struct {
+	*lines
+	m       sync.Mutex
+	updated bool
+}
+
+func (t *lineWriter) Write(b []byte) (int, error) {
+	cleaned := strings.NewReplacer("\r", "", "\t", "    ").Replace(string(bytes.TrimRight(b, "\n")))
+	t.lines.append(strings.Split(cleaned, "\n")...)
+
+	t.m.Lock()
+	t.updated = true
+	t.m.Unlock()
+
+	return len(b), nil
+}
+
+// flush reports if content was written since the last flush.
+func (t *lineWriter) flush() bool {
+	t.m.Lock()
+	defer t.m.Unlock()
+	updated := t.updated
+	t.updated = false
+	return updated
+}
+
+// truncate the string to the display width, colors are removed from truncated strings.
+func truncate(s string, width int) string {
+	plain := pterm.RemoveColorFromString(s)
+	if runewidth.StringWidth(plain) <= width {
+		return s
+	}
+
+	return runewidth.Truncate(plain, width, "")
+}
+
+func writeString(w io.Writer, s string) error {
+	_, err := io.WriteString(w, s)
+	return errors.WithStack(err)
+}
@@ -0,0 +1,159 @@
+package ux
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/pterm/pterm"
+
+	"github.com/james-lawrence/bw/agent"
+)
+
+var _ = Describe("interactive", func() {
+	var (
+		node1 = agent.NewPeer("node1")
+		node2 = agent.NewPeer("node2")
+		node3 = agent.NewPeer("node3")
+		dc    = &agent.DeployCommand{Command: agent.DeployCommand_Begin, Initiator: "wookie", Archive: &agent.Archive{DeploymentID: []byte("deploy1")}, Options: &agent.DeployOptions{}}
+	)
+
+	newTestScreen := func(out *bytes.Buffer, peers ...*agent.Peer) *screen {
+		s := cState{
+			connection:     &agent.ConnectionEvent{},
+			FailureDisplay: FailureDisplayNoop{},
+			Logger:         log.New(out, "", 0),
+			peers:          peers,
+		}
+
+		return newScreen(s, out, func() (int, int) { return 80, 24 })
+	}
+
+	deploy := func(p *agent.Peer, stage agent.Deploy_Stage) *agent.Message {
+		return agent.DeployEvent(p, &agent.Deploy{Stage: stage, Archive: dc.Archive, Options: dc.Options})
+	}
+
+	It("should track the progress of each node", func() {
+		var out bytes.Buffer
+		scr := newTestScreen(&out, node1, node2, node3)
+
+		Expect(consume(scr,
+			agent.NewDeployCommand(node1, dc),
+			agent.PeersFoundEvent(node1, 3),
+			deploy(node1, agent.Deploy_Deploying),
+			deploy(node2, agent.Deploy_Completed),
+			deploy(node3, agent.Deploy_Failed),
+			agent.PeersCompletedEvent(node1, 2),
+		)).To(Equal(scr))
+
+		Expect(scr.counts()).To(Equal([4]int{0, 1, 1, 1}))
+		Expect(scr.found).To(Equal(int64(3)))
+		Expect(scr.completed).To(Equal(int64(2)))
+		Expect(scr.deployer).To(Equal("wookie"))
+
+		view := pterm.RemoveColorFromString(strings.Join(scr.view(), "\n"))
+		Expect(view).To(ContainSubstring("2/3 peers"))
+		Expect(view).To(ContainSubstring("deploying 1"))
+		Expect(view).To(ContainSubstring("failed 1"))
+		Expect(view).To(ContainSubstring("node2"))
+		Expect(scr.view()).To(HaveLen(24))
+	})
+
+	It("should display the archive transfer until the deploy begins", func() {
+		var out bytes.Buffer
+		scr := newTestScreen(&out, node1)
+
+		consume(scr, agent.TransferEvent(node1, "uploading archive", 512*1024, 1024*1024, time.Second))
+		view := pterm.RemoveColorFromString(strings.Join(scr.view(), "\n"))
+		Expect(view).To(ContainSubstring("uploading archive"))
+		Expect(view).To(ContainSubstring("50% 512.0 KiB/1.0 MiB 512.0 KiB/s"))
+
+		consume(scr, agent.NewDeployCommand(node1, dc))
+		view = pterm.RemoveColorFromString(strings.Join(scr.view(), "\n"))
+		Expect(view).To(ContainSubstring("0/0 peers"))
+	})
+
+	It("should filter failed nodes", func() {
+		var out bytes.Buffer
+		scr := newTestScreen(&out, node1, node2, node3)
+
+		consume(scr,
+			agent.NewDeployCommand(node1, dc),
+			deploy(node1, agent.Deploy_Completed),
+			deploy(node3, agent.Deploy_Failed),
+		)
+
+		Expect(scr.visible()).To(HaveLen(3))
+		scr.failedOnly = true
+		Expect(scr.visible()).To(HaveLen(1))
+		Expect(scr.visible()[0].peer.Name).To(Equal("node3"))
+	})
+
+	It("should ignore cancellations during a restart", func() {
+		var out bytes.Buffer
+		scr := newTestScreen(&out, node1)
+
+		restart := &agent.DeployCommand{Command: agent.DeployCommand_Restart, Archive: dc.Archive, Options: dc.Options}
+		cancel := &agent.DeployCommand{Command: agent.DeployCommand_Cancel, Archive: dc.Archive, Options: dc.Options}
+		done := &agent.DeployCommand{Command: agent.DeployCommand_Done, Archive: dc.Archive, Options: dc.Options}
+
+		Expect(consume(scr,
+			agent.NewDeployCommand(node1, dc),
+			deploy(node1, agent.Deploy_Failed),
+			agent.NewDeployCommand(node1, restart),
+			agent.NewDeployCommand(node1, cancel),
+			agent.NewDeployCommand(node1, dc),
+		)).To(Equal(scr))
+		Expect(scr.counts()).To(Equal([4]int{1, 0, 0, 0}))
+
+		Expect(consume(scr,
+			deploy(node1, agent.Deploy_Completed),
+			agent.NewDeployCommand(node1, done),
+		)).To(BeNil())
+		Expect(scr.finished.GetDeployCommand().Command).To(Equal(agent.DeployCommand_Done))
+	})
+
+	It("should display events in the pane", func() {
+		var out bytes.Buffer
+		scr := newTestScreen(&out, node1)
+
+		consume(scr, agent.LogEvent(node1, "hello world"))
+		Expect(strings.Join(scr.view(), "\n")).To(ContainSubstring("hello world"))
+	})
+
+	It("should stop reading the keyboard once the view closes", func() {
+		var out bytes.Buffer
+		scr := newTestScreen(&out, node1)
+
+		r, w, err := os.Pipe()
+		Expect(err).To(Succeed())
+		defer r.Close()
+		defer w.Close()
+
+		ctx, done := context.WithCancel(context.Background())
+		in, err := interruptible(ctx, int(r.Fd()))
+		Expect(err).To(Succeed())
+
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			scr.keyboard(ctx, in)
+		}()
+
+		Consistently(stopped, 50*time.Millisecond).ShouldNot(BeClosed())
+		done()
+		Eventually(stopped).Should(BeClosed())
+	})
+
+	It("should truncate colored strings by their display width", func() {
+		colored := pterm.FgRed.Sprint("●") + " failed"
+		Expect(truncate(colored, 8)).To(Equal(colored))
+		Expect(truncate(colored, 4)).To(Equal("● fa"))
+		Expect(truncate("日本語", 4)).To(Equal("日本"))
+	})
+})
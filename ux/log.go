@@ -39,6 +39,27 @@ func OptionDebug(b bool) Option {
 	}
 }
 
+// OptionInteractive display the deploy using a full screen view when attached to a terminal.
+func OptionInteractive(b bool) Option {
+	return func(cs *cState) {
+		cs.interactive = b
+	}
+}
+
+// OptionPeers the peers targeted by the deploy, displayed as pending until they report progress.
+func OptionPeers(peers ...*agent.Peer) Option {
+	return func(cs *cState) {
+		cs.peers = peers
+	}
+}
+
+// OptionInitiator the name recorded when the deploy is cancelled from the interactive view.
+func OptionInitiator(s string) Option {
+	return func(cs *cState) {
+		cs.initiator = s
+	}
+}
+
 // Deploy monitor a deploy.
 func Deploy(ctx context.Context, failed context.CancelCauseFunc, cached *dialers.Cached, events chan *agent.Message, options ...Option) {
 	var (
@@ -56,6 +77,12 @@ func Deploy(ctx context.Context, failed context.CancelCauseFunc, cached *dialers
 	)
 
 	defer failed(nil)
+
+	if s.interactive {
+		interactive(ctx, s.cState, events)
+		return
+	}
+
 	s.run(ctx, events, s)
 }
 
@@ -107,7 +134,7 @@ func (t cState) run(ctx context.Context, events chan *agent.Message, s consumer)
 			}
 
 			switch m.Type {
-			case agent.Message_LogEvent:
+			case agent.Message_LogEvent, agent.Message_TransferEvent:
 			default:
 				last = m
 			}
@@ -154,6 +181,9 @@ type cState struct {
 	heartbeat      time.Duration
 	debug          bool
 	failed         context.CancelCauseFunc
+	interactive    bool
+	peers          []*agent.Peer
+	initiator      string
 }
 
 func (t cState) merge(options ...Option) cState {
@@ -196,6 +226,10 @@ func (t cState) print(m *agent.Message) {
 			evt.Deploy.Stage,
 			bw.RandomID(evt.Deploy.Archive.DeploymentID),
 		)
+	case *agent.Message_Staged:
+		t.Logger.Println(
+			t.au.Green(fmt.Sprintf("%s - INFO - archive staged on %d node(s) - %s", messagePrefix(m), len(evt.Staged.Peers), bw.RandomID(evt.Staged.GetArchive().GetDeploymentID()))),
+		)
 	case *agent.Message_Membership:
 		t.Logger.Println(
 			t.au.Yellow(
@@ -220,6 +254,12 @@ func (t cState) print(m *agent.Message) {
 		)
 
 		t.connection.State = evt.Connection.State
+	case *agent.Message_Transfer:
+		t.Logger.Printf(
+			"%s - INFO - %s\n",
+			messagePrefix(m),
+			transferSummary(evt.Transfer),
+		)
 	case *agent.Message_Heartbeat:
 		if t.debug {
 			t.Logger.Printf("%s - %s\n", messagePrefix(m), m.Type)
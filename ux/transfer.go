@@ -0,0 +1,54 @@
+package ux
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/james-lawrence/bw/agent"
+)
+
+// bytesize formats the number of bytes using binary units.
+func bytesize(n float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB"}
+	idx := 0
+	for ; n >= 1024 && idx < len(units)-1; idx++ {
+		n /= 1024
+	}
+
+	return fmt.Sprintf("%.1f %s", n, units[idx])
+}
+
+func transferRatio(t *agent.Transfer) float64 {
+	if t.Total == 0 {
+		return 0
+	}
+
+	return min(float64(t.Completed)/float64(t.Total), 1)
+}
+
+// transferLabel describes the progress and throughput of the transfer.
+func transferLabel(t *agent.Transfer) string {
+	var (
+		throughput float64
+	)
+
+	if elapsed := time.Duration(t.Elapsed); elapsed > 0 {
+		throughput = float64(t.Completed) / elapsed.Seconds()
+	}
+
+	return fmt.Sprintf(
+		" %3.0f%% %s/%s %s/s",
+		100*transferRatio(t),
+		bytesize(float64(t.Completed)),
+		bytesize(float64(t.Total)),
+		bytesize(throughput),
+	)
+}
+
+// transferSummary single line summary of the transfer for the log output.
+func transferSummary(t *agent.Transfer) string {
+	const width = 20
+	filled := int(transferRatio(t) * width)
+	return fmt.Sprintf("%s [%s%s]%s", t.Description, strings.Repeat("#", filled), strings.Repeat(".", width-filled), transferLabel(t))
+}
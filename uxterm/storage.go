@@ -0,0 +1,47 @@
+package uxterm
+
+import (
+	"strconv"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/pterm/pterm"
+)
+
+// PrintStorageGC prints the archives retained and removed by the storage garbage collection.
+func PrintStorageGC(s *agent.StorageGCResponse) (err error) {
+	var (
+		archives = pterm.TableData{
+			{"id", "bytes", "status"},
+		}
+		removed = "removed"
+	)
+
+	if s.DryRun {
+		removed = "would be removed"
+	}
+
+	if s.Peer != nil {
+		pterm.Printfln("Node    : %s", PeerString(s.Peer))
+	}
+
+	if s.Error != "" {
+		pterm.Printfln("Error   : %s", s.Error)
+		pterm.Println()
+		return nil
+	}
+
+	for _, a := range s.Retained {
+		archives = append(archives, []string{a.Id, strconv.FormatUint(a.Bytes, 10), "retained (" + a.Reason + ")"})
+	}
+
+	for _, a := range s.Removed {
+		archives = append(archives, []string{a.Id, strconv.FormatUint(a.Bytes, 10), removed})
+	}
+
+	if err = pterm.DefaultTable.WithHasHeader().WithData(archives).Render(); err != nil {
+		return err
+	}
+	pterm.Println()
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package uxterm
+
+import (
+	"strings"
+	"time"
+
+	"github.com/james-lawrence/bw/agent"
+	"github.com/pterm/pterm"
+)
+
+// TLSExpiry classifies the expiration of a certificate, certificates
+// expiring within the threshold are flagged.
+func TLSExpiry(notAfter int64, now time.Time, threshold time.Duration) (status string, flagged bool) {
+	expires := time.Unix(notAfter, 0)
+	switch {
+	case !expires.After(now):
+		return "expired", true
+	case expires.Before(now.Add(threshold)):
+		return "expiring", true
+	default:
+		return "ok", false
+	}
+}
+
+// PrintTLSStatus prints the tls credentials, returns true when the serving certificate chain is
+// unavailable or expires within the threshold.
+func PrintTLSStatus(s *agent.TLSStatusResponse, now time.Time, threshold time.Duration) (flagged bool, err error) {
+	var certificates = pterm.TableData{
+		{"kind", "subject", "issuer", "sans", "expires", "fingerprint", "status"},
+	}
+
+	row := func(kind string, c *agent.TLSCertificate, status string) []string {
+		return []string{
+			kind,
+			c.Subject,
+			c.Issuer,
+			strings.Join(c.Sans, ","),
+			time.Unix(c.NotAfter, 0).UTC().String(),
+			c.Fingerprint[:min(len(c.Fingerprint), 16)],
+			status,
+		}
+	}
+
+	if s.Peer != nil {
+		pterm.Printfln("Node    : %s", PeerString(s.Peer))
+	}
+	pterm.Printfln("Mode    : %s", s.Mode)
+	pterm.Printfln("Path    : %s", s.Path)
+
+	if s.Error != "" {
+		pterm.Printfln("Error   : %s", s.Error)
+		pterm.Println()
+		return true, nil
+	}
+
+	refresh := time.Unix(s.Refresh, 0).UTC()
+	if refresh.After(now) {
+		pterm.Printfln("Refresh : %s", refresh)
+	} else {
+		pterm.Printfln("Refresh : %s (overdue)", refresh)
+	}
+
+	for _, c := range s.Chain {
+		status, expiring := TLSExpiry(c.NotAfter, now, threshold)
+		flagged = flagged || expiring
+		certificates = append(certificates, row("chain", c, status))
+	}
+
+	for _, c := range s.Authorities {
+		status, _ := TLSExpiry(c.NotAfter, now, threshold)
+		certificates = append(certificates, row("authority", c, status))
+	}
+
+	if err = pterm.DefaultTable.WithHasHeader().WithData(certificates).Render(); err != nil {
+		return flagged, err
+	}
+	pterm.Println()
+
+	return flagged, nil
+}